@@ -9,9 +9,11 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"io"
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -1052,3 +1054,68 @@ func TestIssue12449(t *testing.T) {
 		t.Errorf("Error reading the archive: %v", err)
 	}
 }
+
+func TestFS(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, name := range []string{"a/b/c.txt", "a/d.txt", "e/", "f.txt", "/g.txt", "../h.txt"} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(name, "/") {
+			io.WriteString(fw, "data "+name)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	z, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var walked []string
+	err = fs.WalkDir(z, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			path += "/"
+		}
+		walked = append(walked, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"./", "a/", "a/b/", "a/b/c.txt", "a/d.txt", "e/", "f.txt", "g.txt", "h.txt"}
+	if !reflect.DeepEqual(walked, want) {
+		t.Errorf("WalkDir = %q, want %q", walked, want)
+	}
+
+	data, err := fs.ReadFile(z, "a/b/c.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "data a/b/c.txt"; got != want {
+		t.Errorf("ReadFile = %q, want %q", got, want)
+	}
+	info, err := fs.Stat(z, "a/d.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name() != "d.txt" || info.IsDir() || info.Size() != int64(len("data a/d.txt")) {
+		t.Errorf("Stat(a/d.txt) = %q, %v, %d", info.Name(), info.IsDir(), info.Size())
+	}
+	for _, name := range []string{"missing", "a/b/c", "a/b/c.txt/x"} {
+		if _, err := z.Open(name); !os.IsNotExist(err) {
+			t.Errorf("Open(%q) = %v, want not-exist error", name, err)
+		}
+	}
+	for _, name := range []string{"/a", "a/../a", "a/"} {
+		if _, err := z.Open(name); err == nil {
+			t.Errorf("Open(%q) succeeded, want error", name)
+		}
+	}
+}
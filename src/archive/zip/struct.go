@@ -157,8 +157,11 @@ func (fi headerFileInfo) Size() int64 {
 func (fi headerFileInfo) IsDir() bool        { return fi.Mode().IsDir() }
 func (fi headerFileInfo) ModTime() time.Time { return fi.fh.ModTime() }
 func (fi headerFileInfo) Mode() os.FileMode  { return fi.fh.Mode() }
+func (fi headerFileInfo) Type() os.FileMode  { return fi.Mode().Type() }
 func (fi headerFileInfo) Sys() interface{}   { return fi.fh }
 
+func (fi headerFileInfo) Info() (os.FileInfo, error) { return fi, nil }
+
 // FileInfoHeader creates a partially-populated FileHeader from an
 // os.FileInfo.
 // Because os.FileInfo's Name method returns only the base name of
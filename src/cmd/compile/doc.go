@@ -58,6 +58,11 @@ Flags:
 		Allow references to Go symbols in shared libraries (experimental).
 	-e
 		Remove the limit on the number of errors reported (default limit is 10).
+	-embedcfg file
+		Read go:embed configuration from file.
+		This is required if any //go:embed directives are used.
+		The file is a JSON file mapping patterns to lists of filenames
+		and filenames to full path names.
 	-goversion string
 		Specify required go tool version of the runtime.
 		Exits when the runtime go version does not match goversion.
@@ -222,5 +227,12 @@ The //go:linkname directive instructs the compiler to use ``importpath.name'' as
 object file symbol name for the variable or function declared as ``localname'' in the
 source code. Because this directive can subvert the type system and package
 modularity, it is only enabled in files that have imported "unsafe".
+
+	//go:embed pattern...
+
+The //go:embed directive instructs the compiler to initialize the package-level
+variable declared on the following line with the contents of the files matching
+the patterns, as resolved by the -embedcfg file. It is only enabled in files that
+have imported "embed". See the embed package documentation for details.
 */
 package main
@@ -156,6 +156,15 @@ func widstruct(errtype *types.Type, t *types.Type, o int64, flag int) int64 {
 	return o
 }
 
+// isAtomicAlign64 reports whether t is the type sync/atomic.align64.
+func isAtomicAlign64(t *types.Type) bool {
+	if t.Sym == nil || t.Sym.Name != "align64" {
+		return false
+	}
+	pkg := t.Sym.Pkg
+	return pkg.Path == "sync/atomic" || pkg == localpkg && myimportpath == "sync/atomic"
+}
+
 // dowidth calculates and stores the size and alignment for t.
 // If sizeCalculationDisabled is set, and the size/alignment
 // have not already been calculated, it calls Fatal.
@@ -338,6 +347,12 @@ func dowidth(t *types.Type) {
 			Fatalf("dowidth fn struct %v", t)
 		}
 		w = widstruct(t, t, 0, 1)
+		if isAtomicAlign64(t) {
+			// sync/atomic.align64 is an empty struct recognized
+			// as a signal that the struct containing it must be
+			// 64-bit aligned, even on 32-bit systems.
+			t.Align = 8
+		}
 
 	// make fake type to check later to
 	// trigger function argument computation.
@@ -0,0 +1,379 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gc
+
+import (
+	"cmd/compile/internal/syntax"
+	"cmd/compile/internal/types"
+	"cmd/internal/obj"
+	"cmd/internal/src"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// embedCfg is the configuration read from the -embedcfg file.
+// Patterns maps each //go:embed pattern to the list of files it matches,
+// and Files maps each of those files to its location on disk.
+var embedCfg struct {
+	Patterns map[string][]string
+	Files    map[string]string
+}
+
+func readEmbedCfg(file string) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		log.Fatalf("-embedcfg: %v", err)
+	}
+	if err := json.Unmarshal(data, &embedCfg); err != nil {
+		log.Fatalf("%s: %v", file, err)
+	}
+	if embedCfg.Patterns == nil {
+		log.Fatalf("%s: invalid embedcfg: missing Patterns", file)
+	}
+	if embedCfg.Files == nil {
+		log.Fatalf("%s: invalid embedcfg: missing Files", file)
+	}
+}
+
+// pragmaEmbed records a //go:embed directive.
+type pragmaEmbed struct {
+	pos      syntax.Pos
+	patterns []string
+}
+
+// An embedDirective is a //go:embed directive attached to a variable.
+type embedDirective struct {
+	pos      src.XPos
+	patterns []string
+}
+
+// An embedVar is a package-level variable initialized by //go:embed directives.
+type embedVar struct {
+	n    *Node
+	list []embedDirective
+}
+
+// embedlist is the list of variables initialized by //go:embed directives
+// in the package being compiled.
+var embedlist []*embedVar
+
+// parseGoEmbed parses the text following "//go:embed" to extract the glob patterns.
+// It accepts unquoted space-separated patterns as well as double-quoted and back-quoted Go strings.
+// go/build/read.go also processes these strings and contains similar logic.
+func parseGoEmbed(args string) ([]string, error) {
+	var list []string
+	for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+		var path string
+	Switch:
+		switch args[0] {
+		default:
+			i := len(args)
+			for j, c := range args {
+				if unicode.IsSpace(c) {
+					i = j
+					break
+				}
+			}
+			path = args[:i]
+			args = args[i:]
+
+		case '`':
+			i := strings.Index(args[1:], "`")
+			if i < 0 {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+			}
+			path = args[1 : 1+i]
+			args = args[1+i+1:]
+
+		case '"':
+			i := 1
+			for ; i < len(args); i++ {
+				if args[i] == '\\' {
+					i++
+					continue
+				}
+				if args[i] == '"' {
+					q, err := strconv.Unquote(args[:i+1])
+					if err != nil {
+						return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args[:i+1])
+					}
+					path = q
+					args = args[i+1:]
+					break Switch
+				}
+			}
+			if i >= len(args) {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+			}
+		}
+
+		if args != "" {
+			r, _ := utf8.DecodeRuneInString(args)
+			if !unicode.IsSpace(r) {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+			}
+		}
+		list = append(list, path)
+	}
+	return list, nil
+}
+
+// posBefore reports whether x appears before y in the same file.
+func posBefore(x, y syntax.Pos) bool {
+	return x.Line() < y.Line() || x.Line() == y.Line() && x.Col() < y.Col()
+}
+
+// takeEmbeds removes and returns the pending //go:embed directives
+// appearing before pos.
+func (p *noder) takeEmbeds(pos syntax.Pos) []pragmaEmbed {
+	i := 0
+	for i < len(p.embeds) && posBefore(p.embeds[i].pos, pos) {
+		i++
+	}
+	list := p.embeds[:i:i]
+	p.embeds = p.embeds[i:]
+	return list
+}
+
+// checkUnusedEmbeds reports an error for each pending //go:embed
+// directive appearing before pos. A directive must immediately
+// precede the variable declaration it applies to, so any such
+// directive has been misplaced.
+func (p *noder) checkUnusedEmbeds(pos syntax.Pos) {
+	for _, e := range p.takeEmbeds(pos) {
+		p.yyerrorpos(e.pos, "misplaced go:embed directive")
+	}
+}
+
+// varEmbed records the //go:embed directives embeds for the variable
+// declared by names, typ, and exprs, reporting an error if the
+// directives cannot apply to it.
+func (p *noder) varEmbed(names []*Node, typ *Node, exprs []*Node, embeds []pragmaEmbed) {
+	haveEmbed := false
+	for _, decl := range p.file.DeclList {
+		imp, ok := decl.(*syntax.ImportDecl)
+		if !ok {
+			// imports always come first
+			break
+		}
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if path == "embed" {
+			haveEmbed = true
+			break
+		}
+	}
+
+	pos := embeds[0].pos
+	if !haveEmbed {
+		p.yyerrorpos(pos, "invalid go:embed: missing import \"embed\"")
+		return
+	}
+	if embedCfg.Patterns == nil {
+		p.yyerrorpos(pos, "invalid go:embed: build system did not supply embed configuration")
+		return
+	}
+	if len(names) > 1 {
+		p.yyerrorpos(pos, "go:embed cannot apply to multiple vars")
+		return
+	}
+	if len(exprs) > 0 {
+		p.yyerrorpos(pos, "go:embed cannot apply to var with initializer")
+		return
+	}
+	if typ == nil {
+		// Should not happen, since len(exprs) == 0 now.
+		p.yyerrorpos(pos, "go:embed cannot apply to var without type")
+		return
+	}
+	if dclcontext != PEXTERN {
+		p.yyerrorpos(pos, "go:embed cannot apply to var inside func")
+		return
+	}
+
+	v := &embedVar{n: names[0]}
+	for _, e := range embeds {
+		v.list = append(v.list, embedDirective{p.makeXPos(e.pos), e.patterns})
+	}
+	embedlist = append(embedlist, v)
+}
+
+const (
+	embedUnknown = iota
+	embedBytes
+	embedString
+	embedFiles
+)
+
+func embedKind(typ *types.Type) int {
+	if typ.Sym != nil && typ.Sym.Name == "FS" && typ.Sym.Pkg.Path == "embed" {
+		return embedFiles
+	}
+	if typ.IsString() {
+		return embedString
+	}
+	if typ.IsSlice() && typ.Elem().Etype == TUINT8 {
+		return embedBytes
+	}
+	return embedUnknown
+}
+
+// embedFileNameSplit splits name into its directory and final element,
+// ignoring any trailing slash marking a directory entry.
+func embedFileNameSplit(name string) (dir, elem string, isDir bool) {
+	if name[len(name)-1] == '/' {
+		isDir = true
+		name = name[:len(name)-1]
+	}
+	i := len(name) - 1
+	for i >= 0 && name[i] != '/' {
+		i--
+	}
+	if i < 0 {
+		return ".", name, isDir
+	}
+	return name[:i], name[i+1:], isDir
+}
+
+// embedFileLess implements the sort order for a list of embedded files.
+// See the comment inside ../../../../embed/embed.go's FS struct for rationale.
+func embedFileLess(x, y string) bool {
+	xdir, xelem, _ := embedFileNameSplit(x)
+	ydir, yelem, _ := embedFileNameSplit(y)
+	return xdir < ydir || xdir == ydir && xelem < yelem
+}
+
+// embedFileList returns the sorted list of files to embed in v.
+// If there is a problem, embedFileList reports an error and returns ok == false.
+func embedFileList(v *embedVar) (list []string, ok bool) {
+	kind := embedKind(v.n.Type)
+	if kind == embedUnknown {
+		yyerrorl(v.n.Pos, "go:embed cannot apply to var of type %v", v.n.Type)
+		return nil, false
+	}
+
+	// Build list of files to store.
+	ok = true
+	have := make(map[string]bool)
+	for _, e := range v.list {
+		for _, pattern := range e.patterns {
+			files, found := embedCfg.Patterns[pattern]
+			if !found {
+				yyerrorl(e.pos, "invalid go:embed: build system did not map pattern: %s", pattern)
+				ok = false
+			}
+			for _, file := range files {
+				if embedCfg.Files[file] == "" {
+					yyerrorl(e.pos, "invalid go:embed: build system did not map file: %s", file)
+					ok = false
+					continue
+				}
+				if !have[file] {
+					have[file] = true
+					list = append(list, file)
+				}
+				if kind == embedFiles {
+					for dir := path.Dir(file); dir != "." && !have[dir]; dir = path.Dir(dir) {
+						have[dir] = true
+						list = append(list, dir+"/")
+					}
+				}
+			}
+		}
+	}
+	obj.SortSlice(list, func(i, j int) bool {
+		return embedFileLess(list[i], list[j])
+	})
+
+	if kind == embedString || kind == embedBytes {
+		switch {
+		case len(list) == 0 && ok:
+			yyerrorl(v.n.Pos, "invalid go:embed: no files for type %v", v.n.Type)
+			ok = false
+		case len(list) > 1:
+			yyerrorl(v.n.Pos, "invalid go:embed: multiple files for type %v", v.n.Type)
+			ok = false
+		}
+	}
+
+	return list, ok
+}
+
+// readEmbedFile returns the content of the embedded file name,
+// as listed in the embed configuration.
+func readEmbedFile(pos src.XPos, name string) ([]byte, bool) {
+	data, err := ioutil.ReadFile(embedCfg.Files[name])
+	if err != nil {
+		yyerrorl(pos, "embed %s: %v", name, err)
+		return nil, false
+	}
+	return data, true
+}
+
+// initEmbed emits the init data for a //go:embed variable,
+// which is either a string, a []byte, or an embed.FS.
+func initEmbed(v *embedVar) {
+	files, ok := embedFileList(v)
+	if !ok {
+		return
+	}
+	sym := v.n.Sym.Linksym()
+	switch kind := embedKind(v.n.Type); kind {
+	case embedString, embedBytes:
+		data, ok := readEmbedFile(v.n.Pos, files[0])
+		if !ok {
+			return
+		}
+		if kind == embedBytes {
+			slicebytes(v.n, string(data), len(data))
+			return
+		}
+		off := dsymptr(sym, 0, stringsym(v.n.Pos, string(data)), 0) // data string
+		duintptr(sym, off, uint64(len(data)))                       // len
+
+	case embedFiles:
+		slicedata := Ctxt.Lookup(`"".` + v.n.Sym.Name + `.files`)
+		off := 0
+		// []files pointed at by Files
+		off = dsymptr(slicedata, off, slicedata, 3*Widthptr) // []file, pointing just past slice
+		off = duintptr(slicedata, off, uint64(len(files)))
+		off = duintptr(slicedata, off, uint64(len(files)))
+
+		// embed/embed.go type file is:
+		//	name string
+		//	data string
+		//	hash [16]byte
+		// Emit one of these per file in the set.
+		const hashSize = 16
+		for _, file := range files {
+			off = dsymptr(slicedata, off, stringsym(v.n.Pos, file), 0) // file string
+			off = duintptr(slicedata, off, uint64(len(file)))
+			if strings.HasSuffix(file, "/") {
+				// entry for directory - no data
+				off = duintptr(slicedata, off, 0)
+				off = duintptr(slicedata, off, 0)
+				off += hashSize
+			} else {
+				data, ok := readEmbedFile(v.n.Pos, file)
+				if !ok {
+					return
+				}
+				hash := sha256.Sum256(data)
+				off = dsymptr(slicedata, off, stringsym(v.n.Pos, string(data)), 0) // data string
+				off = duintptr(slicedata, off, uint64(len(data)))
+				off = int(slicedata.WriteBytes(Ctxt, int64(off), hash[:hashSize]))
+			}
+		}
+		ggloblsym(slicedata, int32(off), obj.RODATA|obj.LOCAL)
+		dsymptr(sym, 0, slicedata, 0)
+	}
+}
@@ -817,6 +817,20 @@ func (e *EscState) esc(n *Node, parent *Node) {
 		e.escassignSinkWhy(n, n, escapeMsg) // TODO category: tooLarge
 	}
 
+	// Stack frames are only register-aligned, so variables that need
+	// more alignment than that (for example, those containing a
+	// sync/atomic.Int64 on 32-bit systems) are allocated on the heap.
+	if n.Esc != EscHeap && n.Type != nil &&
+		(n.Op == ONAME && n.Class() == PAUTO && n.Type.Align > uint8(Widthreg) ||
+			(n.Op == ONEW || n.Op == OPTRLIT) && n.Type.Elem().Align > uint8(Widthreg)) {
+		if Debug['m'] > 2 {
+			Warnl(n.Pos, "%v is too aligned for stack", n)
+		}
+		n.Esc = EscHeap
+		addrescapes(n)
+		e.escassignSinkWhy(n, n, "too aligned for stack")
+	}
+
 	e.esc(n.Left, n)
 
 	if n.Op == ORANGE {
@@ -133,6 +133,7 @@ type noder struct {
 	file       *syntax.File
 	linknames  []linkname
 	pragcgobuf [][]string
+	embeds     []pragmaEmbed
 	err        chan syntax.Error
 	scope      ScopeID
 
@@ -154,6 +155,7 @@ func (p *noder) funcBody(fn *Node, block *syntax.BlockStmt) {
 			body = []*Node{nod(OEMPTY, nil, nil)}
 		}
 		fn.Nbody.Set(body)
+		p.checkUnusedEmbeds(block.Rbrace)
 
 		lineno = p.makeXPos(block.Rbrace)
 		fn.Func.Endlineno = lineno
@@ -242,6 +244,9 @@ func (p *noder) node() {
 	mkpackage(p.file.PkgName.Value)
 
 	xtop = append(xtop, p.decls(p.file.DeclList)...)
+	for _, e := range p.embeds {
+		p.yyerrorpos(e.pos, "misplaced go:embed directive")
+	}
 
 	for _, n := range p.linknames {
 		if imported_unsafe {
@@ -273,6 +278,9 @@ func (p *noder) decls(decls []syntax.Decl) (l []*Node) {
 
 	for _, decl := range decls {
 		p.setlineno(decl)
+		if _, ok := decl.(*syntax.VarDecl); !ok {
+			p.checkUnusedEmbeds(decl.Pos())
+		}
 		switch decl := decl.(type) {
 		case *syntax.ImportDecl:
 			p.importDecl(decl)
@@ -340,6 +348,10 @@ func (p *noder) importDecl(imp *syntax.ImportDecl) {
 }
 
 func (p *noder) varDecl(decl *syntax.VarDecl) []*Node {
+	// Use the position of the first name rather than decl.Pos,
+	// which is the position of the var keyword for grouped declarations.
+	embeds := p.takeEmbeds(decl.NameList[0].Pos())
+
 	names := p.declNames(decl.NameList)
 	typ := p.typeExprOrNil(decl.Type)
 
@@ -348,6 +360,10 @@ func (p *noder) varDecl(decl *syntax.VarDecl) []*Node {
 		exprs = p.exprList(decl.Values)
 	}
 
+	if len(embeds) > 0 {
+		p.varEmbed(names, typ, exprs, embeds)
+	}
+
 	p.setlineno(decl)
 	return variter(names, typ, exprs)
 }
@@ -1450,6 +1466,18 @@ func (p *noder) pragma(pos syntax.Pos, text string) syntax.Pragma {
 		}
 		p.linknames = append(p.linknames, linkname{pos, f[1], f[2]})
 
+	case text == "go:embed", strings.HasPrefix(text, "go:embed "):
+		args, err := parseGoEmbed(text[len("go:embed"):])
+		if err != nil {
+			p.error(syntax.Error{Pos: pos, Msg: err.Error()})
+			break
+		}
+		if len(args) == 0 {
+			p.error(syntax.Error{Pos: pos, Msg: "usage: //go:embed pattern..."})
+			break
+		}
+		p.embeds = append(p.embeds, pragmaEmbed{pos, args})
+
 	case strings.HasPrefix(text, "go:cgo_import_dynamic "):
 		// This is permitted for general use because Solaris
 		// code relies on it in golang.org/x/sys/unix and others.
@@ -331,11 +331,35 @@ func orderBlock(n *Nodes, free map[string][]*Node) {
 	var order Order
 	order.free = free
 	mark := order.markTemp()
+	order.edge()
 	order.stmtList(*n)
 	order.cleanTemp(mark)
 	n.Set(order.out)
 }
 
+// edge inserts coverage instrumentation for libfuzzer
+// at the start of the statements being ordered.
+func (o *Order) edge() {
+	if Debug_libfuzzer == 0 {
+		return
+	}
+	o.out = append(o.out, libfuzzerEdge())
+}
+
+// libfuzzerEdge returns a statement incrementing a new 8-bit
+// coverage counter. The linker collects the counters into a
+// contiguous block of memory that the fuzzing engine reads
+// to detect when an input reaches new code.
+func libfuzzerEdge() *Node {
+	counter := staticname(types.Types[TUINT8])
+	counter.Name.SetLibfuzzerExtraCounter(true)
+
+	// counter += 1
+	incr := nod(OASOP, counter, nodintconst(1))
+	incr.SetSubOp(OADD)
+	return typecheck(incr, ctxStmt)
+}
+
 // exprInPlace orders the side effects in *np and
 // leaves them as the init list of the final *np.
 // The result of exprInPlace MUST be assigned back to n, e.g.
@@ -1137,6 +1161,9 @@ func (o *Order) expr(n, lhs *Node) *Node {
 		// Leave them on the stack so that they can be killed in the outer
 		// context in case the short circuit is taken.
 		n.Right = addinit(n.Right, o.cleanTempNoPop(mark))
+		if Debug_libfuzzer != 0 {
+			n.Right = addinit(n.Right, []*Node{libfuzzerEdge()})
+		}
 		n.Right = o.exprInPlace(n.Right)
 
 	case OCALLFUNC,
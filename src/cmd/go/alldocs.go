@@ -47,6 +47,7 @@
 // 	importpath  import path syntax
 // 	modules     modules, module versions, and more
 // 	module-get  module-aware go get
+// 	module-private module configuration for non-public modules
 // 	packages    package lists and patterns
 // 	testflag    testing flags
 // 	testfunc    testing functions
@@ -672,6 +673,14 @@
 //         TestGoFiles     []string // _test.go files in package
 //         XTestGoFiles    []string // _test.go files outside package
 //
+//         // Embedded files
+//         EmbedPatterns      []string // //go:embed patterns
+//         EmbedFiles         []string // files matched by EmbedPatterns
+//         TestEmbedPatterns  []string // //go:embed patterns in TestGoFiles
+//         TestEmbedFiles     []string // files matched by TestEmbedPatterns
+//         XTestEmbedPatterns []string // //go:embed patterns in XTestGoFiles
+//         XTestEmbedFiles    []string // files matched by XTestEmbedPatterns
+//
 //         // Cgo directives
 //         CgoCFLAGS    []string // cgo: flags for C compiler
 //         CgoCPPFLAGS  []string // cgo: flags for C preprocessor
@@ -1169,8 +1178,8 @@
 //
 // 'Go test' recompiles each package along with any files with names matching
 // the file pattern "*_test.go".
-// These additional files can contain test functions, benchmark functions, and
-// example functions. See 'go help testfunc' for more.
+// These additional files can contain test functions, benchmark functions, fuzz
+// targets, and example functions. See 'go help testfunc' for more.
 // Each listed package causes the execution of a separate test binary.
 // Files whose names begin with "_" (including "_test.go") or "." are ignored.
 //
@@ -1463,11 +1472,19 @@
 // 		For more details see: 'go help gopath'.
 // 	GOPROXY
 // 		URL of Go module proxy. See 'go help goproxy'.
+// 	GOPRIVATE, GONOPROXY, GONOSUMDB
+// 		Comma-separated list of glob patterns (in the syntax of Go's path.Match)
+// 		of module path prefixes that should always be fetched directly
+// 		or that should not be compared against the checksum database.
+// 		See 'go help module-private'.
 // 	GORACE
 // 		Options for the race detector.
 // 		See https://golang.org/doc/articles/race_detector.html.
 // 	GOROOT
 // 		The root of the go tree.
+// 	GOSUMDB
+// 		The name of checksum database to use and optionally its public key and
+// 		URL. See 'go help modules'.
 // 	GOTMPDIR
 // 		The directory where the go command will write
 // 		temporary source files, packages, and binaries.
@@ -1853,7 +1870,9 @@
 // or is the string "direct", downloads use the default direct connection to version
 // control systems. Setting GOPROXY to "off" disallows downloading modules from
 // any source. Otherwise, GOPROXY is expected to be the URL of a module proxy,
-// in which case the go command will fetch all modules from that proxy.
+// in which case the go command will fetch all modules from that proxy,
+// except modules matching GONOPROXY or GOPRIVATE, which are always fetched
+// directly (see 'go help module-private').
 // No matter the source of the modules, downloaded modules must match existing
 // entries in go.sum (see 'go help modules' for discussion of verification).
 //
@@ -1876,6 +1895,12 @@
 // GET $GOPROXY/<module>/@v/<version>.zip returns the zip archive
 // for that version of the given module.
 //
+// A proxy may also serve the checksum database named by GOSUMDB
+// (see 'go help modules'). Before accessing the database through the proxy,
+// the go command requests $GOPROXY/sumdb/<sumdb-name>/supported.
+// If that request succeeds, the go command sends all database requests
+// to $GOPROXY/sumdb/<sumdb-name>/ instead of connecting to the database directly.
+//
 // To avoid problems when serving from case-sensitive file systems,
 // the <module> and <version> elements are case-encoded, replacing every
 // uppercase letter with an exclamation mark followed by the corresponding
@@ -2428,6 +2453,25 @@
 // used, its checksum is added to go.sum if missing or else required to match
 // the existing entry in go.sum.
 //
+// Before adding a checksum for a new module version to go.sum, the go
+// command consults a checksum database, a transparent log of the go.sum
+// lines for all publicly available module versions. The go command
+// checks that the database's answer is authenticated by its public key
+// and consistent with every earlier answer it has seen, and it refuses
+// to add a checksum that does not match the one reported by the database.
+// The GOSUMDB environment variable identifies the database to use:
+// it defaults to "sum.golang.org", the Go checksum database run by Google.
+// GOSUMDB may also be a verifier key "<name>+<hash>+<keydata>", optionally
+// followed by a space and the URL of the database, to use another database,
+// or "off" to disable the checksum database entirely.
+// The go command stores the latest verified tree head of each database
+// in $GOPATH/pkg/sumdb and caches lookups and log tiles in
+// $GOPATH/pkg/mod/cache/download/sumdb.
+//
+// Modules that are not publicly available cannot be verified by the
+// public checksum database; see 'go help module-private' for how to
+// exclude them.
+//
 // The go command maintains a cache of downloaded packages and computes
 // and records the cryptographic checksum of each package at download time.
 // In normal operation, the go command checks these pre-computed checksums
@@ -2438,7 +2482,10 @@
 //
 // The go command can fetch modules from a proxy instead of connecting
 // to source control systems directly, according to the setting of the GOPROXY
-// environment variable.
+// environment variable. When GOPROXY names a proxy that also serves the
+// checksum database (at <proxy>/sumdb/<database-name>/), the go command
+// accesses the database through the proxy; its answers are still
+// authenticated using the database's public key.
 //
 // See 'go help goproxy' for details about the proxy and also the format of
 // the cached downloaded packages.
@@ -2567,6 +2614,48 @@
 // See also: go build, go install, go clean, go mod.
 //
 //
+// Module configuration for non-public modules
+//
+// The go command defaults to downloading modules from the source control
+// systems or the module proxy named by GOPROXY, and to verifying downloaded
+// modules against the public Go checksum database at sum.golang.org
+// (see 'go help modules'). These defaults work well for publicly available
+// source code.
+//
+// The GOPRIVATE environment variable controls which modules the go command
+// considers to be private (not available publicly) and should therefore not use
+// the proxy or checksum database. The variable is a comma-separated list of
+// glob patterns (in the syntax of Go's path.Match) of module path prefixes.
+// For example,
+//
+// 	GOPRIVATE=*.corp.example.com,rsc.io/private
+//
+// causes the go command to treat as private any module with a path prefix
+// matching either pattern, including git.corp.example.com/xyzzy, rsc.io/private,
+// and rsc.io/private/quux.
+//
+// For fine-grained control over module download and validation, the GONOPROXY
+// and GONOSUMDB environment variables accept the same kind of glob list
+// and override GOPRIVATE for the specific decision of whether to use the proxy
+// and checksum database, respectively.
+//
+// For example, if a company ran a module proxy serving private modules,
+// users would configure go using:
+//
+// 	GOPRIVATE=*.corp.example.com
+// 	GOPROXY=proxy.example.com
+// 	GONOPROXY=none
+//
+// This would tell the go command and other tools that modules beginning with
+// a corp.example.com subdomain are private but that the company proxy should
+// be used for downloading both public and private modules, because
+// GONOPROXY has been set to a pattern that won't match any modules,
+// overriding GOPRIVATE.
+//
+// Setting GOSUMDB=off disables the checksum database for all modules,
+// and 'go get -insecure' disables it for the modules that command downloads.
+//
+//
 // Package lists and patterns
 //
 // Many commands apply to a set of packages:
@@ -2721,6 +2810,25 @@
 // 	-failfast
 // 	    Do not start new tests after the first test failure.
 //
+// 	-fuzz regexp
+// 	    Run the fuzz target matching the regular expression. When specified,
+// 	    the command line argument must match exactly one package, and regexp
+// 	    must match exactly one fuzz target within that package. After tests,
+// 	    benchmarks, seed corpora of other fuzz targets, and examples have
+// 	    completed, the matching target will be fuzzed. See the Fuzzing
+// 	    section of the testing package documentation for details.
+//
+// 	-fuzzminimizetime t
+// 	    Run enough iterations of the fuzz target during each minimization
+// 	    attempt to take t, as specified as a time.Duration (for example,
+// 	    -fuzzminimizetime 30s). The default is 60s.
+//
+// 	-fuzztime t
+// 	    Run enough iterations of the fuzz target to take t, specified as a
+// 	    time.Duration (for example, -fuzztime 1h30s). The default is to run
+// 	    forever. The special syntax Nx means to run the fuzz target N times
+// 	    (for example, -fuzztime 1000x).
+//
 // 	-list regexp
 // 	    List tests, benchmarks, or examples matching the regular expression.
 // 	    No tests, benchmarks or examples will be run. This will only
@@ -2899,6 +3007,16 @@
 //
 // 	func BenchmarkXxx(b *testing.B) { ... }
 //
+// A fuzz target is one named FuzzXxx and should have the signature,
+//
+// 	func FuzzXxx(f *testing.F) { ... }
+//
+// When 'go test' is run without -fuzz, each fuzz target runs its seed corpus,
+// made up of the values passed to f.Add and the files stored in
+// testdata/fuzz/FuzzXxx, as ordinary tests. With -fuzz, the fuzz target is
+// run with generated inputs until it fails, and the failing input is written
+// to testdata/fuzz/FuzzXxx so that later runs of 'go test' reproduce it.
+//
 // An example function is similar to a test function but, instead of using
 // *testing.T to report success or failure, prints output to os.Stdout.
 // If the last comment in the function starts with "Output:" then the output
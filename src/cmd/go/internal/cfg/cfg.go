@@ -107,6 +107,25 @@ var (
 	GOMIPS64 = objabi.GOMIPS64
 )
 
+// Module-related environment variables.
+// GONOPROXY and GONOSUMDB default to GOPRIVATE,
+// and GOSUMDB defaults to the public checksum database.
+var (
+	GOPRIVATE = os.Getenv("GOPRIVATE")
+	GONOPROXY = envOr("GONOPROXY", GOPRIVATE)
+	GONOSUMDB = envOr("GONOSUMDB", GOPRIVATE)
+	GOSUMDB   = envOr("GOSUMDB", "sum.golang.org")
+)
+
+// envOr returns the value of the named environment variable,
+// or def if the variable is unset or empty.
+func envOr(name, def string) string {
+	if val := os.Getenv(name); val != "" {
+		return val
+	}
+	return def
+}
+
 // Update build context to use our computed GOROOT.
 func init() {
 	BuildContext.GOROOT = GOROOT
@@ -96,6 +96,10 @@ type PackagePublic struct {
 	SwigCXXFiles    []string `json:",omitempty"` // .swigcxx files
 	SysoFiles       []string `json:",omitempty"` // .syso system object files added to package
 
+	// Embedded files
+	EmbedPatterns []string `json:",omitempty"` // //go:embed patterns
+	EmbedFiles    []string `json:",omitempty"` // files matched by EmbedPatterns
+
 	// Cgo directives
 	CgoCFLAGS    []string `json:",omitempty"` // cgo: flags for C compiler
 	CgoCPPFLAGS  []string `json:",omitempty"` // cgo: flags for C preprocessor
@@ -117,10 +121,14 @@ type PackagePublic struct {
 	// Test information
 	// If you add to this list you MUST add to p.AllFiles (below) too.
 	// Otherwise file name security lists will not apply to any new additions.
-	TestGoFiles  []string `json:",omitempty"` // _test.go files in package
-	TestImports  []string `json:",omitempty"` // imports from TestGoFiles
-	XTestGoFiles []string `json:",omitempty"` // _test.go files outside package
-	XTestImports []string `json:",omitempty"` // imports from XTestGoFiles
+	TestGoFiles        []string `json:",omitempty"` // _test.go files in package
+	TestImports        []string `json:",omitempty"` // imports from TestGoFiles
+	TestEmbedPatterns  []string `json:",omitempty"` // //go:embed patterns
+	TestEmbedFiles     []string `json:",omitempty"` // files matched by TestEmbedPatterns
+	XTestGoFiles       []string `json:",omitempty"` // _test.go files outside package
+	XTestImports       []string `json:",omitempty"` // imports from XTestGoFiles
+	XTestEmbedPatterns []string `json:",omitempty"` // //go:embed patterns
+	XTestEmbedFiles    []string `json:",omitempty"` // files matched by XTestEmbedPatterns
 }
 
 // AllFiles returns the names of all the files considered for the package.
@@ -129,7 +137,7 @@ type PackagePublic struct {
 // The go/build package filtered others out (like foo_wrongGOARCH.s)
 // and that's OK.
 func (p *Package) AllFiles() []string {
-	return str.StringList(
+	files := str.StringList(
 		p.GoFiles,
 		p.CgoFiles,
 		// no p.CompiledGoFiles, because they are from GoFiles or generated by us
@@ -146,6 +154,20 @@ func (p *Package) AllFiles() []string {
 		p.TestGoFiles,
 		p.XTestGoFiles,
 	)
+
+	// EmbedFiles may overlap with the other files and with each other.
+	have := make(map[string]bool)
+	for _, file := range files {
+		have[file] = true
+	}
+	for _, file := range str.StringList(p.EmbedFiles, p.TestEmbedFiles, p.XTestEmbedFiles) {
+		if have[file] {
+			continue
+		}
+		have[file] = true
+		files = append(files, file)
+	}
+	return files
 }
 
 // Desc returns the package "description", for use in b.showOutput.
@@ -171,10 +193,14 @@ type PackageInternal struct {
 	ExeName           string               // desired name for temporary executable
 	CoverMode         string               // preprocess Go source files with the coverage tool in this mode
 	CoverVars         map[string]*CoverVar // variables created by coverage analysis
+	FuzzInstrument    bool                 // package should be instrumented for fuzzing
 	OmitDebug         bool                 // tell linker not to write debug information
 	GobinSubdir       bool                 // install target would be subdir of GOBIN
 	BuildInfo         string               // add this info to package main
 	TestmainGo        *[]byte              // content for _testmain.go
+	Embed             map[string][]string  // //go:embed pattern to matched files
+	TestEmbed         map[string][]string  // //go:embed pattern to matched files, for TestEmbedPatterns
+	XTestEmbed        map[string][]string  // //go:embed pattern to matched files, for XTestEmbedPatterns
 
 	Asmflags   []string // -asmflags for this package
 	Gcflags    []string // -gcflags for this package
@@ -281,6 +307,7 @@ func (p *Package) copyBuild(pp *build.Package) {
 	p.CgoFFLAGS = pp.CgoFFLAGS
 	p.CgoLDFLAGS = pp.CgoLDFLAGS
 	p.CgoPkgConfig = pp.CgoPkgConfig
+	p.EmbedPatterns = pp.EmbedPatterns
 	// We modify p.Imports in place, so make copy now.
 	p.Imports = make([]string, len(pp.Imports))
 	copy(p.Imports, pp.Imports)
@@ -289,6 +316,8 @@ func (p *Package) copyBuild(pp *build.Package) {
 	p.TestImports = pp.TestImports
 	p.XTestGoFiles = pp.XTestGoFiles
 	p.XTestImports = pp.XTestImports
+	p.TestEmbedPatterns = pp.TestEmbedPatterns
+	p.XTestEmbedPatterns = pp.XTestEmbedPatterns
 	if IgnoreImports {
 		p.Imports = nil
 		p.Internal.RawImports = nil
@@ -1349,6 +1378,31 @@ func (p *Package) load(stk *ImportStack, bp *build.Package, err error) {
 		}
 	}
 
+	// Resolve //go:embed patterns to the files they match.
+	for _, e := range []struct {
+		files    *[]string
+		embed    *map[string][]string
+		patterns []string
+		pos      map[string][]token.Position
+	}{
+		{&p.EmbedFiles, &p.Internal.Embed, p.EmbedPatterns, bp.EmbedPatternPos},
+		{&p.TestEmbedFiles, &p.Internal.TestEmbed, p.TestEmbedPatterns, bp.TestEmbedPatternPos},
+		{&p.XTestEmbedFiles, &p.Internal.XTestEmbed, p.XTestEmbedPatterns, bp.XTestEmbedPatternPos},
+	} {
+		files, embed, err := resolveEmbed(p.Dir, e.patterns)
+		if err != nil {
+			p.Error = &PackageError{
+				ImportStack: stk.Copy(),
+				Err:         err.Error(),
+			}
+			if err, ok := err.(*embedError); ok {
+				setErrorPos(p, e.pos[err.pattern])
+			}
+			return
+		}
+		*e.files, *e.embed = files, embed
+	}
+
 	// Check for case-insensitive collision of input files.
 	// To avoid problems on case-insensitive files, we reject any package
 	// where two different input files have equal names under a case-insensitive
@@ -1545,6 +1599,165 @@ func SafeArg(name string) bool {
 	return '0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || c == '.' || c == '_' || c == '/' || c >= utf8.RuneSelf
 }
 
+// An embedError describes a problem resolving a //go:embed pattern.
+type embedError struct {
+	pattern string
+	err     error
+}
+
+func (e *embedError) Error() string {
+	return fmt.Sprintf("pattern %s: %v", e.pattern, e.err)
+}
+
+// resolveEmbed resolves //go:embed patterns to precise file lists.
+// It returns files, the sorted list of all files matched by any pattern,
+// and pmap, the sorted list of files matched by each pattern.
+// Patterns are slash-separated paths relative to pkgdir.
+// A pattern matching a directory matches all the files in that
+// directory tree, except those whose names begin with . or _
+// and those in other modules.
+func resolveEmbed(pkgdir string, patterns []string) (files []string, pmap map[string][]string, err error) {
+	if len(patterns) == 0 {
+		return nil, nil, nil
+	}
+	have := make(map[string]bool)
+	pmap = make(map[string][]string)
+	for _, pattern := range patterns {
+		list, err := resolveEmbedPattern(pkgdir, pattern)
+		if err != nil {
+			return nil, nil, &embedError{pattern, err}
+		}
+		pmap[pattern] = list
+		for _, file := range list {
+			if !have[file] {
+				have[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, pmap, nil
+}
+
+// resolveEmbedPattern returns the sorted list of files matched by
+// the //go:embed pattern in pkgdir.
+func resolveEmbedPattern(pkgdir, pattern string) ([]string, error) {
+	if _, err := pathpkg.Match(pattern, ""); err != nil || !validEmbedPattern(pattern) {
+		return nil, fmt.Errorf("invalid pattern syntax")
+	}
+	match, err := filepath.Glob(str.QuoteGlob(pkgdir) + string(filepath.Separator) + filepath.FromSlash(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	var list []string
+	have := make(map[string]bool)
+	add := func(rel string) {
+		if !have[rel] {
+			have[rel] = true
+			list = append(list, rel)
+		}
+	}
+	for _, file := range match {
+		rel := filepath.ToSlash(file[len(pkgdir)+1:])
+		info, err := os.Lstat(file)
+		if err != nil {
+			return nil, err
+		}
+		what := "file"
+		if info.IsDir() {
+			what = "directory"
+		}
+
+		// Check that the directories along the path are
+		// embeddable and do not begin a new module.
+		for dir := file; len(dir) > len(pkgdir)+1; dir = filepath.Dir(dir) {
+			if isBadEmbedName(filepath.Base(dir)) {
+				return nil, fmt.Errorf("cannot embed %s %s: invalid name %s", what, rel, filepath.Base(dir))
+			}
+			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+				return nil, fmt.Errorf("cannot embed %s %s: in different module", what, rel)
+			}
+		}
+
+		switch {
+		default:
+			return nil, fmt.Errorf("cannot embed irregular file %s", rel)
+
+		case info.Mode().IsRegular():
+			add(rel)
+
+		case info.IsDir():
+			// Gather all files in the named directory, stopping at
+			// module boundaries and ignoring hidden files.
+			count := 0
+			err := filepath.Walk(file, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if path == file {
+					return nil
+				}
+				name := info.Name()
+				if isBadEmbedName(name) || name[0] == '.' || name[0] == '_' {
+					if info.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if info.IsDir() {
+					if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if !info.Mode().IsRegular() {
+					return nil
+				}
+				count++
+				add(filepath.ToSlash(path[len(pkgdir)+1:]))
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return nil, fmt.Errorf("cannot embed directory %s: contains no embeddable files", rel)
+			}
+		}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no matching files found")
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// validEmbedPattern reports whether pattern is a valid //go:embed pattern:
+// an unrooted slash-separated path with no empty, . or .. elements.
+func validEmbedPattern(pattern string) bool {
+	if pattern == "" || strings.Contains(pattern, `\`) {
+		return false
+	}
+	for _, elem := range strings.Split(pattern, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// isBadEmbedName reports whether name is the name of a file
+// or directory that must not be embedded: version control
+// metadata, which would not be included in a module.
+func isBadEmbedName(name string) bool {
+	switch name {
+	case ".bzr", ".hg", ".git", ".svn":
+		return true
+	}
+	return false
+}
+
 // LinkerDeps returns the list of linker-induced dependencies for main package p.
 func LinkerDeps(p *Package) []string {
 	// Everything links runtime.
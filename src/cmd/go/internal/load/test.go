@@ -139,6 +139,26 @@ func TestPackagesFor(p *Package, cover *TestCover) (pmain, ptest, pxtest *Packag
 			m[k] = append(m[k], v...)
 		}
 		ptest.Internal.Build.ImportPos = m
+
+		if len(p.TestEmbedPatterns) > 0 {
+			ptest.EmbedPatterns = str.StringList(p.EmbedPatterns, p.TestEmbedPatterns)
+			ptest.EmbedFiles = str.StringList(p.EmbedFiles, p.TestEmbedFiles)
+			ptest.Internal.Embed = map[string][]string{}
+			for k, v := range p.Internal.Embed {
+				ptest.Internal.Embed[k] = v
+			}
+			for k, v := range p.Internal.TestEmbed {
+				ptest.Internal.Embed[k] = v
+			}
+			m := map[string][]token.Position{}
+			for k, v := range p.Internal.Build.EmbedPatternPos {
+				m[k] = append(m[k], v...)
+			}
+			for k, v := range p.Internal.Build.TestEmbedPatternPos {
+				m[k] = append(m[k], v...)
+			}
+			ptest.Internal.Build.EmbedPatternPos = m
+		}
 	} else {
 		ptest = p
 	}
@@ -147,26 +167,31 @@ func TestPackagesFor(p *Package, cover *TestCover) (pmain, ptest, pxtest *Packag
 	if len(p.XTestGoFiles) > 0 {
 		pxtest = &Package{
 			PackagePublic: PackagePublic{
-				Name:       p.Name + "_test",
-				ImportPath: p.ImportPath + "_test",
-				Root:       p.Root,
-				Dir:        p.Dir,
-				GoFiles:    p.XTestGoFiles,
-				Imports:    p.XTestImports,
-				ForTest:    p.ImportPath,
+				Name:          p.Name + "_test",
+				ImportPath:    p.ImportPath + "_test",
+				Root:          p.Root,
+				Dir:           p.Dir,
+				GoFiles:       p.XTestGoFiles,
+				Imports:       p.XTestImports,
+				ForTest:       p.ImportPath,
+				EmbedPatterns: p.XTestEmbedPatterns,
+				EmbedFiles:    p.XTestEmbedFiles,
 			},
 			Internal: PackageInternal{
 				LocalPrefix: p.Internal.LocalPrefix,
 				Build: &build.Package{
-					ImportPos: p.Internal.Build.XTestImportPos,
+					ImportPos:       p.Internal.Build.XTestImportPos,
+					EmbedPatternPos: p.Internal.Build.XTestEmbedPatternPos,
 				},
 				Imports:    ximports,
 				RawImports: rawXTestImports,
+				Embed:      p.Internal.XTestEmbed,
 
-				Asmflags:   p.Internal.Asmflags,
-				Gcflags:    p.Internal.Gcflags,
-				Ldflags:    p.Internal.Ldflags,
-				Gccgoflags: p.Internal.Gccgoflags,
+				Asmflags:       p.Internal.Asmflags,
+				Gcflags:        p.Internal.Gcflags,
+				Ldflags:        p.Internal.Ldflags,
+				Gccgoflags:     p.Internal.Gccgoflags,
+				FuzzInstrument: p.Internal.FuzzInstrument,
 			},
 		}
 		if pxtestNeedsPtest {
@@ -365,7 +390,7 @@ func recompileForTest(pmain, preal, ptest, pxtest *Package) {
 }
 
 // isTestFunc tells whether fn has the type of a testing function. arg
-// specifies the parameter type we look for: B, M or T.
+// specifies the parameter type we look for: B, F, M or T.
 func isTestFunc(fn *ast.FuncDecl, arg string) bool {
 	if fn.Type.Results != nil && len(fn.Type.Results.List) > 0 ||
 		fn.Type.Params.List == nil ||
@@ -380,7 +405,7 @@ func isTestFunc(fn *ast.FuncDecl, arg string) bool {
 	// We can't easily check that the type is *testing.M
 	// because we don't know how testing has been imported,
 	// but at least check that it's *M or *something.M.
-	// Same applies for B and T.
+	// Same applies for B, F and T.
 	if name, ok := ptr.X.(*ast.Ident); ok && name.Name == arg {
 		return true
 	}
@@ -390,7 +415,7 @@ func isTestFunc(fn *ast.FuncDecl, arg string) bool {
 	return false
 }
 
-// isTest tells whether name looks like a test (or benchmark, according to prefix).
+// isTest tells whether name looks like a test (or benchmark or fuzz target, according to prefix).
 // It is a Test (say) if there is a character after Test that is not a lower-case letter.
 // We don't want TesticularCancer.
 func isTest(name, prefix string) bool {
@@ -439,6 +464,7 @@ func formatTestmain(t *testFuncs) ([]byte, error) {
 type testFuncs struct {
 	Tests       []testFunc
 	Benchmarks  []testFunc
+	FuzzTargets []testFunc
 	Examples    []testFunc
 	TestMain    *testFunc
 	Package     *Package
@@ -531,6 +557,13 @@ func (t *testFuncs) load(filename, pkg string, doImport, seen *bool) error {
 			}
 			t.Benchmarks = append(t.Benchmarks, testFunc{pkg, name, "", false})
 			*doImport, *seen = true, true
+		case isTest(name, "Fuzz"):
+			err := checkTestFunc(n, "F")
+			if err != nil {
+				return err
+			}
+			t.FuzzTargets = append(t.FuzzTargets, testFunc{pkg, name, "", false})
+			*doImport, *seen = true, true
 		}
 	}
 	ex := doc.Examples(f)
@@ -591,6 +624,12 @@ var benchmarks = []testing.InternalBenchmark{
 {{end}}
 }
 
+var fuzzTargets = []testing.InternalFuzzTarget{
+{{range .FuzzTargets}}
+	{"{{.Name}}", {{.Package}}.{{.Name}}},
+{{end}}
+}
+
 var examples = []testing.InternalExample{
 {{range .Examples}}
 	{"{{.Name}}", {{.Package}}.{{.Name}}, {{.Output | printf "%q"}}, {{.Unordered}}},
@@ -649,7 +688,7 @@ func main() {
 		CoveredPackages: {{printf "%q" .Covered}},
 	})
 {{end}}
-	m := testing.MainStart(testdeps.TestDeps{}, tests, benchmarks, examples)
+	m := testing.MainStart(testdeps.TestDeps{}, tests, benchmarks, fuzzTargets, examples)
 {{with .TestMain}}
 	{{.Package}}.{{.Name}}(m)
 {{else}}
@@ -27,6 +27,8 @@ var QuietLookup bool // do not print about lookups
 
 var PkgMod string // $GOPATH/pkg/mod; set by package modload
 
+var SumdbDir string // $GOPATH/pkg/sumdb; set by package modload
+
 func cacheDir(path string) (string, error) {
 	if PkgMod == "" {
 		return "", fmt.Errorf("internal error: modfetch.PkgMod not set")
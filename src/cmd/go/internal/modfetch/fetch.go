@@ -395,25 +395,84 @@ func checkGoMod(path, version string, data []byte) {
 }
 
 // checkOneSum checks that the recorded hash for mod is h.
+// If go.sum does not yet list mod, checkOneSum consults
+// the checksum database (if enabled for mod) before adding h.
 func checkOneSum(mod module.Version, h string) {
+	// We lock goSum when manipulating it,
+	// but we arrange to release the lock when calling checkSumDB,
+	// so that parallel calls to checkOneSum can execute parallel calls
+	// to checkSumDB.
+
+	// Check whether mod+h is listed in go.sum already. If so, we're done.
 	goSum.mu.Lock()
-	defer goSum.mu.Unlock()
-	if initGoSum() {
+	inited := initGoSum()
+	done := inited && haveModSumLocked(mod, h)
+	if done {
+		goSum.checked[modSum{mod, h}] = true
+	}
+	goSum.mu.Unlock()
+
+	if done {
+		return
+	}
+
+	// Not listed, so we want to add them.
+	// Consult checksum database if appropriate.
+	if useSumDB(mod) {
+		// Calls base.Fatalf if mismatch detected.
+		checkSumDB(mod, h)
+	}
+
+	// Add mod+h to go.sum, if it hasn't appeared already.
+	if inited {
+		goSum.mu.Lock()
 		checkOneSumLocked(mod, h)
+		goSum.mu.Unlock()
 	}
 }
 
-func checkOneSumLocked(mod module.Version, h string) {
-	goSum.checked[modSum{mod, h}] = true
-
+// haveModSumLocked reports whether the pair mod,h is already listed in go.sum.
+// If it finds a conflicting pair instead, it calls base.Fatalf.
+// goSum.mu must be locked.
+func haveModSumLocked(mod module.Version, h string) bool {
 	for _, vh := range goSum.m[mod] {
 		if h == vh {
-			return
+			return true
 		}
 		if strings.HasPrefix(vh, "h1:") {
 			base.Fatalf("verifying %s@%s: checksum mismatch\n\tdownloaded: %v\n\tgo.sum:     %v", mod.Path, mod.Version, h, vh)
 		}
 	}
+	return false
+}
+
+// checkSumDB checks the mod, h pair against the Go checksum database.
+// It calls base.Fatalf if the hash is to be rejected.
+func checkSumDB(mod module.Version, h string) {
+	db, lines, err := lookupSumDB(mod)
+	if err != nil {
+		base.Fatalf("verifying %s@%s: %v", mod.Path, mod.Version, err)
+	}
+
+	have := mod.Path + " " + mod.Version + " " + h
+	prefix := mod.Path + " " + mod.Version + " h1:"
+	for _, line := range lines {
+		if line == have {
+			return
+		}
+		if strings.HasPrefix(line, prefix) {
+			base.Fatalf("verifying %s@%s: checksum mismatch\n\tdownloaded: %v\n\t%s: %v", mod.Path, mod.Version, h, db, line[len(prefix)-len("h1:"):])
+		}
+	}
+	base.Fatalf("verifying %s@%s: checksum missing from %s response", mod.Path, mod.Version, db)
+}
+
+func checkOneSumLocked(mod module.Version, h string) {
+	goSum.checked[modSum{mod, h}] = true
+
+	if haveModSumLocked(mod, h) {
+		return
+	}
 	if len(goSum.m[mod]) > 0 {
 		fmt.Fprintf(os.Stderr, "warning: verifying %s@%s: unknown hashes in go.sum: %v; adding %v", mod.Path, mod.Version, strings.Join(goSum.m[mod], ", "), h)
 	}
@@ -539,3 +598,48 @@ func TrimGoSum(keep map[module.Version]bool) {
 		}
 	}
 }
+
+var HelpPrivate = &base.Command{
+	UsageLine: "module-private",
+	Short:     "module configuration for non-public modules",
+	Long: `
+The go command defaults to downloading modules from the source control
+systems or the module proxy named by GOPROXY, and to verifying downloaded
+modules against the public Go checksum database at sum.golang.org
+(see 'go help modules'). These defaults work well for publicly available
+source code.
+
+The GOPRIVATE environment variable controls which modules the go command
+considers to be private (not available publicly) and should therefore not use
+the proxy or checksum database. The variable is a comma-separated list of
+glob patterns (in the syntax of Go's path.Match) of module path prefixes.
+For example,
+
+	GOPRIVATE=*.corp.example.com,rsc.io/private
+
+causes the go command to treat as private any module with a path prefix
+matching either pattern, including git.corp.example.com/xyzzy, rsc.io/private,
+and rsc.io/private/quux.
+
+For fine-grained control over module download and validation, the GONOPROXY
+and GONOSUMDB environment variables accept the same kind of glob list
+and override GOPRIVATE for the specific decision of whether to use the proxy
+and checksum database, respectively.
+
+For example, if a company ran a module proxy serving private modules,
+users would configure go using:
+
+	GOPRIVATE=*.corp.example.com
+	GOPROXY=proxy.example.com
+	GONOPROXY=none
+
+This would tell the go command and other tools that modules beginning with
+a corp.example.com subdomain are private but that the company proxy should
+be used for downloading both public and private modules, because
+GONOPROXY has been set to a pattern that won't match any modules,
+overriding GOPRIVATE.
+
+Setting GOSUMDB=off disables the checksum database for all modules,
+and 'go get -insecure' disables it for the modules that command downloads.
+`,
+}
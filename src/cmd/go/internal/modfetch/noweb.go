@@ -9,6 +9,8 @@ package modfetch
 import (
 	"fmt"
 	"io"
+
+	"cmd/go/internal/module"
 )
 
 func webGetGoGet(url string, body *io.ReadCloser) error {
@@ -22,3 +24,11 @@ func webGetBytes(url string, body *[]byte) error {
 func webGetBody(url string, body *io.ReadCloser) error {
 	return fmt.Errorf("no network in go_bootstrap")
 }
+
+func useSumDB(mod module.Version) bool {
+	return false
+}
+
+func lookupSumDB(mod module.Version) (string, []string, error) {
+	return "", nil, fmt.Errorf("bootstrap mode has no checksum database")
+}
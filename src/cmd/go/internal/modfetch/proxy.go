@@ -29,7 +29,9 @@ further control over the download source. If GOPROXY is unset, is the empty stri
 or is the string "direct", downloads use the default direct connection to version
 control systems. Setting GOPROXY to "off" disallows downloading modules from
 any source. Otherwise, GOPROXY is expected to be the URL of a module proxy,
-in which case the go command will fetch all modules from that proxy.
+in which case the go command will fetch all modules from that proxy,
+except modules matching GONOPROXY or GOPRIVATE, which are always fetched
+directly (see 'go help module-private').
 No matter the source of the modules, downloaded modules must match existing
 entries in go.sum (see 'go help modules' for discussion of verification).
 
@@ -52,6 +54,12 @@ for that version of the given module.
 GET $GOPROXY/<module>/@v/<version>.zip returns the zip archive
 for that version of the given module.
 
+A proxy may also serve the checksum database named by GOSUMDB
+(see 'go help modules'). Before accessing the database through the proxy,
+the go command requests $GOPROXY/sumdb/<sumdb-name>/supported.
+If that request succeeds, the go command sends all database requests
+to $GOPROXY/sumdb/<sumdb-name>/ instead of connecting to the database directly.
+
 To avoid problems when serving from case-sensitive file systems,
 the <module> and <version> elements are case-encoded, replacing every
 uppercase letter with an exclamation mark followed by the corresponding
@@ -343,6 +343,25 @@ of the content of specific module versions. Each time a dependency is
 used, its checksum is added to go.sum if missing or else required to match
 the existing entry in go.sum.
 
+Before adding a checksum for a new module version to go.sum, the go
+command consults a checksum database, a transparent log of the go.sum
+lines for all publicly available module versions. The go command
+checks that the database's answer is authenticated by its public key
+and consistent with every earlier answer it has seen, and it refuses
+to add a checksum that does not match the one reported by the database.
+The GOSUMDB environment variable identifies the database to use:
+it defaults to "sum.golang.org", the Go checksum database run by Google.
+GOSUMDB may also be a verifier key "<name>+<hash>+<keydata>", optionally
+followed by a space and the URL of the database, to use another database,
+or "off" to disable the checksum database entirely.
+The go command stores the latest verified tree head of each database
+in $GOPATH/pkg/sumdb and caches lookups and log tiles in
+$GOPATH/pkg/mod/cache/download/sumdb.
+
+Modules that are not publicly available cannot be verified by the
+public checksum database; see 'go help module-private' for how to
+exclude them.
+
 The go command maintains a cache of downloaded packages and computes
 and records the cryptographic checksum of each package at download time.
 In normal operation, the go command checks these pre-computed checksums
@@ -353,7 +372,10 @@ checksums and the entries in go.sum.
 
 The go command can fetch modules from a proxy instead of connecting
 to source control systems directly, according to the setting of the GOPROXY
-environment variable.
+environment variable. When GOPROXY names a proxy that also serves the
+checksum database (at <proxy>/sumdb/<database-name>/), the go command
+accesses the database through the proxy; its answers are still
+authenticated using the database's public key.
 
 See 'go help goproxy' for details about the proxy and also the format of
 the cached downloaded packages.
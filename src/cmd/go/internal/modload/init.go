@@ -208,6 +208,7 @@ func Init() {
 	}
 
 	modfetch.PkgMod = pkgMod
+	modfetch.SumdbDir = filepath.Join(list[0], "pkg/sumdb")
 	codehost.WorkRoot = filepath.Join(pkgMod, "cache/vcs")
 
 	cfg.ModulesEnabled = true
@@ -257,6 +258,7 @@ func init() {
 	// Set modfetch.PkgMod unconditionally, so that go clean -modcache can run even without modules enabled.
 	if list := filepath.SplitList(cfg.BuildContext.GOPATH); len(list) > 0 && list[0] != "" {
 		modfetch.PkgMod = filepath.Join(list[0], "pkg/mod")
+		modfetch.SumdbDir = filepath.Join(list[0], "pkg/sumdb")
 	}
 }
 
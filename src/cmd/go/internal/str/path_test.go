@@ -0,0 +1,34 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package str
+
+import "testing"
+
+var globsMatchTests = []struct {
+	globs  string
+	target string
+	want   bool
+}{
+	{"", "rsc.io/quote", false},
+	{"rsc.io", "rsc.io/quote", true},
+	{"rsc.io/quote", "rsc.io/quote", true},
+	{"rsc.io/quote", "rsc.io/quote/v3", true},
+	{"rsc.io/quote/v3", "rsc.io/quote", false},
+	{"rsc.io/q", "rsc.io/quote", false},
+	{"*.corp.example.com", "git.corp.example.com/xyzzy", true},
+	{"*.corp.example.com", "corp.example.com/xyzzy", false},
+	{"*/quote", "rsc.io/quote/v3", true},
+	{"example.com,,rsc.io", "rsc.io/sampler", true},
+	{"example.com,[", "rsc.io/sampler", false},
+	{"rsc.io/*", "rsc.io", false},
+}
+
+func TestGlobsMatchPath(t *testing.T) {
+	for _, tt := range globsMatchTests {
+		if got := GlobsMatchPath(tt.globs, tt.target); got != tt.want {
+			t.Errorf("GlobsMatchPath(%q, %q) = %v, want %v", tt.globs, tt.target, got, tt.want)
+		}
+	}
+}
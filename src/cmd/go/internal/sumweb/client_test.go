@@ -0,0 +1,319 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sumweb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"cmd/go/internal/module"
+	"cmd/go/internal/note"
+	"cmd/go/internal/tlog"
+)
+
+const (
+	testName        = "localhost.localdev/sumdb"
+	testVerifierKey = "localhost.localdev/sumdb+5166803f+AU54bm/1qZs2MryS5Ek49lYlOc9si1a0r6mHaABASY4R"
+	testSignerKey   = "PRIVATE+KEY+localhost.localdev/sumdb+5166803f+AU9vZJjk0jBFXAqEr/OvMYJ9/ug9KewzQKTtT0W1Fcp6"
+)
+
+func TestClientLookup(t *testing.T) {
+	tc := newTestClient(t)
+	tc.mustLookup("rsc.io/sampler", "v1.3.0", "rsc.io/sampler v1.3.0 h1:7uVkIFmeBqHfdjD+gZwtXXI+RODJ2Wc4O7MPEh/QiW4=")
+	tc.mustHaveLatest(1)
+	tc.mustLookup("rsc.io/sampler", "v1.3.0/go.mod", "rsc.io/sampler v1.3.0/go.mod h1:T1hPZKmBbMNahiBKFy5HrXp6adAjACjK9JXDnKaTXpA=")
+	tc.mustHaveLatest(1)
+
+	// Everything should now be cached, both for the original client and a fresh one.
+	tc.wantRemote = false
+	tc.mustLookup("rsc.io/sampler", "v1.3.0", "rsc.io/sampler v1.3.0 h1:7uVkIFmeBqHfdjD+gZwtXXI+RODJ2Wc4O7MPEh/QiW4=")
+	tc.newClient()
+	tc.mustLookup("rsc.io/sampler", "v1.3.0", "rsc.io/sampler v1.3.0 h1:7uVkIFmeBqHfdjD+gZwtXXI+RODJ2Wc4O7MPEh/QiW4=")
+
+	// A new lookup advances the latest tree.
+	tc.wantRemote = true
+	tc.mustLookup("rsc.io/quote", "v1.5.2", "rsc.io/quote v1.5.2 h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=")
+	tc.mustHaveLatest(2)
+
+	// Unknown modules are reported by the server.
+	if _, err := tc.client.Lookup("rsc.io/nonexist", "v1.0.0"); err == nil {
+		t.Errorf("Lookup(rsc.io/nonexist): succeeded, want error")
+	}
+}
+
+func TestClientGONOSUMDB(t *testing.T) {
+	tc := newTestClient(t)
+	tc.client.SetGONOSUMDB("p,*/q")
+	tc.client.Lookup("rsc.io/sampler", "v1.3.0") // initialize before we turn off network
+	tc.wantRemote = false
+
+	var skips = []string{
+		"p",
+		"p/x",
+		"x/q",
+		"x/q/z",
+	}
+	var noskips = []string{
+		"pp",
+		"q",
+		"x/p",
+		"x/p/q",
+		"x/y/q",
+	}
+
+	for _, path := range skips {
+		_, err := tc.client.Lookup(path, "v1.0.0")
+		if err != ErrGONOSUMDB {
+			t.Errorf("Lookup(%q): %v, wanted ErrGONOSUMDB", path, err)
+		}
+	}
+	for _, path := range noskips {
+		_, err := tc.client.Lookup(path, "v1.0.0")
+		if err == ErrGONOSUMDB {
+			t.Errorf("Lookup(%q): ErrGONOSUMDB, wanted failed actual lookup", path)
+		}
+	}
+}
+
+func TestClientFork(t *testing.T) {
+	tc := newTestClient(t)
+	tc2 := tc.fork()
+
+	tc.addRecord("rsc.io/pkg1@v1.5.2", `rsc.io/pkg1 v1.5.2 h1:hash`)
+	tc.addRecord("rsc.io/pkg2@v1.5.2", `rsc.io/pkg2 v1.5.2 h1:hash`)
+	tc.mustLookup("rsc.io/pkg1", "v1.5.2", "rsc.io/pkg1 v1.5.2 h1:hash")
+
+	// The forked server presents a different history for pkg1.
+	tc2.addRecord("rsc.io/pkg1@v1.5.2", `rsc.io/pkg1 v1.5.2 h1:otherhash`)
+	tc2.addRecord("rsc.io/pkg2@v1.5.2", `rsc.io/pkg2 v1.5.2 h1:hash`)
+	tc2.addRecord("rsc.io/pkg3@v1.5.2", `rsc.io/pkg3 v1.5.2 h1:hash`)
+
+	// A client sharing tc's configuration (and cache)
+	// but talking to the forked server must detect the fork.
+	tc2.config = tc.config
+	tc2.cache = tc.cache
+	tc2.newClient()
+	_, err := tc2.client.Lookup("rsc.io/pkg3", "v1.5.2")
+	tc2.mustError(err, ErrSecurity.Error())
+
+	buf := tc2.security.String()
+	if len(buf) == 0 {
+		t.Fatalf("did not detect misbehavior")
+	}
+	for _, want := range []string{
+		"SECURITY ERROR\ngo.sum database server misbehavior detected!",
+		"old database:\n\tgo.sum database tree\n\t2\n",
+		"new database:\n\tgo.sum database tree\n\t3\n",
+		"proof of misbehavior:",
+	} {
+		if !strings.Contains(buf, want) {
+			t.Errorf("security error report missing %q:\n%s", want, buf)
+		}
+	}
+}
+
+// A testClient is a self-contained client-side testing environment.
+type testClient struct {
+	t          *testing.T
+	client     *Client
+	server     *TestServer
+	handler    http.Handler
+	wantRemote bool
+
+	mu       sync.Mutex
+	records  map[string]string // gosum records, keyed by path@vers
+	config   map[string][]byte
+	cache    map[string][]byte
+	security bytes.Buffer
+}
+
+func newTestClient(t *testing.T) *testClient {
+	tc := &testClient{
+		t:          t,
+		wantRemote: true,
+		config:     map[string][]byte{"key": []byte(testVerifierKey)},
+		cache:      make(map[string][]byte),
+		records: map[string]string{
+			"rsc.io/sampler@v1.3.0": "rsc.io/sampler v1.3.0 h1:7uVkIFmeBqHfdjD+gZwtXXI+RODJ2Wc4O7MPEh/QiW4=\n" +
+				"rsc.io/sampler v1.3.0/go.mod h1:T1hPZKmBbMNahiBKFy5HrXp6adAjACjK9JXDnKaTXpA=\n",
+			"rsc.io/quote@v1.5.2": "rsc.io/quote v1.5.2 h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=\n" +
+				"rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=\n",
+		},
+	}
+	tc.server = NewTestServer(testSignerKey, tc.gosum)
+	tc.handler = NewServer(tc.server)
+	tc.newClient()
+	return tc
+}
+
+// fork returns a new testClient with its own server
+// holding a copy of the records known to tc, but no tree.
+func (tc *testClient) fork() *testClient {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc2 := &testClient{
+		t:          tc.t,
+		wantRemote: true,
+		config:     map[string][]byte{"key": []byte(testVerifierKey)},
+		cache:      make(map[string][]byte),
+		records:    make(map[string]string),
+	}
+	tc2.server = NewTestServer(testSignerKey, tc2.gosum)
+	tc2.handler = NewServer(tc2.server)
+	tc2.newClient()
+	return tc2
+}
+
+// newClient resets tc.client to a fresh Client using tc's
+// configuration and cache.
+func (tc *testClient) newClient() {
+	tc.client = NewClient(tc)
+	tc.client.SetTileHeight(2)
+}
+
+// addRecord adds a gosum record to the server and looks it up,
+// so that it becomes part of the server's tree.
+func (tc *testClient) addRecord(key, lines string) {
+	tc.mu.Lock()
+	tc.records[key] = lines + "\n"
+	tc.mu.Unlock()
+	i := strings.Index(key, "@")
+	if _, err := tc.server.Lookup(context.Background(), module.Version{Path: key[:i], Version: key[i+1:]}); err != nil {
+		tc.t.Fatal(err)
+	}
+}
+
+func (tc *testClient) gosum(path, vers string) ([]byte, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	data, ok := tc.records[path+"@"+vers]
+	if !ok {
+		return nil, fmt.Errorf("no such module %s@%s", path, vers)
+	}
+	return []byte(data), nil
+}
+
+// mustHaveLatest checks that the on-disk configuration
+// for latest is a tree of size n.
+func (tc *testClient) mustHaveLatest(n int64) {
+	tc.t.Helper()
+
+	latest := tc.config[testName+"/latest"]
+	lines := strings.Split(string(latest), "\n")
+	if len(lines) < 2 || lines[1] != fmt.Sprint(n) {
+		tc.t.Fatalf("/latest should have tree %d, but has:\n%s", n, latest)
+	}
+}
+
+// mustLookup calls tc.client.Lookup(path, vers) and checks that
+// it returns exactly the single line want.
+func (tc *testClient) mustLookup(path, vers, want string) {
+	tc.t.Helper()
+	lines, err := tc.client.Lookup(path, vers)
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+	if strings.Join(lines, "\n") != want {
+		tc.t.Fatalf("Lookup(%q, %q):\n\t%s\nwant:\n\t%s", path, vers, strings.Join(lines, "\n\t"), want)
+	}
+}
+
+// mustError checks that err's error string contains the text.
+func (tc *testClient) mustError(err error, text string) {
+	tc.t.Helper()
+	if err == nil || !strings.Contains(err.Error(), text) {
+		tc.t.Fatalf("err = %v, want %q", err, text)
+	}
+}
+
+// ReadRemote is for tc's implementation of ClientOps.
+func (tc *testClient) ReadRemote(path string) ([]byte, error) {
+	if !tc.wantRemote {
+		tc.t.Errorf("unexpected ReadRemote(%q)", path)
+		return nil, fmt.Errorf("unexpected ReadRemote")
+	}
+	req, err := http.NewRequest("GET", "http://localhost.localdev"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	w := httptest.NewRecorder()
+	tc.handler.ServeHTTP(w, req)
+	if w.Code != 200 {
+		return nil, fmt.Errorf("GET %s: %d %s", path, w.Code, bytes.TrimSpace(w.Body.Bytes()))
+	}
+	return w.Body.Bytes(), nil
+}
+
+// ReadConfig is for tc's implementation of ClientOps.
+func (tc *testClient) ReadConfig(file string) ([]byte, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.config[file], nil
+}
+
+// WriteConfig is for tc's implementation of ClientOps.
+func (tc *testClient) WriteConfig(file string, old, new []byte) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if !bytes.Equal(tc.config[file], old) {
+		return ErrWriteConflict
+	}
+	tc.config[file] = new
+	return nil
+}
+
+// ReadCache is for tc's implementation of ClientOps.
+func (tc *testClient) ReadCache(file string) ([]byte, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if data, ok := tc.cache[file]; ok {
+		return data, nil
+	}
+	return nil, fmt.Errorf("no cache for %s", file)
+}
+
+// WriteCache is for tc's implementation of ClientOps.
+func (tc *testClient) WriteCache(file string, data []byte) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.cache[file] = data
+}
+
+// Log is for tc's implementation of ClientOps.
+func (tc *testClient) Log(msg string) {
+	tc.t.Log(msg)
+}
+
+// SecurityError is for tc's implementation of ClientOps.
+func (tc *testClient) SecurityError(msg string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.security.WriteString(msg)
+}
+
+func TestTestKeys(t *testing.T) {
+	// Make sure the constants above are a matching key pair.
+	signer, err := note.NewSigner(testSignerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := note.NewVerifier(testVerifierKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := note.Sign(&note.Note{Text: string(tlog.FormatTree(tlog.Tree{}))}, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := note.Open(msg, note.VerifierList(verifier)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -58,8 +58,8 @@ followed by detailed output for each failed package.
 
 'Go test' recompiles each package along with any files with names matching
 the file pattern "*_test.go".
-These additional files can contain test functions, benchmark functions, and
-example functions. See 'go help testfunc' for more.
+These additional files can contain test functions, benchmark functions, fuzz
+targets, and example functions. See 'go help testfunc' for more.
 Each listed package causes the execution of a separate test binary.
 Files whose names begin with "_" (including "_test.go") or "." are ignored.
 
@@ -236,6 +236,25 @@ control the execution of any test:
 	-failfast
 	    Do not start new tests after the first test failure.
 
+	-fuzz regexp
+	    Run the fuzz target matching the regular expression. When specified,
+	    the command line argument must match exactly one package, and regexp
+	    must match exactly one fuzz target within that package. After tests,
+	    benchmarks, seed corpora of other fuzz targets, and examples have
+	    completed, the matching target will be fuzzed. See the Fuzzing
+	    section of the testing package documentation for details.
+
+	-fuzzminimizetime t
+	    Run enough iterations of the fuzz target during each minimization
+	    attempt to take t, as specified as a time.Duration (for example,
+	    -fuzzminimizetime 30s). The default is 60s.
+
+	-fuzztime t
+	    Run enough iterations of the fuzz target to take t, specified as a
+	    time.Duration (for example, -fuzztime 1h30s). The default is to run
+	    forever. The special syntax Nx means to run the fuzz target N times
+	    (for example, -fuzztime 1000x).
+
 	-list regexp
 	    List tests, benchmarks, or examples matching the regular expression.
 	    No tests, benchmarks or examples will be run. This will only
@@ -417,6 +436,16 @@ A benchmark function is one named BenchmarkXxx and should have the signature,
 
 	func BenchmarkXxx(b *testing.B) { ... }
 
+A fuzz target is one named FuzzXxx and should have the signature,
+
+	func FuzzXxx(f *testing.F) { ... }
+
+When 'go test' is run without -fuzz, each fuzz target runs its seed corpus,
+made up of the values passed to f.Add and the files stored in
+testdata/fuzz/FuzzXxx, as ordinary tests. With -fuzz, the fuzz target is
+run with generated inputs until it fails, and the failing input is written
+to testdata/fuzz/FuzzXxx so that later runs of 'go test' reproduce it.
+
 An example function is similar to a test function but, instead of using
 *testing.T to report success or failure, prints output to os.Stdout.
 If the last comment in the function starts with "Output:" then the output
@@ -469,6 +498,7 @@ var (
 	testCoverPaths   []string        // -coverpkg flag
 	testCoverPkgs    []*load.Package // -coverpkg flag
 	testCoverProfile string          // -coverprofile flag
+	testFuzz         string          // -fuzz flag
 	testOutputDir    string          // -outputdir flag
 	testO            string          // -o flag
 	testProfile      string          // profiling flag that limits test to one package
@@ -488,6 +518,18 @@ var (
 	testCacheExpire time.Time // ignore cached test results before this time
 )
 
+// skipFuzzInstrument reports whether the standard library package
+// with the given import path should not be instrumented for fuzzing.
+func skipFuzzInstrument(path string) bool {
+	switch path {
+	case "context", "internal/bytealg", "internal/cpu", "internal/fuzz",
+		"reflect", "runtime", "sync", "sync/atomic", "syscall",
+		"testing", "time", "unsafe":
+		return true
+	}
+	return strings.HasPrefix(path, "runtime/") || strings.HasPrefix(path, "testing/")
+}
+
 // testVetFlags is the list of flags to pass to vet when invoked automatically during go test.
 var testVetFlags = []string{
 	// TODO(rsc): Decide which tests are enabled by default.
@@ -538,6 +580,12 @@ func runTest(cmd *base.Command, args []string) {
 	if testProfile != "" && len(pkgs) != 1 {
 		base.Fatalf("cannot use %s flag with multiple packages", testProfile)
 	}
+	if testFuzz != "" && len(pkgs) != 1 {
+		base.Fatalf("cannot use -fuzz flag with multiple packages")
+	}
+	if testFuzz != "" && cfg.BuildToolchainName == "gccgo" {
+		base.Fatalf("cannot use -fuzz flag with gccgo")
+	}
 	initCoverProfile()
 	defer closeCoverProfile()
 
@@ -552,6 +600,11 @@ func runTest(cmd *base.Command, args []string) {
 		// Let it have one century (almost) before we kill it.
 		testKillTimeout = 100 * 365 * 24 * time.Hour
 	}
+	// Fuzzing runs until it finds a failure or reaches -fuzztime,
+	// so don't kill it.
+	if testFuzz != "" {
+		testKillTimeout = 100 * 365 * 24 * time.Hour
+	}
 
 	// show passing test output (after buffering) with -v flag.
 	// must buffer because tests are running in parallel, and
@@ -695,6 +748,26 @@ func runTest(cmd *base.Command, args []string) {
 		}
 	}
 
+	if testFuzz != "" {
+		// Instrument the package under test and its dependencies with
+		// coverage counters for the fuzzing engine. The packages used to
+		// run the engine itself are left alone so that the coverage
+		// reflects only the code being fuzzed.
+		for _, p := range load.TestPackageList(pkgs) {
+			if p.Standard && skipFuzzInstrument(p.ImportPath) {
+				continue
+			}
+			p.Internal.FuzzInstrument = true
+		}
+
+		// Keep interesting inputs found while fuzzing in the build cache
+		// so that later runs can pick up where this one left off.
+		if dir := cache.DefaultDir(); dir != "off" {
+			cacheDir := filepath.Join(dir, "fuzz", pkgs[0].ImportPath)
+			testArgs = append([]string{"-test.fuzzcachedir=" + cacheDir}, testArgs...)
+		}
+	}
+
 	// Prepare build + run + print actions for all packages being tested.
 	for _, p := range pkgs {
 		// sync/atomic import is inserted by the cover tool. See #18486
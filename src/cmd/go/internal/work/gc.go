@@ -7,6 +7,7 @@ package work
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -115,6 +116,9 @@ func (gcToolchain) gc(b *Builder, a *Action, archive string, importcfg []byte, s
 			}
 		}
 	}
+	if p.Internal.FuzzInstrument {
+		gcflags = append(gcflags, fuzzInstrumentFlags...)
+	}
 
 	args := []interface{}{cfg.BuildToolexec, base.Tool("compile"), "-o", ofile, "-trimpath", trimDir(a.Objdir), gcflags, gcargs, "-D", p.Internal.LocalPrefix}
 	if importcfg != nil {
@@ -123,6 +127,16 @@ func (gcToolchain) gc(b *Builder, a *Action, archive string, importcfg []byte, s
 		}
 		args = append(args, "-importcfg", objdir+"importcfg")
 	}
+	if p.Internal.Embed != nil {
+		embedcfg, err := embedConfig(p)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := b.writeFile(objdir+"embedcfg", embedcfg); err != nil {
+			return "", nil, err
+		}
+		args = append(args, "-embedcfg", objdir+"embedcfg")
+	}
 	if ofile == archive {
 		args = append(args, "-pack")
 	}
@@ -143,6 +157,32 @@ func (gcToolchain) gc(b *Builder, a *Action, archive string, importcfg []byte, s
 	return ofile, output, err
 }
 
+// embedConfig returns the content of the -embedcfg file for package p,
+// mapping each //go:embed pattern to the files it matches
+// and each of those files to its location on disk.
+func embedConfig(p *load.Package) ([]byte, error) {
+	var embed struct {
+		Patterns map[string][]string
+		Files    map[string]string
+	}
+	embed.Patterns = p.Internal.Embed
+	embed.Files = make(map[string]string)
+	for _, list := range p.Internal.Embed {
+		for _, file := range list {
+			embed.Files[file] = mkAbs(p.Dir, filepath.FromSlash(file))
+		}
+	}
+	js, err := json.MarshalIndent(&embed, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("marshal embedcfg: %v", err)
+	}
+	return js, nil
+}
+
+// fuzzInstrumentFlags are the compiler flags that insert the coverage
+// counters used by the fuzzing engine. See cmd/go/internal/test.
+var fuzzInstrumentFlags = []string{"-d=libfuzzer"}
+
 // gcBackendConcurrency returns the backend compiler concurrency level for a package compilation.
 func gcBackendConcurrency(gcflags []string) int {
 	// First, check whether we can use -c at all for this compilation.
@@ -71,6 +71,7 @@ func init() {
 		help.HelpImportPath,
 		modload.HelpModules,
 		modget.HelpModuleGet,
+		modfetch.HelpPrivate,
 		help.HelpPackages,
 		test.HelpTestflag,
 		test.HelpTestfunc,
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net"
@@ -20,11 +21,13 @@ import (
 	"sync"
 	"testing"
 
+	"cmd/go/internal/dirhash"
 	"cmd/go/internal/modfetch"
 	"cmd/go/internal/modfetch/codehost"
 	"cmd/go/internal/module"
 	"cmd/go/internal/par"
 	"cmd/go/internal/semver"
+	"cmd/go/internal/sumweb"
 	"cmd/go/internal/txtar"
 )
 
@@ -94,7 +97,19 @@ func readModList() {
 	}
 }
 
-var zipCache par.Cache
+const (
+	testSumDBName        = "localhost.localdev/sumdb"
+	testSumDBVerifierKey = "localhost.localdev/sumdb+5166803f+AU54bm/1qZs2MryS5Ek49lYlOc9si1a0r6mHaABASY4R"
+	testSumDBSignerKey   = "PRIVATE+KEY+localhost.localdev/sumdb+5166803f+AU9vZJjk0jBFXAqEr/OvMYJ9/ug9KewzQKTtT0W1Fcp6"
+)
+
+var (
+	sumdbOps    = sumweb.NewTestServer(testSumDBSignerKey, proxyGoSum)
+	sumdbServer = sumweb.NewServer(sumdbOps)
+
+	sumdbWrongOps    = sumweb.NewTestServer(testSumDBSignerKey, proxyGoSumWrong)
+	sumdbWrongServer = sumweb.NewServer(sumdbWrongOps)
+)
 
 // proxyHandler serves the Go module proxy protocol.
 // See the proxy section of https://research.swtch.com/vgo-module.
@@ -104,6 +119,29 @@ func proxyHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	path := strings.TrimPrefix(r.URL.Path, "/mod/")
+
+	// Request for $GOPROXY/sumdb/<name>/supported
+	// is checking whether it's OK to access sumdb via the proxy.
+	if path == "sumdb/"+testSumDBName+"/supported" {
+		w.WriteHeader(200)
+		return
+	}
+
+	// Request for $GOPROXY/sumdb/<name>/... goes to sumdb.
+	if sumdbPrefix := "sumdb/" + testSumDBName + "/"; strings.HasPrefix(path, sumdbPrefix) {
+		r.URL.Path = path[len(sumdbPrefix)-1:]
+		sumdbServer.ServeHTTP(w, r)
+		return
+	}
+
+	// Request for $GOPROXY/sumdb-wrong/... goes to a database
+	// that reports wrong hashes for every module.
+	if strings.HasPrefix(path, "sumdb-wrong/") {
+		r.URL.Path = path[len("sumdb-wrong"):]
+		sumdbWrongServer.ServeHTTP(w, r)
+		return
+	}
+
 	i := strings.Index(path, "/@v/")
 	if i < 0 {
 		http.NotFound(w, r)
@@ -186,47 +224,103 @@ func proxyHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 	case "zip":
-		type cached struct {
-			zip []byte
-			err error
+		data, err := archiveZip(path, vers, a)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go proxy: %v\n", err)
+			http.Error(w, err.Error(), 500)
+			return
 		}
-		c := zipCache.Do(a, func() interface{} {
-			var buf bytes.Buffer
-			z := zip.NewWriter(&buf)
-			for _, f := range a.Files {
-				if strings.HasPrefix(f.Name, ".") {
-					continue
-				}
-				var zipName string
-				if strings.HasPrefix(f.Name, "/") {
-					zipName = f.Name[1:]
-				} else {
-					zipName = path + "@" + vers + "/" + f.Name
-				}
-				zf, err := z.Create(zipName)
-				if err != nil {
-					return cached{nil, err}
-				}
-				if _, err := zf.Write(f.Data); err != nil {
-					return cached{nil, err}
-				}
+		w.Write(data)
+		return
+
+	}
+	http.NotFound(w, r)
+}
+
+var zipCache par.Cache
+
+// archiveZip returns the module zip file for path@vers,
+// built from the files in the archive a.
+func archiveZip(path, vers string, a *txtar.Archive) ([]byte, error) {
+	type cached struct {
+		zip []byte
+		err error
+	}
+	c := zipCache.Do(a, func() interface{} {
+		var buf bytes.Buffer
+		z := zip.NewWriter(&buf)
+		for _, f := range a.Files {
+			if strings.HasPrefix(f.Name, ".") {
+				continue
 			}
-			if err := z.Close(); err != nil {
+			var zipName string
+			if strings.HasPrefix(f.Name, "/") {
+				zipName = f.Name[1:]
+			} else {
+				zipName = path + "@" + vers + "/" + f.Name
+			}
+			zf, err := z.Create(zipName)
+			if err != nil {
+				return cached{nil, err}
+			}
+			if _, err := zf.Write(f.Data); err != nil {
 				return cached{nil, err}
 			}
-			return cached{buf.Bytes(), nil}
-		}).(cached)
-
-		if c.err != nil {
-			fmt.Fprintf(os.Stderr, "go proxy: %v\n", c.err)
-			http.Error(w, c.err.Error(), 500)
-			return
 		}
-		w.Write(c.zip)
-		return
+		if err := z.Close(); err != nil {
+			return cached{nil, err}
+		}
+		return cached{buf.Bytes(), nil}
+	}).(cached)
+	return c.zip, c.err
+}
 
+// proxyGoSum returns the two go.sum lines for path@vers,
+// for use by the test checksum database.
+func proxyGoSum(path, vers string) ([]byte, error) {
+	a := readArchive(path, vers)
+	if a == nil {
+		return nil, fmt.Errorf("not found: %s@%s", path, vers)
 	}
-	http.NotFound(w, r)
+	var names []string
+	files := make(map[string][]byte)
+	var gomod []byte
+	for _, f := range a.Files {
+		if strings.HasPrefix(f.Name, ".") {
+			if f.Name == ".mod" {
+				gomod = f.Data
+			}
+			continue
+		}
+		name := path + "@" + vers + "/" + f.Name
+		if strings.HasPrefix(f.Name, "/") {
+			name = f.Name[1:]
+		}
+		names = append(names, name)
+		files[name] = f.Data
+	}
+	h1, err := dirhash.Hash1(names, func(name string) (io.ReadCloser, error) {
+		data := files[name]
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	h1mod, err := dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(gomod)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := []byte(fmt.Sprintf("%s %s %s\n%s %s/go.mod %s\n", path, vers, h1, path, vers, h1mod))
+	return data, nil
+}
+
+// proxyGoSumWrong returns go.sum lines for path@vers
+// that do not match the module's content.
+func proxyGoSumWrong(path, vers string) ([]byte, error) {
+	data := []byte(fmt.Sprintf("%s %s %s\n%s %s/go.mod %s\n", path, vers, "h1:wrong", path, vers, "h1:wrong"))
+	return data, nil
 }
 
 func findHash(m module.Version) string {
@@ -1391,6 +1391,28 @@ func (ctxt *Link) dodata() {
 		datsize += s.Size
 	}
 
+	// Coverage counters inserted by the compiler for fuzzing
+	// (-d=libfuzzer) are laid out contiguously at the end of .noptrbss.
+	// The fuzzing engine finds them between internal/fuzz._counters
+	// and internal/fuzz._ecounters, which are placed around them here
+	// even when there are no counters, so that the range is empty.
+	counters := ctxt.Syms.ROLookup("internal/fuzz._counters", 0)
+	if counters != nil && counters.Attr.Reachable() {
+		counters.Sect = sect
+		counters.Value = int64(uint64(datsize) - sect.Vaddr)
+	}
+	for _, s := range data[sym.SLIBFUZZER_EXTRA_COUNTER] {
+		datsize = aligndatsize(datsize, s)
+		s.Sect = sect
+		s.Value = int64(uint64(datsize) - sect.Vaddr)
+		datsize += s.Size
+	}
+	ecounters := ctxt.Syms.ROLookup("internal/fuzz._ecounters", 0)
+	if ecounters != nil && ecounters.Attr.Reachable() {
+		ecounters.Sect = sect
+		ecounters.Value = int64(uint64(datsize) - sect.Vaddr)
+	}
+
 	sect.Length = uint64(datsize) - sect.Vaddr
 	ctxt.Syms.Lookup("runtime.end", 0).Sect = sect
 	checkdatsize(ctxt, datsize, sym.SNOPTRBSS)
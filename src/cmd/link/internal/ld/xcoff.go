@@ -938,7 +938,7 @@ func Xcoffadddynrel(ctxt *Link, s *sym.Symbol, r *sym.Reloc) bool {
 			case &Segrodata:
 				ldr.symndx = 0 // .text
 			case &Segdata:
-				if r.Sym.Type == sym.SBSS || r.Sym.Type == sym.SNOPTRBSS {
+				if r.Sym.Type == sym.SBSS || r.Sym.Type == sym.SNOPTRBSS || r.Sym.Type == sym.SLIBFUZZER_EXTRA_COUNTER {
 					ldr.symndx = 2 // .bss
 				} else {
 					ldr.symndx = 1 // .data
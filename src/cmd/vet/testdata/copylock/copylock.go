@@ -1,6 +1,9 @@
 package copylock
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 func BadFunc() {
 	var x *sync.Mutex
@@ -9,3 +12,12 @@ func BadFunc() {
 	p = &y
 	*p = *x // ERROR "assignment copies lock value to \*p: sync.Mutex"
 }
+
+func BadAtomic() {
+	var x atomic.Int64
+	y := x // ERROR "assignment copies lock value to y: sync/atomic.Int64 contains sync/atomic.noCopy"
+	var b atomic.Bool
+	c := b // ERROR "assignment copies lock value to c: sync/atomic.Bool contains sync/atomic.noCopy"
+	y.Load()
+	c.Load()
+}
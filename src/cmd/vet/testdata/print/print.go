@@ -7,6 +7,7 @@
 package print
 
 import (
+	"errors"
 	"fmt"
 	logpkg "log" // renamed to make it harder to see
 	"math"
@@ -247,6 +248,14 @@ func PrintfTests() {
 	Errorf(1, "%d", 3)    // OK
 	Errorf(1, "%d", "hi") // ERROR "Errorf format %d has arg \x22hi\x22 of wrong type string"
 
+	// Error wrapping
+	err := errors.New("oops")
+	_ = fmt.Errorf("wrapped: %w", err)            // OK
+	_ = fmt.Errorf("wrapped: %w", "not an error") // ERROR "Errorf format %w has arg \x22not an error\x22 of wrong type string"
+	_ = fmt.Errorf("wrapped: %w, %w", err, err)   // ERROR "Errorf call has more than one error-wrapping directive %w"
+	fmt.Printf("wrapped: %w", err)                // ERROR "Printf does not support error-wrapping directive %w"
+	_ = fmt.Sprintf("wrapped: %w", err)           // ERROR "Sprintf does not support error-wrapping directive %w"
+
 	// Multiple string arguments before variadic args
 	errorf("WARNING", "foobar")            // OK
 	errorf("INFO", "s=%s, n=%d", "foo", 1) // OK
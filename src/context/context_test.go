@@ -648,3 +648,329 @@ func XTestDeadlineExceededSupportsTimeout(t testingT) {
 		t.Fatal("wrong value for timeout")
 	}
 }
+
+func XTestCause(t testingT) {
+	var (
+		forever       = 1e6 * time.Second
+		parentCause   = fmt.Errorf("parentCause")
+		childCause    = fmt.Errorf("childCause")
+		timeoutCause  = fmt.Errorf("timeoutCause")
+		deadlineCause = fmt.Errorf("deadlineCause")
+	)
+	for _, test := range []struct {
+		name  string
+		ctx   func() Context
+		err   error
+		cause error
+	}{
+		{
+			name: "Background",
+			ctx:  Background,
+		},
+		{
+			name: "WithCancel",
+			ctx: func() Context {
+				ctx, cancel := WithCancel(Background())
+				cancel()
+				return ctx
+			},
+			err:   Canceled,
+			cause: Canceled,
+		},
+		{
+			name: "WithCancelCause",
+			ctx: func() Context {
+				ctx, cancel := WithCancelCause(Background())
+				cancel(parentCause)
+				return ctx
+			},
+			err:   Canceled,
+			cause: parentCause,
+		},
+		{
+			name: "WithCancelCause nil",
+			ctx: func() Context {
+				ctx, cancel := WithCancelCause(Background())
+				cancel(nil)
+				return ctx
+			},
+			err:   Canceled,
+			cause: Canceled,
+		},
+		{
+			name: "WithCancelCause canceled twice",
+			ctx: func() Context {
+				ctx, cancel := WithCancelCause(Background())
+				cancel(parentCause)
+				cancel(childCause)
+				return ctx
+			},
+			err:   Canceled,
+			cause: parentCause,
+		},
+		{
+			name: "parent of WithCancelCause",
+			ctx: func() Context {
+				parent, cancelParent := WithCancelCause(Background())
+				_, cancelChild := WithCancelCause(parent)
+				cancelParent(parentCause)
+				cancelChild(childCause)
+				return parent
+			},
+			err:   Canceled,
+			cause: parentCause,
+		},
+		{
+			name: "parent canceled before child",
+			ctx: func() Context {
+				parent, cancelParent := WithCancelCause(Background())
+				child, cancelChild := WithCancelCause(parent)
+				cancelParent(parentCause)
+				cancelChild(childCause)
+				return child
+			},
+			err:   Canceled,
+			cause: parentCause,
+		},
+		{
+			name: "child canceled before parent",
+			ctx: func() Context {
+				parent, cancelParent := WithCancelCause(Background())
+				child, cancelChild := WithCancelCause(parent)
+				cancelChild(childCause)
+				cancelParent(parentCause)
+				return child
+			},
+			err:   Canceled,
+			cause: childCause,
+		},
+		{
+			name: "WithValue child of canceled parent",
+			ctx: func() Context {
+				parent, cancel := WithCancelCause(Background())
+				child := WithValue(parent, "key", "value")
+				cancel(parentCause)
+				return child
+			},
+			err:   Canceled,
+			cause: parentCause,
+		},
+		{
+			name: "WithTimeout",
+			ctx: func() Context {
+				ctx, cancel := WithTimeout(Background(), 0)
+				cancel()
+				return ctx
+			},
+			err:   DeadlineExceeded,
+			cause: DeadlineExceeded,
+		},
+		{
+			name: "WithTimeoutCause",
+			ctx: func() Context {
+				ctx, cancel := WithTimeoutCause(Background(), 0, timeoutCause)
+				cancel()
+				return ctx
+			},
+			err:   DeadlineExceeded,
+			cause: timeoutCause,
+		},
+		{
+			name: "WithTimeoutCause canceled",
+			ctx: func() Context {
+				ctx, cancel := WithTimeoutCause(Background(), forever, timeoutCause)
+				cancel()
+				return ctx
+			},
+			err:   Canceled,
+			cause: Canceled,
+		},
+		{
+			name: "WithTimeout child of WithCancelCause",
+			ctx: func() Context {
+				parent, cancelParent := WithCancelCause(Background())
+				child, cancelChild := WithTimeout(parent, forever)
+				defer cancelChild()
+				cancelParent(parentCause)
+				return child
+			},
+			err:   Canceled,
+			cause: parentCause,
+		},
+		{
+			name: "WithDeadlineCause",
+			ctx: func() Context {
+				ctx, cancel := WithDeadlineCause(Background(), time.Now(), deadlineCause)
+				cancel()
+				return ctx
+			},
+			err:   DeadlineExceeded,
+			cause: deadlineCause,
+		},
+		{
+			name: "WithCancelCause child of otherContext",
+			ctx: func() Context {
+				parent, cancelParent := WithCancelCause(Background())
+				child, cancelChild := WithCancelCause(otherContext{parent})
+				defer cancelChild(childCause)
+				cancelParent(parentCause)
+				select {
+				case <-child.Done():
+				case <-time.After(5 * time.Second):
+				}
+				return child
+			},
+			err:   Canceled,
+			cause: parentCause,
+		},
+	} {
+		ctx := test.ctx()
+		if got, want := ctx.Err(), test.err; want != got {
+			t.Errorf("%s: ctx.Err() = %v want %v", test.name, got, want)
+		}
+		if got, want := Cause(ctx), test.cause; want != got {
+			t.Errorf("%s: Cause(ctx) = %v want %v", test.name, got, want)
+		}
+	}
+}
+
+func XTestCauseTimeout(t testingT) {
+	cause := fmt.Errorf("timeoutCause")
+	ctx, cancel := WithTimeoutCause(Background(), 10*time.Millisecond, cause)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for Done")
+	}
+	if got, want := ctx.Err(), DeadlineExceeded; got != want {
+		t.Errorf("ctx.Err() = %v want %v", got, want)
+	}
+	if got := Cause(ctx); got != cause {
+		t.Errorf("Cause(ctx) = %v want %v", got, cause)
+	}
+}
+
+func XTestAfterFuncCalledAfterCancel(t testingT) {
+	ctx, cancel := WithCancel(Background())
+	donec := make(chan struct{})
+	stop := AfterFunc(ctx, func() {
+		close(donec)
+	})
+	select {
+	case <-donec:
+		t.Fatalf("AfterFunc called before context is done")
+	case <-time.After(10 * time.Millisecond):
+	}
+	cancel()
+	select {
+	case <-donec:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("AfterFunc not called after context is canceled")
+	}
+	if stop() {
+		t.Fatalf("stop() = true, want false")
+	}
+}
+
+func XTestAfterFuncCalledAfterTimeout(t testingT) {
+	ctx, cancel := WithTimeout(Background(), 5*time.Millisecond)
+	defer cancel()
+	donec := make(chan struct{})
+	AfterFunc(ctx, func() {
+		close(donec)
+	})
+	select {
+	case <-donec:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("AfterFunc not called after context is canceled")
+	}
+}
+
+func XTestAfterFuncCalledImmediately(t testingT) {
+	ctx, cancel := WithCancel(Background())
+	cancel()
+	donec := make(chan struct{})
+	AfterFunc(ctx, func() {
+		close(donec)
+	})
+	select {
+	case <-donec:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("AfterFunc not called for already-canceled context")
+	}
+}
+
+func XTestAfterFuncNotCalledAfterStop(t testingT) {
+	ctx, cancel := WithCancel(Background())
+	donec := make(chan struct{})
+	stop := AfterFunc(ctx, func() {
+		close(donec)
+	})
+	if !stop() {
+		t.Fatalf("stop() = false, want true")
+	}
+	if n := len(ctx.(*cancelCtx).children); n != 0 {
+		t.Errorf("context has %d children after stop, want 0", n)
+	}
+	cancel()
+	select {
+	case <-donec:
+		t.Fatalf("AfterFunc called for stopped context")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if stop() {
+		t.Fatalf("stop() = true, want false")
+	}
+}
+
+func XTestAfterFuncOtherContext(t testingT) {
+	ctx, cancel := WithCancel(Background())
+	donec := make(chan struct{})
+	stop := AfterFunc(otherContext{ctx}, func() {
+		close(donec)
+	})
+	cancel()
+	select {
+	case <-donec:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("AfterFunc not called after context is canceled")
+	}
+	if stop() {
+		t.Fatalf("stop() = true, want false")
+	}
+}
+
+func XTestWithoutCancel(t testingT) {
+	key, value := "key", "value"
+	parent, cancel := WithCancelCause(WithValue(Background(), key, value))
+	ctx := WithoutCancel(parent)
+	if got := ctx.Value(key); got != value {
+		t.Errorf("ctx.Value(%q) = %v want %v", key, got, value)
+	}
+	if d := ctx.Done(); d != nil {
+		t.Errorf("ctx.Done() = %v want nil", d)
+	}
+	cancel(fmt.Errorf("parentCause"))
+	if err := ctx.Err(); err != nil {
+		t.Errorf("ctx.Err() = %v want nil after parent is canceled", err)
+	}
+	if err := Cause(ctx); err != nil {
+		t.Errorf("Cause(ctx) = %v want nil after parent is canceled", err)
+	}
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("ctx.Deadline() reported a deadline")
+	}
+
+	child, childCancel := WithCancel(ctx)
+	if child.Err() != nil {
+		t.Errorf("child of WithoutCancel canceled by its original parent")
+	}
+	childCancel()
+	if got, want := Cause(child), Canceled; got != want {
+		t.Errorf("Cause(child) = %v want %v", got, want)
+	}
+	if got, want := fmt.Sprint(ctx), "context.Background.WithValue("; !strings.HasPrefix(got, want) || !strings.HasSuffix(got, ".WithCancel.WithoutCancel") {
+		t.Errorf("ctx.String() = %q", got)
+	}
+}
@@ -27,3 +27,11 @@ func TestCancelRemoves(t *testing.T)                   { XTestCancelRemoves(t) }
 func TestWithCancelCanceledParent(t *testing.T)        { XTestWithCancelCanceledParent(t) }
 func TestWithValueChecksKey(t *testing.T)              { XTestWithValueChecksKey(t) }
 func TestDeadlineExceededSupportsTimeout(t *testing.T) { XTestDeadlineExceededSupportsTimeout(t) }
+func TestCause(t *testing.T)                           { XTestCause(t) }
+func TestCauseTimeout(t *testing.T)                    { XTestCauseTimeout(t) }
+func TestAfterFuncCalledAfterCancel(t *testing.T)      { XTestAfterFuncCalledAfterCancel(t) }
+func TestAfterFuncCalledAfterTimeout(t *testing.T)     { XTestAfterFuncCalledAfterTimeout(t) }
+func TestAfterFuncCalledImmediately(t *testing.T)      { XTestAfterFuncCalledImmediately(t) }
+func TestAfterFuncNotCalledAfterStop(t *testing.T)     { XTestAfterFuncNotCalledAfterStop(t) }
+func TestAfterFuncOtherContext(t *testing.T)           { XTestAfterFuncOtherContext(t) }
+func TestWithoutCancel(t *testing.T)                   { XTestWithoutCancel(t) }
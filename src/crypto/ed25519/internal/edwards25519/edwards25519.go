@@ -0,0 +1,253 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package edwards25519 implements operations in GF(2**255-19) and on an
+// Edwards curve that is isomorphic to curve25519. See
+// https://ed25519.cr.yp.to/.
+package edwards25519
+
+import "crypto/subtle"
+
+// This code operates on points of the twisted Edwards curve
+//	-x^2 + y^2 = 1 + d*x^2*y^2
+// represented in extended coordinates (X:Y:Z:T), where x = X/Z, y = Y/Z
+// and x*y = T/Z. See "Twisted Edwards Curves Revisited" by Hisil, Wong,
+// Carter and Dawson, https://eprint.iacr.org/2008/522.
+//
+// The addition and doubling formulas used below are complete for this
+// curve, so they need no special cases for the identity or for adding a
+// point to itself.
+
+var (
+	// d is the curve constant -121665/121666.
+	d FieldElement
+	// d2 is 2*d.
+	d2 FieldElement
+	// sqrtM1 is a square root of -1, 2^((p-1)/4).
+	sqrtM1 FieldElement
+	// basePoint is the generator B of the prime order subgroup, the point
+	// with y = 4/5 and positive x.
+	basePoint ExtendedGroupElement
+	// baseTable holds the multiples 0*B through 15*B of basePoint.
+	baseTable precomputedTable
+)
+
+func init() {
+	var num, den FieldElement
+	num = FieldElement{121665}
+	den = FieldElement{121666}
+	FeNeg(&num, &num)
+	FeInvert(&den, &den)
+	FeMul(&d, &num, &den)
+	FeAdd(&d2, &d, &d)
+
+	// sqrtM1 = 2^((p-1)/4) = 2^(2^253 - 5), computed as
+	// (2^((p-5)/8))^2 * 2.
+	two := FieldElement{2}
+	FePow22523(&sqrtM1, &two)
+	FeSquare(&sqrtM1, &sqrtM1)
+	FeMul(&sqrtM1, &sqrtM1, &two)
+
+	var b [32]byte
+	b[0] = 0x58
+	for i := 1; i < len(b); i++ {
+		b[i] = 0x66
+	}
+	if !basePoint.FromBytes(&b) {
+		panic("edwards25519: invalid base point encoding")
+	}
+	baseTable.init(&basePoint)
+}
+
+// ExtendedGroupElement is a point on the curve in extended coordinates.
+// The zero value is not a valid point; use Zero to set the identity.
+type ExtendedGroupElement struct {
+	X, Y, Z, T FieldElement
+}
+
+// Zero sets p to the identity element (0, 1).
+func (p *ExtendedGroupElement) Zero() {
+	FeZero(&p.X)
+	FeOne(&p.Y)
+	FeOne(&p.Z)
+	FeZero(&p.T)
+}
+
+// ToBytes sets s to the RFC 8032 encoding of p: the little-endian
+// y coordinate, with the top bit set to the sign of x.
+func (p *ExtendedGroupElement) ToBytes(s *[32]byte) {
+	var recip, x, y FieldElement
+
+	FeInvert(&recip, &p.Z)
+	FeMul(&x, &p.X, &recip)
+	FeMul(&y, &p.Y, &recip)
+	FeToBytes(s, &y)
+	s[31] ^= byte(FeIsNegative(&x) << 7)
+}
+
+// FromBytes sets p to the point encoded by s, and reports whether s
+// is a valid point encoding.
+func (p *ExtendedGroupElement) FromBytes(s *[32]byte) bool {
+	var u, v, x, y FieldElement
+
+	FeFromBytes(&y, s)
+
+	// -x^2 + y^2 = 1 + d*x^2*y^2
+	// x^2 = (y^2 - 1) / (d*y^2 + 1)
+	var one FieldElement
+	FeOne(&one)
+	FeSquare(&u, &y)
+	FeMul(&v, &u, &d)
+	FeSub(&u, &u, &one) // u = y^2 - 1
+	FeAdd(&v, &v, &one) // v = d*y^2 + 1
+
+	if feSqrtRatio(&x, &u, &v) != 1 {
+		return false
+	}
+
+	// feSqrtRatio returns the non-negative root; select the one that
+	// matches the encoded sign bit. x = 0 has no negative form.
+	sign := int(s[31] >> 7)
+	var zero FieldElement
+	if sign == 1 && FeEqual(&x, &zero) == 1 {
+		return false
+	}
+	var negX FieldElement
+	FeNeg(&negX, &x)
+	FeCMove(&x, &negX, sign)
+
+	p.X = x
+	p.Y = y
+	FeOne(&p.Z)
+	FeMul(&p.T, &x, &y)
+	return true
+}
+
+// geAdd sets r = p + q.
+func geAdd(r, p, q *ExtendedGroupElement) {
+	var a, b, c, dd, e, f, g, h, t FieldElement
+
+	FeSub(&a, &p.Y, &p.X)
+	FeSub(&t, &q.Y, &q.X)
+	FeMul(&a, &a, &t) // A = (Y1-X1)*(Y2-X2)
+	FeAdd(&b, &p.Y, &p.X)
+	FeAdd(&t, &q.Y, &q.X)
+	FeMul(&b, &b, &t) // B = (Y1+X1)*(Y2+X2)
+	FeMul(&c, &p.T, &q.T)
+	FeMul(&c, &c, &d2) // C = T1*2d*T2
+	FeMul(&dd, &p.Z, &q.Z)
+	FeAdd(&dd, &dd, &dd) // D = Z1*2*Z2
+	FeSub(&e, &b, &a)    // E = B-A
+	FeSub(&f, &dd, &c)   // F = D-C
+	FeAdd(&g, &dd, &c)   // G = D+C
+	FeAdd(&h, &b, &a)    // H = B+A
+
+	FeMul(&r.X, &e, &f)
+	FeMul(&r.Y, &g, &h)
+	FeMul(&r.T, &e, &h)
+	FeMul(&r.Z, &f, &g)
+}
+
+// geDouble sets r = 2 * p.
+func geDouble(r, p *ExtendedGroupElement) {
+	var a, b, c, e, f, g, h FieldElement
+
+	FeSquare(&a, &p.X) // A = X1^2
+	FeSquare(&b, &p.Y) // B = Y1^2
+	FeSquare(&c, &p.Z)
+	FeAdd(&c, &c, &c) // C = 2*Z1^2
+	FeAdd(&e, &p.X, &p.Y)
+	FeSquare(&e, &e)
+	FeSub(&e, &e, &a)
+	FeSub(&e, &e, &b) // E = (X1+Y1)^2 - A - B
+	FeSub(&g, &b, &a) // G = B - A
+	FeSub(&f, &g, &c) // F = G - C
+	FeAdd(&h, &a, &b)
+	FeNeg(&h, &h) // H = -A - B
+
+	FeMul(&r.X, &e, &f)
+	FeMul(&r.Y, &g, &h)
+	FeMul(&r.T, &e, &h)
+	FeMul(&r.Z, &f, &g)
+}
+
+// geCMove sets p = q if b == 1, and leaves p unchanged if b == 0.
+func geCMove(p, q *ExtendedGroupElement, b int) {
+	FeCMove(&p.X, &q.X, b)
+	FeCMove(&p.Y, &q.Y, b)
+	FeCMove(&p.Z, &q.Z, b)
+	FeCMove(&p.T, &q.T, b)
+}
+
+// A precomputedTable holds the multiples 0*P through 15*P of a point P.
+type precomputedTable [16]ExtendedGroupElement
+
+func (t *precomputedTable) init(p *ExtendedGroupElement) {
+	t[0].Zero()
+	t[1] = *p
+	for i := 2; i < len(t); i++ {
+		geAdd(&t[i], &t[i-1], p)
+	}
+}
+
+// selectInto sets r = t[i] in constant time.
+func (t *precomputedTable) selectInto(r *ExtendedGroupElement, i byte) {
+	r.Zero()
+	for j := range t {
+		geCMove(r, &t[j], subtle.ConstantTimeByteEq(byte(j), i))
+	}
+}
+
+// geScalarMult sets h = a*P, where t holds the multiples of P, and
+// a = a[0]+256*a[1]+...+256^31 a[31]. It runs in time independent of a.
+func geScalarMult(h *ExtendedGroupElement, a *[32]byte, t *precomputedTable) {
+	var q ExtendedGroupElement
+
+	h.Zero()
+	for i := len(a) - 1; i >= 0; i-- {
+		for _, nibble := range [2]byte{a[i] >> 4, a[i] & 15} {
+			geDouble(h, h)
+			geDouble(h, h)
+			geDouble(h, h)
+			geDouble(h, h)
+			t.selectInto(&q, nibble)
+			geAdd(h, h, &q)
+		}
+	}
+}
+
+// GeScalarMultBase computes h = a*B, where
+//   a = a[0]+256*a[1]+...+256^31 a[31]
+//   B is the Ed25519 base point (x,4/5) with x positive.
+func GeScalarMultBase(h *ExtendedGroupElement, a *[32]byte) {
+	geScalarMult(h, a, &baseTable)
+}
+
+// GeDoubleScalarMultVartime sets r = a*A + b*B
+// where a = a[0]+256*a[1]+...+256^31 a[31].
+// and b = b[0]+256*b[1]+...+256^31 b[31].
+// B is the Ed25519 base point (x,4/5) with x positive.
+//
+// Unlike GeScalarMultBase, its running time depends on its inputs, so it
+// must only be used with public values.
+func GeDoubleScalarMultVartime(r *ExtendedGroupElement, a *[32]byte, A *ExtendedGroupElement, b *[32]byte) {
+	var aTable precomputedTable
+	aTable.init(A)
+
+	r.Zero()
+	for i := len(a) - 1; i >= 0; i-- {
+		for _, shift := range [2]uint{4, 0} {
+			geDouble(r, r)
+			geDouble(r, r)
+			geDouble(r, r)
+			geDouble(r, r)
+			if n := (a[i] >> shift) & 15; n != 0 {
+				geAdd(r, r, &aTable[n])
+			}
+			if n := (b[i] >> shift) & 15; n != 0 {
+				geAdd(r, r, &baseTable[n])
+			}
+		}
+	}
+}
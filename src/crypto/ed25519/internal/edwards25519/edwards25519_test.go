@@ -0,0 +1,237 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+var (
+	bigL = func() *big.Int {
+		l, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+		return l
+	}()
+	bigP = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+)
+
+// leBytesToBig interprets b as a little-endian integer.
+func leBytesToBig(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// bigToLEBytes writes n as a little-endian integer to b.
+func bigToLEBytes(b []byte, n *big.Int) {
+	be := n.Bytes()
+	for i := range b {
+		b[i] = 0
+	}
+	for i := range be {
+		b[i] = be[len(be)-1-i]
+	}
+}
+
+func TestScalarOrder(t *testing.T) {
+	var want [32]byte
+	bigToLEBytes(want[:], bigL)
+	if want != order {
+		t.Errorf("order = %x, want %x", order, want)
+	}
+}
+
+func TestScReduce(t *testing.T) {
+	f := func(in [64]byte) bool {
+		var out [32]byte
+		ScReduce(&out, &in)
+		want := new(big.Int).Mod(leBytesToBig(in[:]), bigL)
+		return leBytesToBig(out[:]).Cmp(want) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	// All bits set is the largest possible input.
+	var in [64]byte
+	for i := range in {
+		in[i] = 0xff
+	}
+	if !f(in) {
+		t.Errorf("ScReduce(2^512 - 1) is wrong")
+	}
+}
+
+func TestScMulAdd(t *testing.T) {
+	f := func(a, b, c [32]byte) bool {
+		// ScMulAdd accepts arbitrary 256-bit values for b, as used for
+		// clamped secret scalars, but expects a and c to be reduced.
+		a[31] &= 0x0f
+		c[31] &= 0x0f
+		var s [32]byte
+		ScMulAdd(&s, &a, &b, &c)
+		want := new(big.Int).Mul(leBytesToBig(a[:]), leBytesToBig(b[:]))
+		want.Add(want, leBytesToBig(c[:]))
+		want.Mod(want, bigL)
+		return leBytesToBig(s[:]).Cmp(want) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScMinimal(t *testing.T) {
+	lMinusOne := new(big.Int).Sub(bigL, big.NewInt(1))
+	for _, tt := range []struct {
+		n    *big.Int
+		want bool
+	}{
+		{big.NewInt(0), true},
+		{lMinusOne, true},
+		{bigL, false},
+		{new(big.Int).Add(bigL, big.NewInt(1)), false},
+		{new(big.Int).Lsh(big.NewInt(1), 255), false},
+	} {
+		var s [32]byte
+		bigToLEBytes(s[:], tt.n)
+		if got := ScMinimal(&s); got != tt.want {
+			t.Errorf("ScMinimal(%v) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFieldMulInvert(t *testing.T) {
+	f := func(in [32]byte) bool {
+		in[31] &= 0x7f
+		var x, inv, prod FieldElement
+		FeFromBytes(&x, &in)
+		FeInvert(&inv, &x)
+		FeMul(&prod, &x, &inv)
+		var out [32]byte
+		FeToBytes(&out, &prod)
+
+		n := new(big.Int).Mod(leBytesToBig(in[:]), bigP)
+		if n.Sign() == 0 {
+			return out == [32]byte{}
+		}
+		return out == [32]byte{1}
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFieldToBytes(t *testing.T) {
+	f := func(in [32]byte) bool {
+		in[31] &= 0x7f
+		var x FieldElement
+		FeFromBytes(&x, &in)
+		var out [32]byte
+		FeToBytes(&out, &x)
+		want := new(big.Int).Mod(leBytesToBig(in[:]), bigP)
+		return leBytesToBig(out[:]).Cmp(want) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	// p and p+1 encode as 0 and 1.
+	var pBytes [32]byte
+	bigToLEBytes(pBytes[:], bigP)
+	if !f(pBytes) {
+		t.Errorf("FeToBytes(p) is not canonical")
+	}
+	pBytes[0]++
+	if !f(pBytes) {
+		t.Errorf("FeToBytes(p+1) is not canonical")
+	}
+}
+
+func TestSqrtM1(t *testing.T) {
+	var sq, minusOne, one FieldElement
+	FeSquare(&sq, &sqrtM1)
+	FeOne(&one)
+	FeNeg(&minusOne, &one)
+	if FeEqual(&sq, &minusOne) != 1 {
+		t.Errorf("sqrtM1^2 != -1")
+	}
+}
+
+func TestBasePointOrder(t *testing.T) {
+	var p ExtendedGroupElement
+	GeScalarMultBase(&p, &order)
+	var got, identity [32]byte
+	p.ToBytes(&got)
+	identity[0] = 1
+	if got != identity {
+		t.Errorf("l*B = %x, want identity", got)
+	}
+}
+
+func TestPointEncodingRoundTrip(t *testing.T) {
+	f := func(s [32]byte) bool {
+		var p, q ExtendedGroupElement
+		GeScalarMultBase(&p, &s)
+		var enc, enc2 [32]byte
+		p.ToBytes(&enc)
+		if !q.FromBytes(&enc) {
+			return false
+		}
+		q.ToBytes(&enc2)
+		return enc == enc2
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDoubleScalarMult(t *testing.T) {
+	f := func(a, b, c [32]byte) bool {
+		a[31] &= 0x0f
+		b[31] &= 0x0f
+		var A, r, want ExtendedGroupElement
+		GeScalarMultBase(&A, &c)
+
+		// a*A + b*B = (a*c + b)*B
+		var ac [32]byte
+		ScMulAdd(&ac, &a, &c, &b)
+		GeScalarMultBase(&want, &ac)
+
+		GeDoubleScalarMultVartime(&r, &a, &A, &b)
+		var got, wantBytes [32]byte
+		r.ToBytes(&got)
+		want.ToBytes(&wantBytes)
+		return bytes.Equal(got[:], wantBytes[:])
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 20}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInvalidPointEncoding(t *testing.T) {
+	// y = 2 is not on the curve: x^2 = 3/(4d+1) is not a square.
+	var s [32]byte
+	s[0] = 2
+	var p ExtendedGroupElement
+	if p.FromBytes(&s) {
+		var x [32]byte
+		FeToBytes(&x, &p.X)
+		t.Errorf("FromBytes(%x) succeeded, x = %x", s, x)
+	}
+
+	// y = 1 gives x = 0, which has no negative encoding.
+	s = [32]byte{1}
+	if !p.FromBytes(&s) {
+		t.Errorf("FromBytes of identity failed")
+	}
+	s[31] |= 0x80
+	if p.FromBytes(&s) {
+		t.Errorf("FromBytes of negative zero x succeeded")
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"crypto/subtle"
+	"math/bits"
+)
+
+// FieldElement represents an element of the field GF(2^255 - 19). An element
+// t represents the integer t[0] + t[1]*2^51 + t[2]*2^102 + t[3]*2^153 +
+// t[4]*2^204.
+//
+// Between operations, all limbs are expected to be lower than 2^52.
+type FieldElement [5]uint64
+
+const maskLow51Bits uint64 = (1 << 51) - 1
+
+// FeZero sets h = 0.
+func FeZero(h *FieldElement) {
+	*h = FieldElement{}
+}
+
+// FeOne sets h = 1.
+func FeOne(h *FieldElement) {
+	*h = FieldElement{1}
+}
+
+// FeAdd sets h = f + g.
+func FeAdd(h, f, g *FieldElement) {
+	h[0] = f[0] + g[0]
+	h[1] = f[1] + g[1]
+	h[2] = f[2] + g[2]
+	h[3] = f[3] + g[3]
+	h[4] = f[4] + g[4]
+	feCarryPropagate(h)
+}
+
+// FeSub sets h = f - g.
+func FeSub(h, f, g *FieldElement) {
+	// We first add 2 * p, to guarantee the subtraction won't underflow, and
+	// then subtract g.
+	h[0] = (f[0] + 0xFFFFFFFFFFFDA) - g[0]
+	h[1] = (f[1] + 0xFFFFFFFFFFFFE) - g[1]
+	h[2] = (f[2] + 0xFFFFFFFFFFFFE) - g[2]
+	h[3] = (f[3] + 0xFFFFFFFFFFFFE) - g[3]
+	h[4] = (f[4] + 0xFFFFFFFFFFFFE) - g[4]
+	feCarryPropagate(h)
+}
+
+// FeNeg sets h = -f.
+func FeNeg(h, f *FieldElement) {
+	var zero FieldElement
+	FeSub(h, &zero, f)
+}
+
+// feCarryPropagate brings the limbs of h below 52 bits by applying the
+// reduction identity 2^255 = 19 to the carry of the highest limb.
+func feCarryPropagate(h *FieldElement) {
+	c0 := h[0] >> 51
+	c1 := h[1] >> 51
+	c2 := h[2] >> 51
+	c3 := h[3] >> 51
+	c4 := h[4] >> 51
+
+	h[0] = h[0]&maskLow51Bits + c4*19
+	h[1] = h[1]&maskLow51Bits + c0
+	h[2] = h[2]&maskLow51Bits + c1
+	h[3] = h[3]&maskLow51Bits + c2
+	h[4] = h[4]&maskLow51Bits + c3
+}
+
+// uint128 holds a 128-bit number as two 64-bit limbs, for use in FeMul.
+type uint128 struct {
+	lo, hi uint64
+}
+
+// mul64 returns a * b.
+func mul64(a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	return uint128{lo, hi}
+}
+
+// addMul64 returns v + a * b.
+func addMul64(v uint128, a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	lo, c := bits.Add64(lo, v.lo, 0)
+	hi, _ = bits.Add64(hi, v.hi, c)
+	return uint128{lo, hi}
+}
+
+// shiftRightBy51 returns a >> 51. a is assumed to be at most 115 bits.
+func shiftRightBy51(a uint128) uint64 {
+	return (a.hi << (64 - 51)) | (a.lo >> 51)
+}
+
+// FeMul sets h = f * g.
+func FeMul(h, f, g *FieldElement) {
+	f0, f1, f2, f3, f4 := f[0], f[1], f[2], f[3], f[4]
+	g0, g1, g2, g3, g4 := g[0], g[1], g[2], g[3], g[4]
+
+	// Limbs that would end up above 2^255 wrap around multiplied by 19.
+	f1_19 := f1 * 19
+	f2_19 := f2 * 19
+	f3_19 := f3 * 19
+	f4_19 := f4 * 19
+
+	// r0 = f0×g0 + 19×(f1×g4 + f2×g3 + f3×g2 + f4×g1)
+	r0 := mul64(f0, g0)
+	r0 = addMul64(r0, f1_19, g4)
+	r0 = addMul64(r0, f2_19, g3)
+	r0 = addMul64(r0, f3_19, g2)
+	r0 = addMul64(r0, f4_19, g1)
+
+	// r1 = f0×g1 + f1×g0 + 19×(f2×g4 + f3×g3 + f4×g2)
+	r1 := mul64(f0, g1)
+	r1 = addMul64(r1, f1, g0)
+	r1 = addMul64(r1, f2_19, g4)
+	r1 = addMul64(r1, f3_19, g3)
+	r1 = addMul64(r1, f4_19, g2)
+
+	// r2 = f0×g2 + f1×g1 + f2×g0 + 19×(f3×g4 + f4×g3)
+	r2 := mul64(f0, g2)
+	r2 = addMul64(r2, f1, g1)
+	r2 = addMul64(r2, f2, g0)
+	r2 = addMul64(r2, f3_19, g4)
+	r2 = addMul64(r2, f4_19, g3)
+
+	// r3 = f0×g3 + f1×g2 + f2×g1 + f3×g0 + 19×f4×g4
+	r3 := mul64(f0, g3)
+	r3 = addMul64(r3, f1, g2)
+	r3 = addMul64(r3, f2, g1)
+	r3 = addMul64(r3, f3, g0)
+	r3 = addMul64(r3, f4_19, g4)
+
+	// r4 = f0×g4 + f1×g3 + f2×g2 + f3×g1 + f4×g0
+	r4 := mul64(f0, g4)
+	r4 = addMul64(r4, f1, g3)
+	r4 = addMul64(r4, f2, g2)
+	r4 = addMul64(r4, f3, g1)
+	r4 = addMul64(r4, f4, g0)
+
+	// Each r is at most 2^115, so the carries fit in 64 bits and the
+	// results below are at most 2^61, which feCarryPropagate brings
+	// back under 2^52.
+	c0 := shiftRightBy51(r0)
+	c1 := shiftRightBy51(r1)
+	c2 := shiftRightBy51(r2)
+	c3 := shiftRightBy51(r3)
+	c4 := shiftRightBy51(r4)
+
+	h[0] = r0.lo&maskLow51Bits + c4*19
+	h[1] = r1.lo&maskLow51Bits + c0
+	h[2] = r2.lo&maskLow51Bits + c1
+	h[3] = r3.lo&maskLow51Bits + c2
+	h[4] = r4.lo&maskLow51Bits + c3
+	feCarryPropagate(h)
+}
+
+// FeSquare sets h = f * f.
+func FeSquare(h, f *FieldElement) {
+	FeMul(h, f, f)
+}
+
+// feSquareN sets h = f^(2^n).
+func feSquareN(h, f *FieldElement, n int) {
+	FeSquare(h, f)
+	for i := 1; i < n; i++ {
+		FeSquare(h, h)
+	}
+}
+
+// fePow2250 sets z2_250_0 = z^(2^250 - 1) and z11 = z^11, the common prefix
+// of the exponentiation chains used by FeInvert and FePow22523.
+func fePow2250(z2_250_0, z11, z *FieldElement) {
+	var z2, z9, z2_5_0, z2_10_0, z2_20_0, z2_50_0, z2_100_0, t FieldElement
+
+	FeSquare(&z2, z)          // 2
+	feSquareN(&t, &z2, 2)     // 8
+	FeMul(&z9, &t, z)         // 9
+	FeMul(z11, &z9, &z2)      // 11
+	FeSquare(&t, z11)         // 22
+	FeMul(&z2_5_0, &t, &z9)   // 2^5 - 2^0 = 31
+	feSquareN(&t, &z2_5_0, 5) // 2^10 - 2^5
+	FeMul(&z2_10_0, &t, &z2_5_0)
+	feSquareN(&t, &z2_10_0, 10) // 2^20 - 2^10
+	FeMul(&z2_20_0, &t, &z2_10_0)
+	feSquareN(&t, &z2_20_0, 20) // 2^40 - 2^20
+	FeMul(&t, &t, &z2_20_0)     // 2^40 - 2^0
+	feSquareN(&t, &t, 10)       // 2^50 - 2^10
+	FeMul(&z2_50_0, &t, &z2_10_0)
+	feSquareN(&t, &z2_50_0, 50) // 2^100 - 2^50
+	FeMul(&z2_100_0, &t, &z2_50_0)
+	feSquareN(&t, &z2_100_0, 100) // 2^200 - 2^100
+	FeMul(&t, &t, &z2_100_0)      // 2^200 - 2^0
+	feSquareN(&t, &t, 50)         // 2^250 - 2^50
+	FeMul(z2_250_0, &t, &z2_50_0) // 2^250 - 2^0
+}
+
+// FeInvert sets out = z^-1 = z^(p-2). If z is zero, out is zero.
+func FeInvert(out, z *FieldElement) {
+	var z2_250_0, z11, t FieldElement
+	fePow2250(&z2_250_0, &z11, z)
+	feSquareN(&t, &z2_250_0, 5) // 2^255 - 2^5
+	FeMul(out, &t, &z11)        // 2^255 - 21
+}
+
+// FePow22523 sets out = z^((p-5)/8) = z^(2^252 - 3).
+func FePow22523(out, z *FieldElement) {
+	var z2_250_0, z11, t FieldElement
+	fePow2250(&z2_250_0, &z11, z)
+	feSquareN(&t, &z2_250_0, 2) // 2^252 - 2^2
+	FeMul(out, &t, z)           // 2^252 - 3
+}
+
+// FeFromBytes sets h to the little-endian value of s, ignoring the most
+// significant bit, as required by RFC 7748.
+func FeFromBytes(h *FieldElement, s *[32]byte) {
+	h[0] = load64(s[0:]) & maskLow51Bits
+	h[1] = (load64(s[6:]) >> 3) & maskLow51Bits
+	h[2] = (load64(s[12:]) >> 6) & maskLow51Bits
+	h[3] = (load64(s[19:]) >> 1) & maskLow51Bits
+	h[4] = (load64(s[24:]) >> 12) & maskLow51Bits
+}
+
+// FeToBytes sets s to the canonical little-endian encoding of h.
+func FeToBytes(s *[32]byte, h *FieldElement) {
+	t := *h
+	feCarryPropagate(&t)
+
+	// t is now at most 2^255 + 2^13*19, but we need t < 2^255 - 19.
+	// q is 1 if t >= 2^255 - 19, in which case we subtract p by adding 19
+	// and dropping the bit at 2^255.
+	q := (t[0] + 19) >> 51
+	q = (t[1] + q) >> 51
+	q = (t[2] + q) >> 51
+	q = (t[3] + q) >> 51
+	q = (t[4] + q) >> 51
+
+	t[0] += 19 * q
+
+	t[1] += t[0] >> 51
+	t[0] &= maskLow51Bits
+	t[2] += t[1] >> 51
+	t[1] &= maskLow51Bits
+	t[3] += t[2] >> 51
+	t[2] &= maskLow51Bits
+	t[4] += t[3] >> 51
+	t[3] &= maskLow51Bits
+	t[4] &= maskLow51Bits
+
+	*s = [32]byte{}
+	for i, l := range t {
+		off := i * 51
+		v := l << uint(off%8)
+		for j := off / 8; j < len(s) && v != 0; j++ {
+			s[j] |= byte(v)
+			v >>= 8
+		}
+	}
+}
+
+func load64(b []byte) uint64 {
+	_ = b[7] // bounds check hint to compiler; see golang.org/issue/14808
+	return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
+		uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
+}
+
+// FeIsNegative reports whether the canonical encoding of f is odd,
+// which RFC 8032 defines as f being negative. It returns 1 or 0.
+func FeIsNegative(f *FieldElement) int {
+	var s [32]byte
+	FeToBytes(&s, f)
+	return int(s[0] & 1)
+}
+
+// FeEqual returns 1 if f and g represent the same field element, and 0
+// otherwise.
+func FeEqual(f, g *FieldElement) int {
+	var s1, s2 [32]byte
+	FeToBytes(&s1, f)
+	FeToBytes(&s2, g)
+	return subtle.ConstantTimeCompare(s1[:], s2[:])
+}
+
+// FeCMove sets f = g if b == 1, and leaves f unchanged if b == 0.
+// Any other value of b results in undefined behavior.
+func FeCMove(f, g *FieldElement, b int) {
+	mask := -uint64(b)
+	f[0] ^= mask & (f[0] ^ g[0])
+	f[1] ^= mask & (f[1] ^ g[1])
+	f[2] ^= mask & (f[2] ^ g[2])
+	f[3] ^= mask & (f[3] ^ g[3])
+	f[4] ^= mask & (f[4] ^ g[4])
+}
+
+// feSqrtRatio sets r to the non-negative square root of u/v if it exists,
+// and returns 1. If u/v is not a square, it returns 0 and the value of r is
+// undefined.
+func feSqrtRatio(r, u, v *FieldElement) int {
+	var v2, v3, v7, uv3, uv7, check, negU, negUI, rPrime FieldElement
+
+	FeSquare(&v2, v)
+	FeMul(&v3, &v2, v)
+	FeSquare(&v7, &v3)
+	FeMul(&v7, &v7, v)
+	FeMul(&uv3, u, &v3)
+	FeMul(&uv7, u, &v7)
+
+	// r = (u * v^3) * (u * v^7)^((p-5)/8)
+	FePow22523(r, &uv7)
+	FeMul(r, r, &uv3)
+
+	FeSquare(&check, r)
+	FeMul(&check, &check, v)
+
+	FeNeg(&negU, u)
+	FeMul(&negUI, &negU, &sqrtM1)
+
+	correctSignSqrt := FeEqual(&check, u)
+	flippedSignSqrt := FeEqual(&check, &negU)
+	flippedSignSqrtI := FeEqual(&check, &negUI)
+
+	// If the sign was flipped, r * sqrt(-1) is the square root.
+	FeMul(&rPrime, r, &sqrtM1)
+	FeCMove(r, &rPrime, flippedSignSqrt|flippedSignSqrtI)
+
+	// Choose the non-negative square root.
+	FeNeg(&rPrime, r)
+	FeCMove(r, &rPrime, FeIsNegative(r))
+
+	return correctSignSqrt | flippedSignSqrt
+}
@@ -0,0 +1,166 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+// Scalars are little-endian integers modulo the order of the base point,
+//	l = 2^252 + 27742317777372353535851937790883648493.
+//
+// Arithmetic is done on signed 21-bit limbs, following the ref10
+// implementation from SUPERCOP. The multiples of l used for reduction are
+// folded into the limbs using
+//	2^252 = -27742317777372353535851937790883648493 mod l
+// which, split into 21-bit signed limbs, is
+//	(-666643, -470296, -654183, 997805, -136657, 683901).
+
+const limbMask = 1<<21 - 1
+
+// loadLimbs splits the little-endian integer in into 21-bit limbs. The
+// last limb holds all remaining bits.
+func loadLimbs(s []int64, in []byte) {
+	for i := range s {
+		off := 21 * i
+		var v uint64
+		for j := 0; j < 4 && off/8+j < len(in); j++ {
+			v |= uint64(in[off/8+j]) << uint(8*j)
+		}
+		v >>= uint(off % 8)
+		if i < len(s)-1 {
+			v &= limbMask
+		}
+		s[i] = int64(v)
+	}
+}
+
+// reduceLimb folds s[i] into s[i-12] through s[i-7], using the fact that
+// 2^(21*i) = 2^(21*(i-12)) * 2^252.
+func reduceLimb(s *[24]int64, i int) {
+	s[i-12] += s[i] * 666643
+	s[i-11] += s[i] * 470296
+	s[i-10] += s[i] * 654183
+	s[i-9] -= s[i] * 997805
+	s[i-8] += s[i] * 136657
+	s[i-7] -= s[i] * 683901
+	s[i] = 0
+}
+
+// carry moves the excess of s[i] above 21 bits into s[i+1], rounding so
+// that s[i] ends up in [-2^20, 2^20).
+func carry(s *[24]int64, i int) {
+	c := (s[i] + 1<<20) >> 21
+	s[i+1] += c
+	s[i] -= c << 21
+}
+
+// carryFloor is like carry, but leaves s[i] in [0, 2^21).
+func carryFloor(s *[24]int64, i int) {
+	c := s[i] >> 21
+	s[i+1] += c
+	s[i] -= c << 21
+}
+
+// reduce sets out to the value of the limbs in s modulo l. The limbs
+// must all have been carried to at most 21 bits, except for the top one.
+func reduce(out *[32]byte, s *[24]int64) {
+	for i := 23; i >= 18; i-- {
+		reduceLimb(s, i)
+	}
+	for i := 6; i <= 16; i += 2 {
+		carry(s, i)
+	}
+	for i := 7; i <= 15; i += 2 {
+		carry(s, i)
+	}
+
+	for i := 17; i >= 12; i-- {
+		reduceLimb(s, i)
+	}
+	for i := 0; i <= 10; i += 2 {
+		carry(s, i)
+	}
+	for i := 1; i <= 11; i += 2 {
+		carry(s, i)
+	}
+
+	reduceLimb(s, 12)
+	for i := 0; i <= 11; i++ {
+		carryFloor(s, i)
+	}
+
+	reduceLimb(s, 12)
+	for i := 0; i <= 10; i++ {
+		carryFloor(s, i)
+	}
+
+	// Pack the twelve 21-bit limbs into 252 bits.
+	*out = [32]byte{}
+	var acc uint64
+	var n uint
+	j := 0
+	for i := 0; i < 12; i++ {
+		acc |= uint64(s[i]) << n
+		n += 21
+		for n >= 8 {
+			out[j] = byte(acc)
+			acc >>= 8
+			n -= 8
+			j++
+		}
+	}
+	out[j] = byte(acc)
+}
+
+// ScReduce sets out = s mod l, where
+//   s = s[0]+256*s[1]+...+256^63*s[63].
+func ScReduce(out *[32]byte, s *[64]byte) {
+	var t [24]int64
+	loadLimbs(t[:], s[:])
+	reduce(out, &t)
+}
+
+// ScMulAdd sets s = (ab+c) mod l, where
+//   a = a[0]+256*a[1]+...+256^31*a[31]
+//   b = b[0]+256*b[1]+...+256^31*b[31]
+//   c = c[0]+256*c[1]+...+256^31*c[31]
+func ScMulAdd(s, a, b, c *[32]byte) {
+	var al, bl, cl [12]int64
+	loadLimbs(al[:], a[:])
+	loadLimbs(bl[:], b[:])
+	loadLimbs(cl[:], c[:])
+
+	var t [24]int64
+	copy(t[:], cl[:])
+	for i := range al {
+		for j := range bl {
+			t[i+j] += al[i] * bl[j]
+		}
+	}
+
+	for i := 0; i <= 22; i += 2 {
+		carry(&t, i)
+	}
+	for i := 1; i <= 21; i += 2 {
+		carry(&t, i)
+	}
+	reduce(s, &t)
+}
+
+// order is l in little-endian form.
+var order = [32]byte{
+	0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+	0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+}
+
+// ScMinimal reports whether the given scalar is reduced, that is,
+// whether it is less than l.
+func ScMinimal(s *[32]byte) bool {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] != order[i] {
+			return s[i] < order[i]
+		}
+	}
+	return false
+}
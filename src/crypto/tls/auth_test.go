@@ -6,12 +6,14 @@ package tls
 
 import (
 	"crypto"
+	"crypto/ed25519"
 	"testing"
 )
 
 func TestSignatureSelection(t *testing.T) {
 	rsaCert := &testRSAPrivateKey.PublicKey
 	ecdsaCert := &testECDSAPrivateKey.PublicKey
+	ed25519Cert := testEd25519PrivateKey.Public().(ed25519.PublicKey)
 	sigsPKCS1WithSHA := []SignatureScheme{PKCS1WithSHA256, PKCS1WithSHA1}
 	sigsPSSWithSHA := []SignatureScheme{PSSWithSHA256, PSSWithSHA384}
 	sigsECDSAWithSHA := []SignatureScheme{ECDSAWithP256AndSHA256, ECDSAWithSHA1}
@@ -56,6 +58,10 @@ func TestSignatureSelection(t *testing.T) {
 		// RSASSA-PSS is defined in TLS 1.3 for TLS 1.2
 		// https://tools.ietf.org/html/draft-ietf-tls-tls13-21#page-45
 		{rsaCert, []SignatureScheme{PSSWithSHA256}, sigsPSSWithSHA, VersionTLS12, PSSWithSHA256, signatureRSAPSS, crypto.SHA256},
+
+		// Ed25519 signs messages directly, without pre-hashing.
+		{ed25519Cert, []SignatureScheme{Ed25519}, []SignatureScheme{ECDSAWithP256AndSHA256, Ed25519}, VersionTLS12, Ed25519, signatureEd25519, directSigning},
+		{ed25519Cert, []SignatureScheme{ECDSAWithP256AndSHA256, Ed25519}, []SignatureScheme{Ed25519}, VersionTLS13, Ed25519, signatureEd25519, directSigning},
 	}
 
 	for testNo, test := range tests {
@@ -84,6 +90,12 @@ func TestSignatureSelection(t *testing.T) {
 		{ecdsaCert, sigsPKCS1WithSHA, sigsPKCS1WithSHA, VersionTLS12},
 		{ecdsaCert, sigsECDSAWithSHA, sigsPKCS1WithSHA, VersionTLS12},
 		{rsaCert, []SignatureScheme{0}, sigsPKCS1WithSHA, VersionTLS12},
+		{ed25519Cert, sigsECDSAWithSHA, sigsECDSAWithSHA, VersionTLS12},
+		{ecdsaCert, []SignatureScheme{Ed25519}, []SignatureScheme{Ed25519}, VersionTLS12},
+
+		// Ed25519 can only be negotiated with signature_algorithms.
+		{ed25519Cert, nil, []SignatureScheme{Ed25519}, VersionTLS12},
+		{ed25519Cert, nil, nil, VersionTLS11},
 
 		// ECDSA is unspecified for SSL 3.0 in RFC 4492.
 		// TODO a SSL 3.0 client cannot advertise signature_algorithms,
@@ -158,8 +158,14 @@ const (
 	signaturePKCS1v15 uint8 = iota + 16
 	signatureECDSA
 	signatureRSAPSS
+	signatureEd25519
 )
 
+// directSigning is a standard Hash value that signals that no pre-hashing
+// should be performed, and that the input should be signed directly. It is the
+// hash function associated with the Ed25519 signature scheme.
+const directSigning crypto.Hash = 0
+
 // supportedSignatureAlgorithms contains the signature and hash algorithms that
 // the code advertises as supported in a TLS 1.2+ ClientHello and in a TLS 1.2+
 // CertificateRequest. The two fields are merged to match with TLS 1.3.
@@ -168,6 +174,7 @@ var supportedSignatureAlgorithms = []SignatureScheme{
 	PSSWithSHA256,
 	PSSWithSHA384,
 	PSSWithSHA512,
+	Ed25519,
 	PKCS1WithSHA256,
 	ECDSAWithP256AndSHA256,
 	PKCS1WithSHA384,
@@ -181,6 +188,34 @@ var supportedSignatureAlgorithms = []SignatureScheme{
 // RSA-PSS is disabled in TLS 1.2 for Go 1.12. See Issue 30055.
 var supportedSignatureAlgorithmsTLS12 = supportedSignatureAlgorithms[3:]
 
+// signatureAlgorithms returns supportedSignatureAlgorithms, as
+// adjusted for tests by c.
+func (c *Config) signatureAlgorithms() []SignatureScheme {
+	if c != nil && c.testingOnlyOmitEd25519 {
+		return withoutEd25519(supportedSignatureAlgorithms)
+	}
+	return supportedSignatureAlgorithms
+}
+
+// signatureAlgorithmsTLS12 returns supportedSignatureAlgorithmsTLS12,
+// as adjusted for tests by c.
+func (c *Config) signatureAlgorithmsTLS12() []SignatureScheme {
+	if c != nil && c.testingOnlyOmitEd25519 {
+		return withoutEd25519(supportedSignatureAlgorithmsTLS12)
+	}
+	return supportedSignatureAlgorithmsTLS12
+}
+
+func withoutEd25519(sigAlgs []SignatureScheme) []SignatureScheme {
+	var out []SignatureScheme
+	for _, sigAlg := range sigAlgs {
+		if sigAlg != Ed25519 {
+			out = append(out, sigAlg)
+		}
+	}
+	return out
+}
+
 // helloRetryRequestRandom is set as the Random value of a ServerHello
 // to signal that the message is actually a HelloRetryRequest.
 var helloRetryRequestRandom = []byte{ // See RFC 8446, Section 4.1.3.
@@ -310,6 +345,9 @@ const (
 	ECDSAWithP384AndSHA384 SignatureScheme = 0x0503
 	ECDSAWithP521AndSHA512 SignatureScheme = 0x0603
 
+	// EdDSA algorithms.
+	Ed25519 SignatureScheme = 0x0807
+
 	// Legacy signature and hash algorithms for TLS 1.2.
 	PKCS1WithSHA1 SignatureScheme = 0x0201
 	ECDSAWithSHA1 SignatureScheme = 0x0203
@@ -599,6 +637,11 @@ type Config struct {
 	// for new tickets and any subsequent keys can be used to decrypt old
 	// tickets.
 	sessionTicketKeys []ticketKey
+
+	// testingOnlyOmitEd25519 removes Ed25519 from the signature
+	// algorithms used by connections with this Config, so that tests
+	// can replay connections recorded before it was supported.
+	testingOnlyOmitEd25519 bool
 }
 
 // ticketKeyNameLen is the number of bytes of identifier that is prepended to
@@ -668,6 +711,7 @@ func (c *Config) Clone() *Config {
 		Renegotiation:               c.Renegotiation,
 		KeyLogWriter:                c.KeyLogWriter,
 		sessionTicketKeys:           sessionTicketKeys,
+		testingOnlyOmitEd25519:      c.testingOnlyOmitEd25519,
 	}
 }
 
@@ -969,7 +1013,8 @@ var writerMutex sync.Mutex
 type Certificate struct {
 	Certificate [][]byte
 	// PrivateKey contains the private key corresponding to the public key in
-	// Leaf. This must implement crypto.Signer with an RSA or ECDSA PublicKey.
+	// Leaf. This must implement crypto.Signer with an RSA, ECDSA or Ed25519
+	// PublicKey.
 	// For a server up to TLS 1.2, it can also implement crypto.Decrypter with
 	// an RSA PublicKey.
 	PrivateKey crypto.PrivateKey
@@ -1185,6 +1230,8 @@ func signatureFromSignatureScheme(signatureAlgorithm SignatureScheme) uint8 {
 		return signatureRSAPSS
 	case ECDSAWithSHA1, ECDSAWithP256AndSHA256, ECDSAWithP384AndSHA384, ECDSAWithP521AndSHA512:
 		return signatureECDSA
+	case Ed25519:
+		return signatureEd25519
 	default:
 		return 0
 	}
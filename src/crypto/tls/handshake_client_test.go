@@ -336,6 +336,9 @@ func (test *clientTest) run(t *testing.T, write bool) {
 		if config == nil {
 			config = testConfig
 		}
+		if !write {
+			config = replayConfig(config)
+		}
 		client := Client(clientConn, config)
 		defer client.Close()
 
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/x509"
@@ -657,6 +658,9 @@ func (test *serverTest) run(t *testing.T, write bool) {
 	if config == nil {
 		config = testConfig
 	}
+	if !write {
+		config = replayConfig(config)
+	}
 	server := Server(serverConn, config)
 	connStateChan := make(chan ConnectionState, 1)
 	go func() {
@@ -1082,6 +1086,49 @@ func TestCipherSuiteCertPreferenceECDSA(t *testing.T) {
 	runServerTestTLS12(t, test)
 }
 
+func TestHandshakeEd25519(t *testing.T) {
+	t.Run("TLSv12", func(t *testing.T) { testHandshakeEd25519(t, VersionTLS12) })
+	t.Run("TLSv13", func(t *testing.T) { testHandshakeEd25519(t, VersionTLS13) })
+}
+
+func testHandshakeEd25519(t *testing.T, version uint16) {
+	ed25519Cert := Certificate{
+		Certificate: [][]byte{testEd25519Certificate},
+		PrivateKey:  testEd25519PrivateKey,
+	}
+	serverConfig := testConfig.Clone()
+	serverConfig.MaxVersion = version
+	serverConfig.CipherSuites = []uint16{TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}
+	serverConfig.Certificates = []Certificate{ed25519Cert}
+	serverConfig.ClientAuth = RequireAnyClientCert
+	clientConfig := testConfig.Clone()
+	clientConfig.Certificates = []Certificate{ed25519Cert}
+
+	serverState, clientState, err := testHandshake(t, clientConfig, serverConfig)
+	if err != nil {
+		t.Fatalf("handshake failed: %s", err)
+	}
+	if clientState.Version != version {
+		t.Errorf("got version %x, expected %x", clientState.Version, version)
+	}
+	if version == VersionTLS12 && clientState.CipherSuite != TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("got cipher suite %x, expected an ECDHE_ECDSA one", clientState.CipherSuite)
+	}
+	if len(clientState.PeerCertificates) != 1 || clientState.PeerCertificates[0].PublicKeyAlgorithm != x509.Ed25519 {
+		t.Errorf("client did not receive the Ed25519 server certificate")
+	}
+	if len(serverState.PeerCertificates) != 1 || serverState.PeerCertificates[0].PublicKeyAlgorithm != x509.Ed25519 {
+		t.Errorf("server did not receive the Ed25519 client certificate")
+	}
+
+	// Ed25519 can't be negotiated without the signature_algorithms extension.
+	serverConfig.MaxVersion = VersionTLS11
+	clientConfig.MaxVersion = VersionTLS11
+	if _, _, err := testHandshake(t, clientConfig, serverConfig); err == nil {
+		t.Errorf("handshake with an Ed25519 certificate succeeded in TLS 1.1")
+	}
+}
+
 func TestServerResumption(t *testing.T) {
 	sessionFilePath := tempFile("")
 	defer os.Remove(sessionFilePath)
@@ -1727,6 +1774,10 @@ var testECDSAPrivateKey = &ecdsa.PrivateKey{
 	D: bigFromString("5477294338614160138026852784385529180817726002953041720191098180813046231640184669647735805135001309477695746518160084669446643325196003346204701381388769751"),
 }
 
+var testEd25519Certificate = fromHex("3082012c3081dfa003020102020101300506032b657030123110300e060355040a130741636d6520436f301e170d3139303130313030303030305a170d3439303130313030303030305a30123110300e060355040a130741636d6520436f302a300506032b65700321004d0ec3a0d03fefff93a9bcce2a24b406d46f44aeff1f7787daedb13caafa402ca35a3058300e0603551d0f0101ff040403020780301d0603551d250416301406082b0601050507030106082b06010505070302300c0603551d130101ff0402300030190603551d1104123010820e6578616d706c652e676f6c616e67300506032b657003410037b6099a5d9c3e775c93c257c1088395efff87b282453d9ae27e21dd28121689183566a0739ec670c4136df90cbdc58334453f5c8a4cd164feb5d17ca4fd0400")
+
+var testEd25519PrivateKey = ed25519.PrivateKey(fromHex("00070e151c232a31383f464d545b626970777e858c939aa1a8afb6bdc4cbd2d94d0ec3a0d03fefff93a9bcce2a24b406d46f44aeff1f7787daedb13caafa402c"))
+
 var testP256PrivateKey, _ = x509.ParseECPrivateKey(fromHex("30770201010420012f3b52bc54c36ba3577ad45034e2e8efe1e6999851284cb848725cfe029991a00a06082a8648ce3d030107a14403420004c02c61c9b16283bbcc14956d886d79b358aa614596975f78cece787146abf74c2d5dc578c0992b4f3c631373479ebf3892efe53d21c4f4f1cc9a11c3536b7f75"))
 
 func TestCloseServerConnectionOnIdleClient(t *testing.T) {
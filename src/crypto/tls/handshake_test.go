@@ -85,6 +85,16 @@ func testOpenSSLVersion() {
 	opensslVersionTestErr = errors.New("version of OpenSSL does not appear to be suitable for updating test data")
 }
 
+// replayConfig returns a copy of config for replaying a reference
+// connection from testdata. Those were recorded before Ed25519 was
+// supported, so it is left out of the signature algorithms. Connections
+// recorded with -update use the complete lists.
+func replayConfig(config *Config) *Config {
+	config = config.Clone()
+	config.testingOnlyOmitEd25519 = true
+	return config
+}
+
 // recordingConn is a net.Conn that records the traffic that passes through it.
 // WriteTo can be used to produce output that can be later be loaded with
 // ParseTestData.
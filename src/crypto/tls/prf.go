@@ -187,6 +187,8 @@ func hashFromSignatureScheme(signatureAlgorithm SignatureScheme) (crypto.Hash, e
 		return crypto.SHA384, nil
 	case PKCS1WithSHA512, PSSWithSHA512, ECDSAWithP521AndSHA512:
 		return crypto.SHA512, nil
+	case Ed25519:
+		return directSigning, nil
 	default:
 		return 0, fmt.Errorf("tls: unsupported signature algorithm: %#04x", signatureAlgorithm)
 	}
@@ -309,7 +311,8 @@ func (h finishedHash) serverSum(masterSecret []byte) []byte {
 }
 
 // hashForClientCertificate returns a digest over the handshake messages so far,
-// suitable for signing by a TLS client certificate.
+// suitable for signing by a TLS client certificate. For Ed25519, which signs
+// messages directly, it returns the handshake messages themselves.
 func (h finishedHash) hashForClientCertificate(sigType uint8, hashAlg crypto.Hash, masterSecret []byte) ([]byte, error) {
 	if (h.version == VersionSSL30 || h.version >= VersionTLS12) && h.buffer == nil {
 		panic("a handshake hash for a client-certificate was requested after discarding the handshake buffer")
@@ -327,6 +330,9 @@ func (h finishedHash) hashForClientCertificate(sigType uint8, hashAlg crypto.Has
 		return finishedSum30(md5Hash, sha1Hash, masterSecret, nil), nil
 	}
 	if h.version >= VersionTLS12 {
+		if sigType == signatureEd25519 {
+			return h.buffer, nil
+		}
 		hash := hashAlg.New()
 		hash.Write(h.buffer)
 		return hash.Sum(nil), nil
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"crypto/dsa"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -77,6 +78,58 @@ func TestParsePKIXPublicKey(t *testing.T) {
 	}
 }
 
+func TestParsePKIXPublicKeyEd25519(t *testing.T) {
+	block, _ := pem.Decode([]byte(pemEd25519Key))
+	pub, err := ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse Ed25519 public key: %s", err)
+	}
+	edPub, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		t.Fatalf("Value returned from ParsePKIXPublicKey was not an Ed25519 public key")
+	}
+
+	// Check that the key matches the one in the RFC 8410 PKCS#8 example.
+	derBytes, _ := hex.DecodeString(pkcs8Ed25519PrivateKeyHex)
+	priv, err := ParsePKCS8PrivateKey(derBytes)
+	if err != nil {
+		t.Fatalf("Failed to parse Ed25519 private key: %s", err)
+	}
+	if want := priv.(ed25519.PrivateKey).Public().(ed25519.PublicKey); !bytes.Equal(edPub, want) {
+		t.Errorf("Ed25519 public key = %x, want %x", edPub, want)
+	}
+
+	pubBytes2, err := MarshalPKIXPublicKey(edPub)
+	if err != nil {
+		t.Fatalf("Failed to marshal Ed25519 public key for the second time: %s", err)
+	}
+	if !bytes.Equal(pubBytes2, block.Bytes) {
+		t.Errorf("Reserialization of public key didn't match. got %x, want %x", pubBytes2, block.Bytes)
+	}
+
+	// RFC 8410 requires the parameters to be absent.
+	var pki publicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &pki); err != nil {
+		t.Fatal(err)
+	}
+	pki.Raw = nil
+	pki.Algorithm.Parameters = asn1.NullRawValue
+	withParams, err := asn1.Marshal(pki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParsePKIXPublicKey(withParams); err == nil {
+		t.Errorf("ParsePKIXPublicKey accepted an Ed25519 key with parameters")
+	}
+}
+
+// From RFC 8410, Section 10.1.
+var pemEd25519Key = `
+-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEAGb9ECWmEzf6FQbrBZ9w7lshQhqowtrbLDFw4rXAxZuE=
+-----END PUBLIC KEY-----
+`
+
 var pemPublicKey = `-----BEGIN PUBLIC KEY-----
 MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA3VoPN9PKUjKFLMwOge6+
 wnDi8sbETGIx2FKXGgqtAKpzmem53kRGEQg8WeqRmp12wgp74TGpkEXsGae7RS1k
@@ -496,6 +549,11 @@ func TestCreateSelfSignedCertificate(t *testing.T) {
 		t.Fatalf("Failed to generate ECDSA key: %s", err)
 	}
 
+	ed25519Pub, ed25519Priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %s", err)
+	}
+
 	tests := []struct {
 		name      string
 		pub, priv interface{}
@@ -509,6 +567,9 @@ func TestCreateSelfSignedCertificate(t *testing.T) {
 		{"RSAPSS/RSAPSS", &testPrivateKey.PublicKey, testPrivateKey, true, SHA256WithRSAPSS},
 		{"ECDSA/RSAPSS", &ecdsaPriv.PublicKey, testPrivateKey, false, SHA256WithRSAPSS},
 		{"RSAPSS/ECDSA", &testPrivateKey.PublicKey, ecdsaPriv, false, ECDSAWithSHA384},
+		{"Ed25519/Ed25519", ed25519Pub, ed25519Priv, true, PureEd25519},
+		{"Ed25519/ECDSA", ed25519Pub, ecdsaPriv, false, ECDSAWithSHA256},
+		{"ECDSA/Ed25519", &ecdsaPriv.PublicKey, ed25519Priv, false, PureEd25519},
 	}
 
 	testExtKeyUsage := []ExtKeyUsage{ExtKeyUsageClientAuth, ExtKeyUsageServerAuth}
@@ -1154,6 +1215,11 @@ func TestCreateCertificateRequest(t *testing.T) {
 		t.Fatalf("Failed to generate ECDSA key: %s", err)
 	}
 
+	_, ed25519Priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %s", err)
+	}
+
 	tests := []struct {
 		name    string
 		priv    interface{}
@@ -1163,6 +1229,7 @@ func TestCreateCertificateRequest(t *testing.T) {
 		{"ECDSA-256", ecdsa256Priv, ECDSAWithSHA1},
 		{"ECDSA-384", ecdsa384Priv, ECDSAWithSHA1},
 		{"ECDSA-521", ecdsa521Priv, ECDSAWithSHA1},
+		{"Ed25519", ed25519Priv, PureEd25519},
 	}
 
 	for _, test := range tests {
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 	"unicode"
 	"unicode/utf8"
@@ -203,6 +204,33 @@ func driverArgsConnLocked(ci driver.Conn, ds *driverStmt, args []interface{}) ([
 
 }
 
+// timeFormats are the textual timestamp layouts that convertAssign
+// accepts when scanning a string or []byte into a time.Time. Layouts
+// without a zone are interpreted as UTC.
+var timeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999 -0700 MST", // time.Time.String
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTime sets *d to the time in s, which must be in one of
+// timeFormats. src is the original driver value, for errors.
+func parseTime(d *time.Time, src interface{}, s string) error {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			*d = t
+			return nil
+		}
+	}
+	return fmt.Errorf("converting driver.Value type %T (%q) to a time.Time: unrecognized timestamp format", src, s)
+}
+
 // convertAssign is the same as convertAssignRows, but without the optional
 // rows argument.
 func convertAssign(dest, src interface{}) error {
@@ -237,6 +265,11 @@ func convertAssignRows(dest, src interface{}, rows *Rows) error {
 			}
 			*d = append((*d)[:0], s...)
 			return nil
+		case *time.Time:
+			if d == nil {
+				return errNilPtr
+			}
+			return parseTime(d, src, s)
 		}
 	case []byte:
 		switch d := dest.(type) {
@@ -264,6 +297,11 @@ func convertAssignRows(dest, src interface{}, rows *Rows) error {
 			}
 			*d = s
 			return nil
+		case *time.Time:
+			if d == nil {
+				return errNilPtr
+			}
+			return parseTime(d, src, string(s))
 		}
 	case time.Time:
 		switch d := dest.(type) {
@@ -92,6 +92,15 @@ func conversionTests() []conversionTest {
 		{s: time.Unix(1, 2).UTC(), d: &scanbytes, wantbytes: []byte("1970-01-01T00:00:01.000000002Z")},
 		{s: time.Unix(1, 2).UTC(), d: &scaniface, wantiface: time.Unix(1, 2).UTC()},
 
+		// To time.Time:
+		{s: "2016-01-26T22:03:17-08:00", d: &scantime, wanttime: time.Unix(1453874597, 0)},
+		{s: []byte("1970-01-01T00:00:01.000000002Z"), d: &scantime, wanttime: time.Unix(1, 2)},
+		{s: "2016-01-27 06:03:17", d: &scantime, wanttime: time.Unix(1453874597, 0)},
+		{s: "2016-01-27 06:03:17.5+00:00", d: &scantime, wanttime: time.Unix(1453874597, 5e8)},
+		{s: []byte("2016-01-27T06:03"), d: &scantime, wanttime: time.Unix(1453874580, 0)},
+		{s: "2016-01-27", d: &scantime, wanttime: time.Unix(1453852800, 0)},
+		{s: "yesterday", d: &scantime, wanterr: `converting driver.Value type string ("yesterday") to a time.Time: unrecognized timestamp format`},
+
 		// To []byte
 		{s: nil, d: &scanbytes, wantbytes: nil},
 		{s: "string", d: &scanbytes, wantbytes: []byte("string")},
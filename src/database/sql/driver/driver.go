@@ -264,6 +264,18 @@ type SessionResetter interface {
 	ResetSession(ctx context.Context) error
 }
 
+// Validator may be implemented by Conn to allow drivers to
+// signal if a connection is valid or if it should be discarded.
+//
+// If implemented, drivers may return the underlying error from queries,
+// even if the connection should be discarded by the connection pool.
+type Validator interface {
+	// IsValid is called before a connection from the connection pool
+	// is reused. The connection will be discarded, and another one
+	// used in its place, if false is returned.
+	IsValid() bool
+}
+
 // Result is the result of a query execution.
 type Result interface {
 	// LastInsertId returns the database's auto-generated ID
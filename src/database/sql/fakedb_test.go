@@ -134,6 +134,9 @@ type fakeConn struct {
 	bad       bool
 	stickyBad bool
 
+	// invalid is reported by IsValid; see TestValidatorDiscardsConn.
+	invalid bool
+
 	skipDirtySession bool // tests that use Conn should set this to true.
 
 	// dirtySession tests ResetSession, true if a query has executed
@@ -399,6 +402,10 @@ func (c *fakeConn) ResetSession(ctx context.Context) error {
 	return nil
 }
 
+func (c *fakeConn) IsValid() bool {
+	return !c.invalid
+}
+
 func (c *fakeConn) Close() (err error) {
 	drv := fdriver.(*fakeDriver)
 	defer func() {
@@ -1141,6 +1148,16 @@ func converterForType(typ string) driver.ValueConverter {
 		return driver.Null{Converter: driver.Bool}
 	case "int32":
 		return driver.Int32
+	case "nullint32":
+		return driver.Null{Converter: driver.DefaultParameterConverter}
+	case "int16":
+		return driver.NotNull{Converter: driver.DefaultParameterConverter}
+	case "nullint16":
+		return driver.Null{Converter: driver.DefaultParameterConverter}
+	case "byte":
+		return driver.NotNull{Converter: driver.DefaultParameterConverter}
+	case "nullbyte":
+		return driver.Null{Converter: driver.DefaultParameterConverter}
 	case "string":
 		return driver.NotNull{Converter: fakeDriverString{}}
 	case "nullstring":
@@ -1159,6 +1176,10 @@ func converterForType(typ string) driver.ValueConverter {
 		return driver.Null{Converter: driver.DefaultParameterConverter}
 	case "datetime":
 		return driver.DefaultParameterConverter
+	case "notnulldatetime":
+		return driver.NotNull{Converter: driver.DefaultParameterConverter}
+	case "nulldatetime":
+		return driver.Null{Converter: driver.DefaultParameterConverter}
 	case "any":
 		return anyTypeConverter{}
 	}
@@ -1173,6 +1194,16 @@ func colTypeToReflectType(typ string) reflect.Type {
 		return reflect.TypeOf(NullBool{})
 	case "int32":
 		return reflect.TypeOf(int32(0))
+	case "nullint32":
+		return reflect.TypeOf(NullInt32{})
+	case "int16":
+		return reflect.TypeOf(int16(0))
+	case "nullint16":
+		return reflect.TypeOf(NullInt16{})
+	case "byte":
+		return reflect.TypeOf(byte(0))
+	case "nullbyte":
+		return reflect.TypeOf(NullByte{})
 	case "string":
 		return reflect.TypeOf("")
 	case "nullstring":
@@ -1187,6 +1218,10 @@ func colTypeToReflectType(typ string) reflect.Type {
 		return reflect.TypeOf(NullFloat64{})
 	case "datetime":
 		return reflect.TypeOf(time.Time{})
+	case "notnulldatetime":
+		return reflect.TypeOf(time.Time{})
+	case "nulldatetime":
+		return reflect.TypeOf(NullTime{})
 	case "any":
 		return reflect.TypeOf(new(interface{})).Elem()
 	}
@@ -0,0 +1,146 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Op identifies the kind of operation reported to an Interceptor.
+type Op int
+
+const (
+	OpPrepare  Op = iota + 1 // PrepareContext on a DB, Conn or Tx
+	OpExec                   // ExecContext on a DB, Conn, Tx or Stmt
+	OpQuery                  // QueryContext or QueryRowContext on a DB, Conn, Tx or Stmt
+	OpBegin                  // BeginTx on a DB or Conn
+	OpCommit                 // Tx.Commit
+	OpRollback               // Tx.Rollback, or a rollback after the transaction's context is done
+)
+
+var opNames = [...]string{
+	OpPrepare:  "Prepare",
+	OpExec:     "Exec",
+	OpQuery:    "Query",
+	OpBegin:    "Begin",
+	OpCommit:   "Commit",
+	OpRollback: "Rollback",
+}
+
+func (op Op) String() string {
+	if op > 0 && int(op) < len(opNames) {
+		return opNames[op]
+	}
+	return "Op(" + strconv.Itoa(int(op)) + ")"
+}
+
+// An Event describes an operation performed through a DB.
+type Event struct {
+	Op Op
+
+	// Query is the query text of an OpPrepare, OpExec or OpQuery
+	// operation. For operations on a Stmt it is the query the
+	// statement was prepared with.
+	Query string
+
+	// Args holds the arguments of an OpExec or OpQuery operation,
+	// as passed by the caller.
+	Args []interface{}
+
+	// Duration is how long the operation took. For OpQuery, it is
+	// the time until the driver returned the rows, and does not
+	// include the time spent reading them. It is set before After
+	// is called.
+	Duration time.Duration
+
+	// Err is the error the operation returned, if any. It is set
+	// before After is called.
+	Err error
+}
+
+// An Interceptor wraps the operations performed through a DB, such as
+// to trace them or to log slow queries. See DB.SetInterceptor.
+//
+// Before and After are called around each prepare, exec, query,
+// begin, commit and rollback on the DB, or on a Conn, Tx or Stmt
+// derived from it. They are called on the goroutine that performs the
+// operation, with the same Event, which must not be retained after
+// After returns.
+//
+// An operation that fails with driver.ErrBadConn and is retried on
+// another connection is reported once for each attempt.
+type Interceptor interface {
+	// Before is called before the operation described by ev, with the
+	// context it was given. It returns the context to pass to the
+	// driver for the operation: ctx, or a context derived from it,
+	// such as one carrying a tracing span.
+	//
+	// For OpCommit and OpRollback, ctx is the context the transaction
+	// was begun with, and the returned context is only passed to
+	// After, as the driver's Commit and Rollback take no context.
+	Before(ctx context.Context, ev *Event) context.Context
+
+	// After is called once the driver has completed the operation,
+	// with the context returned by Before. The connection may still
+	// be in use: after a successful OpQuery it is held by the Rows
+	// until they are closed, and after a successful OpBegin it is
+	// held by the Tx.
+	After(ctx context.Context, ev *Event)
+}
+
+// interceptorHolder lets an Interceptor of any type be stored in an
+// atomic.Value.
+type interceptorHolder struct {
+	i Interceptor
+}
+
+// SetInterceptor sets the Interceptor that is notified of operations
+// on db. A nil Interceptor stops notifications.
+//
+// The Interceptor may be changed while db is in use. Operations that
+// are in progress when it is changed are reported to the Interceptor
+// whose Before method was called for them.
+func (db *DB) SetInterceptor(i Interceptor) {
+	db.interceptor.Store(interceptorHolder{i})
+}
+
+// An interceptCall is an operation being reported to an Interceptor.
+type interceptCall struct {
+	i     Interceptor
+	ctx   context.Context // returned by Before
+	ev    Event
+	start time.Time
+}
+
+// interceptBefore reports the start of an operation to db's Interceptor
+// and returns the context to perform the operation with, and the call
+// on which to report its end. If db has no Interceptor, it returns ctx
+// and a nil *interceptCall.
+func (db *DB) interceptBefore(ctx context.Context, op Op, query string, args []interface{}) (context.Context, *interceptCall) {
+	h, _ := db.interceptor.Load().(interceptorHolder)
+	if h.i == nil {
+		return ctx, nil
+	}
+	c := &interceptCall{
+		i:  h.i,
+		ev: Event{Op: op, Query: query, Args: args},
+	}
+	c.ctx = h.i.Before(ctx, &c.ev)
+	c.start = time.Now()
+	return c.ctx, c
+}
+
+// after reports the end of c's operation, which returned err.
+// It is a no-op if c is nil.
+func (c *interceptCall) after(err error) {
+	if c == nil {
+		return
+	}
+	c.ev.Duration = time.Since(c.start)
+	c.ev.Err = err
+	c.i.After(c.ctx, &c.ev)
+}
@@ -0,0 +1,261 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type recordingInterceptor struct {
+	mu      sync.Mutex
+	pending map[*Event]bool
+	events  []Event
+}
+
+func (r *recordingInterceptor) Before(ctx context.Context, ev *Event) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending == nil {
+		r.pending = make(map[*Event]bool)
+	}
+	r.pending[ev] = true
+	return ctx
+}
+
+func (r *recordingInterceptor) After(ctx context.Context, ev *Event) {
+	if ev.Duration < 0 {
+		panic("negative Duration")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.pending[ev] {
+		panic("After called without Before")
+	}
+	delete(r.pending, ev)
+	e := *ev
+	e.Duration = 0
+	r.events = append(r.events, e)
+}
+
+func (r *recordingInterceptor) take() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evs := r.events
+	r.events = nil
+	return evs
+}
+
+func TestInterceptor(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+
+	rec := new(recordingInterceptor)
+	db.SetInterceptor(rec)
+
+	if _, err := db.Exec("INSERT|people|name=?,age=?", "Dave", 4); err != nil {
+		t.Fatal(err)
+	}
+	var name string
+	if err := db.QueryRow("SELECT|people|name|age=?", 4).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	_, err := db.Exec("INSERT|nosuchtable|name=?", "Eve")
+	if err == nil {
+		t.Fatal("expected error inserting into a missing table")
+	}
+	want := []Event{
+		{Op: OpExec, Query: "INSERT|people|name=?,age=?", Args: []interface{}{"Dave", 4}},
+		{Op: OpQuery, Query: "SELECT|people|name|age=?", Args: []interface{}{4}},
+		{Op: OpExec, Query: "INSERT|nosuchtable|name=?", Args: []interface{}{"Eve"}, Err: err},
+	}
+	if got := rec.take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DB events:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	stmt, err := db.Prepare("SELECT|people|name|age=?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.QueryRow(3).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	stmt.Close()
+	want = []Event{
+		{Op: OpPrepare, Query: "SELECT|people|name|age=?"},
+		{Op: OpQuery, Query: "SELECT|people|name|age=?", Args: []interface{}{3}},
+	}
+	if got := rec.take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Stmt events:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec("INSERT|people|name=?,age=?", "Fay", 6); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	want = []Event{
+		{Op: OpBegin},
+		{Op: OpExec, Query: "INSERT|people|name=?,age=?", Args: []interface{}{"Fay", 6}},
+		{Op: OpCommit},
+		{Op: OpBegin},
+		{Op: OpRollback},
+	}
+	if got := rec.take(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tx events:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	db.SetInterceptor(nil)
+	if _, err := db.Exec("INSERT|people|name=?,age=?", "Gus", 7); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.take(); len(got) != 0 {
+		t.Errorf("events after removing interceptor: %+v", got)
+	}
+}
+
+func TestInterceptorContext(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	var got []interface{}
+	db.SetInterceptor(interceptorFunc(func(ctx context.Context, ev *Event) {
+		got = append(got, ctx.Value(ctxKey{}))
+	}))
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.dc.ci.(*fakeConn).skipDirtySession = true
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "INSERT|people|name=?,age=?", "Dave", 4); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"v", "v", "v"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("context values = %v; want %v", got, want)
+	}
+}
+
+// interceptorFunc is an Interceptor that calls f after each operation.
+type interceptorFunc func(context.Context, *Event)
+
+func (f interceptorFunc) Before(ctx context.Context, ev *Event) context.Context { return ctx }
+func (f interceptorFunc) After(ctx context.Context, ev *Event)                  { f(ctx, ev) }
+
+type spanInterceptor struct {
+	cancelExec bool
+	afterCtx   []interface{}
+}
+
+type spanKey struct{}
+
+func (s *spanInterceptor) Before(ctx context.Context, ev *Event) context.Context {
+	ctx = context.WithValue(ctx, spanKey{}, ev.Op.String())
+	if s.cancelExec && ev.Op == OpExec {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		cancel()
+	}
+	return ctx
+}
+
+func (s *spanInterceptor) After(ctx context.Context, ev *Event) {
+	s.afterCtx = append(s.afterCtx, ctx.Value(spanKey{}))
+}
+
+func TestInterceptorBeforeContext(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+
+	si := new(spanInterceptor)
+	db.SetInterceptor(si)
+	if _, err := db.Exec("INSERT|people|name=?,age=?", "Dave", 4); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"Exec", "Begin", "Commit"}; !reflect.DeepEqual(si.afterCtx, want) {
+		t.Errorf("After contexts = %v; want %v", si.afterCtx, want)
+	}
+
+	// The driver is called with the context returned by Before.
+	si.cancelExec = true
+	if _, err := db.Exec("INSERT|people|name=?,age=?", "Eve", 5); err != context.Canceled {
+		t.Errorf("Exec with a canceled Before context: err = %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestInterceptorRetries(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+
+	var ops []Op
+	var errs []error
+	db.SetInterceptor(interceptorFunc(func(ctx context.Context, ev *Event) {
+		ops = append(ops, ev.Op)
+		errs = append(errs, ev.Err)
+	}))
+
+	// Fail the first attempt with driver.ErrBadConn.
+	broken := false
+	hookExecBadConn = func() bool {
+		if broken {
+			return false
+		}
+		broken = true
+		return true
+	}
+	defer func() { hookExecBadConn = nil }()
+
+	if _, err := db.Exec("INSERT|people|name=?,age=?", "Dave", 4); err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 || errs[0] != driver.ErrBadConn || errs[1] != nil {
+		t.Errorf("got ops %v with errors %v; want a failed attempt followed by a success", ops, errs)
+	}
+}
+
+func TestOpString(t *testing.T) {
+	for op, want := range map[Op]string{
+		OpPrepare:  "Prepare",
+		OpRollback: "Rollback",
+		0:          "Op(0)",
+		Op(42):     "Op(42)",
+	} {
+		if got := op.String(); got != want {
+			t.Errorf("Op(%d).String() = %q; want %q", int(op), got, want)
+		}
+	}
+}
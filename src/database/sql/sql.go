@@ -234,6 +234,84 @@ func (n NullInt64) Value() (driver.Value, error) {
 	return n.Int64, nil
 }
 
+// NullInt32 represents an int32 that may be null.
+// NullInt32 implements the Scanner interface so
+// it can be used as a scan destination, similar to NullString.
+type NullInt32 struct {
+	Int32 int32
+	Valid bool // Valid is true if Int32 is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullInt32) Scan(value interface{}) error {
+	if value == nil {
+		n.Int32, n.Valid = 0, false
+		return nil
+	}
+	n.Valid = true
+	return convertAssign(&n.Int32, value)
+}
+
+// Value implements the driver Valuer interface.
+func (n NullInt32) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Int32), nil
+}
+
+// NullInt16 represents an int16 that may be null.
+// NullInt16 implements the Scanner interface so
+// it can be used as a scan destination, similar to NullString.
+type NullInt16 struct {
+	Int16 int16
+	Valid bool // Valid is true if Int16 is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullInt16) Scan(value interface{}) error {
+	if value == nil {
+		n.Int16, n.Valid = 0, false
+		return nil
+	}
+	n.Valid = true
+	return convertAssign(&n.Int16, value)
+}
+
+// Value implements the driver Valuer interface.
+func (n NullInt16) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Int16), nil
+}
+
+// NullByte represents a byte that may be null.
+// NullByte implements the Scanner interface so
+// it can be used as a scan destination, similar to NullString.
+type NullByte struct {
+	Byte  byte
+	Valid bool // Valid is true if Byte is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullByte) Scan(value interface{}) error {
+	if value == nil {
+		n.Byte, n.Valid = 0, false
+		return nil
+	}
+	n.Valid = true
+	return convertAssign(&n.Byte, value)
+}
+
+// Value implements the driver Valuer interface.
+func (n NullByte) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Byte), nil
+}
+
 // NullFloat64 represents a float64 that may be null.
 // NullFloat64 implements the Scanner interface so
 // it can be used as a scan destination, similar to NullString.
@@ -286,6 +364,32 @@ func (n NullBool) Value() (driver.Value, error) {
 	return n.Bool, nil
 }
 
+// NullTime represents a time.Time that may be null.
+// NullTime implements the Scanner interface so
+// it can be used as a scan destination, similar to NullString.
+type NullTime struct {
+	Time  time.Time
+	Valid bool // Valid is true if Time is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullTime) Scan(value interface{}) error {
+	if value == nil {
+		n.Time, n.Valid = time.Time{}, false
+		return nil
+	}
+	n.Valid = true
+	return convertAssign(&n.Time, value)
+}
+
+// Value implements the driver Valuer interface.
+func (n NullTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Time, nil
+}
+
 // Scanner is an interface used by Scan.
 type Scanner interface {
 	// Scan assigns a value from a database driver.
@@ -376,12 +480,16 @@ type DB struct {
 	maxIdle           int                    // zero means defaultMaxIdleConns; negative means 0
 	maxOpen           int                    // <= 0 means unlimited
 	maxLifetime       time.Duration          // maximum amount of time a connection may be reused
+	maxIdleTime       time.Duration          // maximum amount of time a connection may be idle before being closed
 	cleanerCh         chan struct{}
 	waitCount         int64 // Total number of connections waited for.
-	maxIdleClosed     int64 // Total number of connections closed due to idle.
-	maxLifetimeClosed int64 // Total number of connections closed due to max free limit.
+	maxIdleClosed     int64 // Total number of connections closed due to idle count.
+	maxIdleTimeClosed int64 // Total number of connections closed due to idle time.
+	maxLifetimeClosed int64 // Total number of connections closed due to max connection lifetime limit.
 
 	stop func() // stop cancels the connection opener and the session resetter.
+
+	interceptor atomic.Value // of interceptorHolder; see SetInterceptor
 }
 
 // connReuseStrategy determines how (*DB).conn returns database connections.
@@ -404,6 +512,8 @@ type driverConn struct {
 	db        *DB
 	createdAt time.Time
 
+	returnedAt time.Time // time last returned to the idle pool; guarded by db.mu
+
 	sync.Mutex  // guards following
 	ci          driver.Conn
 	closed      bool
@@ -459,6 +569,19 @@ func (dc *driverConn) prepareLocked(ctx context.Context, cg stmtConnGrabber, que
 	return ds, nil
 }
 
+// validateLocked reports whether dc may be reused, asking the driver
+// through driver.Validator if it implements it. The embedded mutex must
+// be held.
+func (dc *driverConn) validateLocked() bool {
+	if dc.lastErr == driver.ErrBadConn {
+		return false
+	}
+	if cv, ok := dc.ci.(driver.Validator); ok {
+		return cv.IsValid()
+	}
+	return true
+}
+
 // resetSession resets the connection session and sets the lastErr
 // that is checked before returning the connection to another query.
 //
@@ -878,11 +1001,45 @@ func (db *DB) SetConnMaxLifetime(d time.Duration) {
 	db.mu.Unlock()
 }
 
+// SetConnMaxIdleTime sets the maximum amount of time a connection may be idle.
+//
+// Expired connections may be closed lazily before reuse.
+//
+// If d <= 0, connections are not closed due to a connection's idle time.
+func (db *DB) SetConnMaxIdleTime(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	db.mu.Lock()
+	// wake cleaner up when idle time is shortened.
+	if d > 0 && (db.maxIdleTime == 0 || d < db.maxIdleTime) && db.cleanerCh != nil {
+		select {
+		case db.cleanerCh <- struct{}{}:
+		default:
+		}
+	}
+	db.maxIdleTime = d
+	db.startCleanerLocked()
+	db.mu.Unlock()
+}
+
+// shortestIdleTimeLocked returns the shorter of the max idle time and
+// the max lifetime, ignoring whichever is unset.
+func (db *DB) shortestIdleTimeLocked() time.Duration {
+	if db.maxIdleTime <= 0 {
+		return db.maxLifetime
+	}
+	if db.maxLifetime <= 0 || db.maxIdleTime < db.maxLifetime {
+		return db.maxIdleTime
+	}
+	return db.maxLifetime
+}
+
 // startCleanerLocked starts connectionCleaner if needed.
 func (db *DB) startCleanerLocked() {
-	if db.maxLifetime > 0 && db.numOpen > 0 && db.cleanerCh == nil {
+	if (db.maxLifetime > 0 || db.maxIdleTime > 0) && db.numOpen > 0 && db.cleanerCh == nil {
 		db.cleanerCh = make(chan struct{}, 1)
-		go db.connectionCleaner(db.maxLifetime)
+		go db.connectionCleaner(db.shortestIdleTimeLocked())
 	}
 }
 
@@ -897,31 +1054,18 @@ func (db *DB) connectionCleaner(d time.Duration) {
 	for {
 		select {
 		case <-t.C:
-		case <-db.cleanerCh: // maxLifetime was changed or db was closed.
+		case <-db.cleanerCh: // maxLifetime or maxIdleTime was changed or db was closed.
 		}
 
 		db.mu.Lock()
-		d = db.maxLifetime
+		d = db.shortestIdleTimeLocked()
 		if db.closed || db.numOpen == 0 || d <= 0 {
 			db.cleanerCh = nil
 			db.mu.Unlock()
 			return
 		}
 
-		expiredSince := nowFunc().Add(-d)
-		var closing []*driverConn
-		for i := 0; i < len(db.freeConn); i++ {
-			c := db.freeConn[i]
-			if c.createdAt.Before(expiredSince) {
-				closing = append(closing, c)
-				last := len(db.freeConn) - 1
-				db.freeConn[i] = db.freeConn[last]
-				db.freeConn[last] = nil
-				db.freeConn = db.freeConn[:last]
-				i--
-			}
-		}
-		db.maxLifetimeClosed += int64(len(closing))
+		closing := db.connectionCleanerRunLocked()
 		db.mu.Unlock()
 
 		for _, c := range closing {
@@ -935,6 +1079,33 @@ func (db *DB) connectionCleaner(d time.Duration) {
 	}
 }
 
+// connectionCleanerRunLocked removes the idle connections that have
+// exceeded the max idle time or the max lifetime from the free pool and
+// returns them to be closed. The remaining connections keep their order.
+func (db *DB) connectionCleanerRunLocked() (closing []*driverConn) {
+	now := nowFunc()
+	idleSince := now.Add(-db.maxIdleTime)
+	expiredSince := now.Add(-db.maxLifetime)
+	kept := db.freeConn[:0]
+	for _, c := range db.freeConn {
+		switch {
+		case db.maxIdleTime > 0 && c.returnedAt.Before(idleSince):
+			db.maxIdleTimeClosed++
+			closing = append(closing, c)
+		case db.maxLifetime > 0 && c.createdAt.Before(expiredSince):
+			db.maxLifetimeClosed++
+			closing = append(closing, c)
+		default:
+			kept = append(kept, c)
+		}
+	}
+	for i := len(kept); i < len(db.freeConn); i++ {
+		db.freeConn[i] = nil
+	}
+	db.freeConn = kept
+	return closing
+}
+
 // DBStats contains database statistics.
 type DBStats struct {
 	MaxOpenConnections int // Maximum number of open connections to the database.
@@ -948,6 +1119,7 @@ type DBStats struct {
 	WaitCount         int64         // The total number of connections waited for.
 	WaitDuration      time.Duration // The total time blocked waiting for a new connection.
 	MaxIdleClosed     int64         // The total number of connections closed due to SetMaxIdleConns.
+	MaxIdleTimeClosed int64         // The total number of connections closed due to SetConnMaxIdleTime.
 	MaxLifetimeClosed int64         // The total number of connections closed due to SetConnMaxLifetime.
 }
 
@@ -968,6 +1140,7 @@ func (db *DB) Stats() DBStats {
 		WaitCount:         db.waitCount,
 		WaitDuration:      time.Duration(wait),
 		MaxIdleClosed:     db.maxIdleClosed,
+		MaxIdleTimeClosed: db.maxIdleTimeClosed,
 		MaxLifetimeClosed: db.maxLifetimeClosed,
 	}
 	return stats
@@ -1098,16 +1271,21 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 		copy(db.freeConn, db.freeConn[1:])
 		db.freeConn = db.freeConn[:numFree-1]
 		conn.inUse = true
+		// The cleaner may not have run since the conn became too idle.
+		idleExpired := db.maxIdleTime > 0 && conn.returnedAt.Add(db.maxIdleTime).Before(nowFunc())
+		if idleExpired {
+			db.maxIdleTimeClosed++
+		}
 		db.mu.Unlock()
-		if conn.expired(lifetime) {
+		if idleExpired || conn.expired(lifetime) {
 			conn.Close()
 			return nil, driver.ErrBadConn
 		}
 		// Lock around reading lastErr to ensure the session resetter finished.
 		conn.Lock()
-		err := conn.lastErr
+		valid := conn.validateLocked()
 		conn.Unlock()
-		if err == driver.ErrBadConn {
+		if !valid {
 			conn.Close()
 			return nil, driver.ErrBadConn
 		}
@@ -1161,9 +1339,9 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 			}
 			// Lock around reading lastErr to ensure the session resetter finished.
 			ret.conn.Lock()
-			err := ret.conn.lastErr
+			valid := ret.conn.validateLocked()
 			ret.conn.Unlock()
-			if err == driver.ErrBadConn {
+			if !valid {
 				ret.conn.Close()
 				return nil, driver.ErrBadConn
 			}
@@ -1323,6 +1501,7 @@ func (db *DB) putConnDBLocked(dc *driverConn, err error) bool {
 		return true
 	} else if err == nil && !db.closed {
 		if db.maxIdleConnsLocked() > len(db.freeConn) {
+			dc.returnedAt = nowFunc()
 			db.freeConn = append(db.freeConn, dc)
 			db.startCleanerLocked()
 			return true
@@ -1389,6 +1568,10 @@ func (db *DB) prepare(ctx context.Context, query string, strategy connReuseStrat
 func (db *DB) prepareDC(ctx context.Context, dc *driverConn, release func(error), cg stmtConnGrabber, query string) (*Stmt, error) {
 	var ds *driverStmt
 	var err error
+	ctx, ic := db.interceptBefore(ctx, OpPrepare, query, nil)
+	defer func() {
+		ic.after(err)
+	}()
 	defer func() {
 		release(err)
 	}()
@@ -1448,6 +1631,10 @@ func (db *DB) exec(ctx context.Context, query string, args []interface{}, strate
 }
 
 func (db *DB) execDC(ctx context.Context, dc *driverConn, release func(error), query string, args []interface{}) (res Result, err error) {
+	ctx, ic := db.interceptBefore(ctx, OpExec, query, args)
+	defer func() {
+		ic.after(err)
+	}()
 	defer func() {
 		release(err)
 	}()
@@ -1522,7 +1709,13 @@ func (db *DB) query(ctx context.Context, query string, args []interface{}, strat
 // The connection gets released by the releaseConn function.
 // The ctx context is from a query method and the txctx context is from an
 // optional transaction context.
-func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn func(error), query string, args []interface{}) (*Rows, error) {
+func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn func(error), query string, args []interface{}) (rows *Rows, err error) {
+	// The Interceptor's context applies to the driver calls; the rows
+	// are still closed when the caller's context is done.
+	opCtx, ic := db.interceptBefore(ctx, OpQuery, query, args)
+	defer func() {
+		ic.after(err)
+	}()
 	queryerCtx, ok := dc.ci.(driver.QueryerContext)
 	var queryer driver.Queryer
 	if !ok {
@@ -1531,13 +1724,12 @@ func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn fu
 	if ok {
 		var nvdargs []driver.NamedValue
 		var rowsi driver.Rows
-		var err error
 		withLock(dc, func() {
 			nvdargs, err = driverArgsConnLocked(dc.ci, nil, args)
 			if err != nil {
 				return
 			}
-			rowsi, err = ctxDriverQuery(ctx, queryerCtx, queryer, query, nvdargs)
+			rowsi, err = ctxDriverQuery(opCtx, queryerCtx, queryer, query, nvdargs)
 		})
 		if err != driver.ErrSkip {
 			if err != nil {
@@ -1546,7 +1738,7 @@ func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn fu
 			}
 			// Note: ownership of dc passes to the *Rows, to be freed
 			// with releaseConn.
-			rows := &Rows{
+			rows = &Rows{
 				dc:          dc,
 				releaseConn: releaseConn,
 				rowsi:       rowsi,
@@ -1557,9 +1749,8 @@ func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn fu
 	}
 
 	var si driver.Stmt
-	var err error
 	withLock(dc, func() {
-		si, err = ctxDriverPrepare(ctx, dc.ci, query)
+		si, err = ctxDriverPrepare(opCtx, dc.ci, query)
 	})
 	if err != nil {
 		releaseConn(err)
@@ -1567,7 +1758,7 @@ func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn fu
 	}
 
 	ds := &driverStmt{Locker: dc, si: si}
-	rowsi, err := rowsiFromStatement(ctx, dc.ci, ds, args...)
+	rowsi, err := rowsiFromStatement(opCtx, dc.ci, ds, args...)
 	if err != nil {
 		ds.Close()
 		releaseConn(err)
@@ -1576,7 +1767,7 @@ func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn fu
 
 	// Note: ownership of ci passes to the *Rows, to be freed
 	// with releaseConn.
-	rows := &Rows{
+	rows = &Rows{
 		dc:          dc,
 		releaseConn: releaseConn,
 		rowsi:       rowsi,
@@ -1648,9 +1839,13 @@ func (db *DB) begin(ctx context.Context, opts *TxOptions, strategy connReuseStra
 
 // beginDC starts a transaction. The provided dc must be valid and ready to use.
 func (db *DB) beginDC(ctx context.Context, dc *driverConn, release func(error), opts *TxOptions) (tx *Tx, err error) {
+	opCtx, ic := db.interceptBefore(ctx, OpBegin, "", nil)
+	defer func() {
+		ic.after(err)
+	}()
 	var txi driver.Tx
 	withLock(dc, func() {
-		txi, err = ctxDriverBegin(ctx, opts, dc.ci)
+		txi, err = ctxDriverBegin(opCtx, opts, dc.ci)
 	})
 	if err != nil {
 		release(err)
@@ -1822,6 +2017,40 @@ func (c *Conn) BeginTx(ctx context.Context, opts *TxOptions) (*Tx, error) {
 	return c.db.beginDC(ctx, dc, release, opts)
 }
 
+// Raw executes f exposing the underlying driver connection for the
+// duration of f. The driverConn must not be used outside of f.
+//
+// Once f returns and err is nil, the Conn will continue to be usable
+// until Conn.Close is called. If f returns driver.ErrBadConn or panics,
+// the Conn is closed and its driver connection discarded.
+func (c *Conn) Raw(f func(driverConn interface{}) error) (err error) {
+	var dc *driverConn
+	var release releaseConn
+
+	// grabConn takes a context to implement stmtConnGrabber, but the context is not used.
+	dc, release, err = c.grabConn(nil)
+	if err != nil {
+		return
+	}
+	fPanic := true
+	dc.Lock()
+	defer func() {
+		dc.Unlock()
+
+		// If f panics fPanic will remain true.
+		// Ensure an error is passed to release so the connection
+		// may be discarded.
+		if fPanic {
+			err = driver.ErrBadConn
+		}
+		release(err)
+	}()
+	err = f(dc.ci)
+	fPanic = false
+
+	return
+}
+
 // closemuRUnlockCondReleaseConn read unlocks closemu
 // as the sql operation is done with the dc.
 func (c *Conn) closemuRUnlockCondReleaseConn(err error) {
@@ -2004,6 +2233,7 @@ func (tx *Tx) Commit() error {
 		return ErrTxDone
 	}
 	var err error
+	_, ic := tx.db.interceptBefore(tx.ctx, OpCommit, "", nil)
 	withLock(tx.dc, func() {
 		err = tx.txi.Commit()
 	})
@@ -2011,6 +2241,7 @@ func (tx *Tx) Commit() error {
 		tx.closePrepared()
 	}
 	tx.close(err)
+	ic.after(err)
 	return err
 }
 
@@ -2021,9 +2252,11 @@ func (tx *Tx) rollback(discardConn bool) error {
 		return ErrTxDone
 	}
 	var err error
+	_, ic := tx.db.interceptBefore(tx.ctx, OpRollback, "", nil)
 	withLock(tx.dc, func() {
 		err = tx.txi.Rollback()
 	})
+	rollbackErr := err
 	if err != driver.ErrBadConn {
 		tx.closePrepared()
 	}
@@ -2031,6 +2264,7 @@ func (tx *Tx) rollback(discardConn bool) error {
 		err = driver.ErrBadConn
 	}
 	tx.close(err)
+	ic.after(rollbackErr)
 	return err
 }
 
@@ -2321,8 +2555,10 @@ func (s *Stmt) ExecContext(ctx context.Context, args ...interface{}) (Result, er
 			return nil, err
 		}
 
-		res, err = resultFromStatement(ctx, dc.ci, ds, args...)
+		opCtx, ic := s.db.interceptBefore(ctx, OpExec, s.query, args)
+		res, err = resultFromStatement(opCtx, dc.ci, ds, args...)
 		releaseConn(err)
+		ic.after(err)
 		if err != driver.ErrBadConn {
 			return res, err
 		}
@@ -2466,7 +2702,8 @@ func (s *Stmt) QueryContext(ctx context.Context, args ...interface{}) (*Rows, er
 			return nil, err
 		}
 
-		rowsi, err = rowsiFromStatement(ctx, dc.ci, ds, args...)
+		opCtx, ic := s.db.interceptBefore(ctx, OpQuery, s.query, args)
+		rowsi, err = rowsiFromStatement(opCtx, dc.ci, ds, args...)
 		if err == nil {
 			// Note: ownership of ci passes to the *Rows, to be freed
 			// with releaseConn.
@@ -2490,10 +2727,12 @@ func (s *Stmt) QueryContext(ctx context.Context, args ...interface{}) (*Rows, er
 				txctx = s.cg.txCtx()
 			}
 			rows.initContextClose(ctx, txctx)
+			ic.after(nil)
 			return rows, nil
 		}
 
 		releaseConn(err)
+		ic.after(err)
 		if err != driver.ErrBadConn {
 			return nil, err
 		}
@@ -2887,6 +3126,7 @@ func rowsColumnInfoSetupConnLocked(rowsi driver.Rows) []*ColumnType {
 //    *uint, *uint8, *uint16, *uint32, *uint64
 //    *bool
 //    *float32, *float64
+//    *time.Time
 //    *interface{}
 //    *RawBytes
 //    *Rows (cursor value)
@@ -2921,6 +3161,12 @@ func rowsColumnInfoSetupConnLocked(rowsi driver.Rows) []*ColumnType {
 // *time.Time, *interface{}, *string, or *[]byte. When converting to
 // the latter two, time.RFC3339Nano is used.
 //
+// Source values of type string or []byte may be scanned into *time.Time
+// if they hold a timestamp in RFC 3339 format or in the common
+// "2006-01-02 15:04:05" form, with optional fractional seconds and
+// zone offset. A date alone, such as "2006-01-02", is also accepted.
+// Timestamps without a zone are taken to be in UTC.
+//
 // Source values of type bool may be scanned into types *bool,
 // *interface{}, *string, *[]byte, or *RawBytes.
 //
@@ -1338,6 +1338,72 @@ func TestConnQuery(t *testing.T) {
 	}
 }
 
+func TestConnRaw(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.dc.ci.(*fakeConn).skipDirtySession = true
+	defer conn.Close()
+
+	sawFunc := false
+	err = conn.Raw(func(dc interface{}) error {
+		sawFunc = true
+		if _, ok := dc.(*fakeConn); !ok {
+			return fmt.Errorf("got %T want *fakeConn", dc)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sawFunc {
+		t.Fatal("Raw func not called")
+	}
+
+	func() {
+		defer func() {
+			x := recover()
+			if x == nil {
+				t.Fatal("expected panic")
+			}
+			conn.closemu.Lock()
+			closed := conn.dc == nil
+			conn.closemu.Unlock()
+			if !closed {
+				t.Fatal("expected connection to be closed after panic")
+			}
+		}()
+		err = conn.Raw(func(dc interface{}) error {
+			panic("Conn.Raw panic should return an error")
+		})
+		t.Fatal("expected panic from Raw func")
+	}()
+}
+
+func TestConnRawAfterClose(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	err = conn.Raw(func(interface{}) error {
+		t.Error("Raw func called on a closed Conn")
+		return nil
+	})
+	if err != ErrConnDone {
+		t.Errorf("Raw on closed Conn = %v; want ErrConnDone", err)
+	}
+}
+
 func TestCursorFake(t *testing.T) {
 	db := newTestDB(t, "people")
 	defer closeDB(t, db)
@@ -1671,6 +1737,42 @@ func TestNullInt64Param(t *testing.T) {
 	nullTestRun(t, spec)
 }
 
+func TestNullInt32Param(t *testing.T) {
+	spec := nullTestSpec{"nullint32", "int32", [6]nullTestRow{
+		{NullInt32{31, true}, 1, NullInt32{31, true}},
+		{NullInt32{-22, false}, 1, NullInt32{0, false}},
+		{22, 1, NullInt32{22, true}},
+		{NullInt32{33, true}, 1, NullInt32{33, true}},
+		{NullInt32{222, false}, 1, NullInt32{0, false}},
+		{0, NullInt32{31, false}, nil},
+	}}
+	nullTestRun(t, spec)
+}
+
+func TestNullInt16Param(t *testing.T) {
+	spec := nullTestSpec{"nullint16", "int16", [6]nullTestRow{
+		{NullInt16{31, true}, 1, NullInt16{31, true}},
+		{NullInt16{-22, false}, 1, NullInt16{0, false}},
+		{22, 1, NullInt16{22, true}},
+		{NullInt16{33, true}, 1, NullInt16{33, true}},
+		{NullInt16{222, false}, 1, NullInt16{0, false}},
+		{0, NullInt16{31, false}, nil},
+	}}
+	nullTestRun(t, spec)
+}
+
+func TestNullByteParam(t *testing.T) {
+	spec := nullTestSpec{"nullbyte", "byte", [6]nullTestRow{
+		{NullByte{31, true}, 1, NullByte{31, true}},
+		{NullByte{0, false}, 1, NullByte{0, false}},
+		{22, 1, NullByte{22, true}},
+		{NullByte{33, true}, 1, NullByte{33, true}},
+		{NullByte{222, false}, 1, NullByte{0, false}},
+		{0, NullByte{31, false}, nil},
+	}}
+	nullTestRun(t, spec)
+}
+
 func TestNullFloat64Param(t *testing.T) {
 	spec := nullTestSpec{"nullfloat64", "float64", [6]nullTestRow{
 		{NullFloat64{31.2, true}, 1, NullFloat64{31.2, true}},
@@ -1695,6 +1797,21 @@ func TestNullBoolParam(t *testing.T) {
 	nullTestRun(t, spec)
 }
 
+func TestNullTimeParam(t *testing.T) {
+	t0 := time.Time{}
+	t1 := time.Date(2000, 1, 1, 8, 9, 10, 11, time.UTC)
+	t2 := time.Date(2010, 1, 1, 8, 9, 10, 11, time.UTC)
+	spec := nullTestSpec{"nulldatetime", "notnulldatetime", [6]nullTestRow{
+		{NullTime{t1, true}, t2, NullTime{t1, true}},
+		{NullTime{t1, false}, t2, NullTime{t0, false}},
+		{t1, t2, NullTime{t1, true}},
+		{NullTime{t1, true}, t2, NullTime{t1, true}},
+		{NullTime{t1, false}, t2, NullTime{t0, false}},
+		{t2, NullTime{t1, false}, nil},
+	}}
+	nullTestRun(t, spec)
+}
+
 func nullTestRun(t *testing.T, spec nullTestSpec) {
 	db := newTestDB(t, "")
 	defer closeDB(t, db)
@@ -3513,6 +3630,202 @@ func TestStatsMaxIdleClosedTen(t *testing.T) {
 	}
 }
 
+func TestConnMaxIdleTime(t *testing.T) {
+	t0 := time.Unix(1000000, 0)
+	offset := time.Duration(0)
+
+	nowFunc = func() time.Time { return t0.Add(offset) }
+	defer func() { nowFunc = time.Now }()
+
+	db := newTestDB(t, "magicquery")
+	defer closeDB(t, db)
+
+	driver := db.Driver().(*fakeDriver)
+
+	// Force the number of open connections to 0 so we can get an accurate
+	// count for the test
+	db.clearAllConns(t)
+
+	driver.mu.Lock()
+	opens0 := driver.openCount
+	closes0 := driver.closeCount
+	driver.mu.Unlock()
+
+	db.SetMaxIdleConns(10)
+	db.SetMaxOpenConns(10)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first conn returns to the pool at t0, the second one
+	// 10 seconds later.
+	tx.Commit()
+	offset = 10 * time.Second
+	tx2.Commit()
+
+	offset = 15 * time.Second
+	db.SetConnMaxIdleTime(10 * time.Second)
+	db.mu.Lock()
+	closing := db.connectionCleanerRunLocked()
+	db.mu.Unlock()
+	for _, c := range closing {
+		c.Close()
+	}
+
+	if g, w := len(closing), 1; g != w {
+		t.Errorf("closing = %d; want %d", g, w)
+	}
+	if g, w := db.numFreeConns(), 1; g != w {
+		t.Errorf("free conns = %d; want %d", g, w)
+	}
+	if g, w := db.Stats().MaxIdleTimeClosed, int64(1); g != w {
+		t.Errorf("MaxIdleTimeClosed = %d; want %d", g, w)
+	}
+	if g, w := db.Stats().MaxLifetimeClosed, int64(0); g != w {
+		t.Errorf("MaxLifetimeClosed = %d; want %d", g, w)
+	}
+
+	driver.mu.Lock()
+	opens := driver.openCount - opens0
+	closes := driver.closeCount - closes0
+	driver.mu.Unlock()
+	if opens != 2 {
+		t.Errorf("opens = %d; want 2", opens)
+	}
+	if closes != 1 {
+		t.Errorf("closes = %d; want 1", closes)
+	}
+}
+
+func TestConnMaxIdleTimeOnReuse(t *testing.T) {
+	t0 := time.Unix(1000000, 0)
+	offset := time.Duration(0)
+
+	nowFunc = func() time.Time { return t0.Add(offset) }
+	defer func() { nowFunc = time.Now }()
+
+	db := newTestDB(t, "magicquery")
+	defer closeDB(t, db)
+	db.clearAllConns(t)
+	db.SetMaxIdleConns(10)
+	db.SetConnMaxIdleTime(10 * time.Second)
+
+	driver := db.Driver().(*fakeDriver)
+	driver.mu.Lock()
+	opens0 := driver.openCount
+	driver.mu.Unlock()
+
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	// The idle conn must not be handed out even though the cleaner
+	// has not closed it yet.
+	offset = 15 * time.Second
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	driver.mu.Lock()
+	opens := driver.openCount - opens0
+	driver.mu.Unlock()
+	if opens != 2 {
+		t.Errorf("opens = %d; want 2", opens)
+	}
+	if g, w := db.Stats().MaxIdleTimeClosed, int64(1); g != w {
+		t.Errorf("MaxIdleTimeClosed = %d; want %d", g, w)
+	}
+}
+
+func TestConnectionCleanerStats(t *testing.T) {
+	t0 := time.Unix(1000000, 0)
+	offset := time.Duration(0)
+
+	nowFunc = func() time.Time { return t0.Add(offset) }
+	defer func() { nowFunc = time.Now }()
+
+	db := newTestDB(t, "magicquery")
+	defer closeDB(t, db)
+	db.clearAllConns(t)
+	db.SetMaxIdleConns(10)
+	db.SetMaxOpenConns(10)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Commit()
+	offset = 20 * time.Second
+	tx2.Commit()
+
+	// The first conn has been idle for 25s and is closed for its idle
+	// time; the second was created 25s ago but idle for only 5s, and
+	// is closed for its lifetime.
+	offset = 25 * time.Second
+	db.SetConnMaxIdleTime(10 * time.Second)
+	db.SetConnMaxLifetime(20 * time.Second)
+	db.mu.Lock()
+	closing := db.connectionCleanerRunLocked()
+	db.mu.Unlock()
+	for _, c := range closing {
+		c.Close()
+	}
+
+	st := db.Stats()
+	if st.MaxIdleTimeClosed != 1 || st.MaxLifetimeClosed != 1 {
+		t.Errorf("MaxIdleTimeClosed, MaxLifetimeClosed = %d, %d; want 1, 1", st.MaxIdleTimeClosed, st.MaxLifetimeClosed)
+	}
+	if st.Idle != 0 {
+		t.Errorf("Idle = %d; want 0", st.Idle)
+	}
+}
+
+func TestValidatorDiscardsConn(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+
+	db.SetMaxIdleConns(1)
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	driver := db.Driver().(*fakeDriver)
+	driver.mu.Lock()
+	opens0 := driver.openCount
+	driver.mu.Unlock()
+
+	db.mu.Lock()
+	if len(db.freeConn) != 1 {
+		db.mu.Unlock()
+		t.Fatalf("free conns = %d; want 1", len(db.freeConn))
+	}
+	db.freeConn[0].ci.(*fakeConn).invalid = true
+	db.mu.Unlock()
+
+	var name string
+	if err := db.QueryRow("SELECT|people|name|age=?", 3).Scan(&name); err != nil {
+		t.Fatalf("QueryRow with invalid pooled conn: %v", err)
+	}
+	if name != "Chris" {
+		t.Errorf("name = %q; want Chris", name)
+	}
+
+	driver.mu.Lock()
+	opens := driver.openCount - opens0
+	driver.mu.Unlock()
+	if opens != 1 {
+		t.Errorf("opens = %d; want 1", opens)
+	}
+}
+
 type nvcDriver struct {
 	fakeDriver
 	skipNamedValueCheck bool
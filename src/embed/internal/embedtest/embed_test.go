@@ -0,0 +1,181 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embedtest
+
+import (
+	"embed"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+//go:embed testdata/h*.txt
+//go:embed c*.txt testdata/g*.txt
+var global embed.FS
+
+//go:embed c*txt
+var concurrency string
+
+//go:embed testdata/g*.txt
+var glass []byte
+
+//go:embed testdata
+var testDirAll embed.FS
+
+//go:embed testdata/*
+var testHiddenStar embed.FS
+
+//go:embed "testdata/hello.txt" `testdata/ken.txt`
+var quoted embed.FS
+
+type myString string
+
+//go:embed testdata/hello.txt
+var helloT myString
+
+func testFiles(t *testing.T, f fs.FS, name, data string) {
+	t.Helper()
+	d, err := fs.ReadFile(f, name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(d) != data {
+		t.Errorf("read %v = %q, want %q", name, d, data)
+	}
+}
+
+func testString(t *testing.T, s, name, data string) {
+	t.Helper()
+	if s != data {
+		t.Errorf("%v = %q, want %q", name, s, data)
+	}
+}
+
+func testDir(t *testing.T, f fs.FS, name string, expect ...string) {
+	t.Helper()
+	dirs, err := fs.ReadDir(f, name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var names []string
+	for _, d := range dirs {
+		name := d.Name()
+		if d.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	if !reflect.DeepEqual(names, expect) {
+		t.Errorf("readdir %v = %v, want %v", name, names, expect)
+	}
+}
+
+func TestGlobal(t *testing.T) {
+	testFiles(t, global, "concurrency.txt", "Concurrency is not parallelism.\n")
+	testFiles(t, global, "testdata/hello.txt", "hello, world\n")
+	testFiles(t, global, "testdata/glass.txt", "I can eat glass and it doesn't hurt me.\n")
+
+	testDir(t, global, ".", "concurrency.txt", "testdata/")
+	testDir(t, global, "testdata", "glass.txt", "hello.txt")
+
+	testString(t, concurrency, "concurrency", "Concurrency is not parallelism.\n")
+	testString(t, string(glass), "glass", "I can eat glass and it doesn't hurt me.\n")
+	testString(t, string(helloT), "helloT", "hello, world\n")
+}
+
+func TestQuoted(t *testing.T) {
+	testFiles(t, quoted, "testdata/hello.txt", "hello, world\n")
+	testFiles(t, quoted, "testdata/ken.txt", "If a program is too slow, it must have a loop.\n")
+}
+
+func TestHidden(t *testing.T) {
+	testDir(t, testDirAll, ".", "testdata/")
+	testDir(t, testDirAll, "testdata", "glass.txt", "hello.txt", "i/", "ken.txt")
+	testDir(t, testDirAll, "testdata/i", "i18n.txt", "j/")
+	testDir(t, testDirAll, "testdata/i/j", "k/")
+	testDir(t, testDirAll, "testdata/i/j/k", "k8s.txt")
+
+	testDir(t, testHiddenStar, "testdata", ".hidden/", "_hidden/", "glass.txt", "hello.txt", "i/", "ken.txt")
+	testDir(t, testHiddenStar, "testdata/.hidden", "fortune.txt")
+	testDir(t, testHiddenStar, "testdata/i", "i18n.txt", "j/")
+
+	if _, err := testDirAll.Open("testdata/.hidden/fortune.txt"); err == nil {
+		t.Errorf("Open(testdata/.hidden/fortune.txt) succeeded in testDirAll")
+	}
+}
+
+func TestFS(t *testing.T) {
+	f, err := testDirAll.Open("testdata/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name() != "hello.txt" || info.Size() != int64(len("hello, world\n")) || info.IsDir() || info.Mode() != 0444 {
+		t.Errorf("Stat = %v %v %v %v, want hello.txt 13 false -r--r--r--", info.Name(), info.Size(), info.IsDir(), info.Mode())
+	}
+	s, ok := f.(io.Seeker)
+	if !ok {
+		t.Fatalf("embedded file is not an io.Seeker")
+	}
+	if _, err := s.Seek(7, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "world\n" {
+		t.Errorf("read after seek = %q, want %q", b, "world\n")
+	}
+
+	d, err := testDirAll.Open("testdata/i/j")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if _, err := d.Read(make([]byte, 1)); err == nil {
+		t.Errorf("Read of directory succeeded")
+	}
+	rd, ok := d.(fs.ReadDirFile)
+	if !ok {
+		t.Fatalf("embedded directory is not an fs.ReadDirFile")
+	}
+	list, err := rd.ReadDir(1)
+	if err != nil || len(list) != 1 || list[0].Name() != "k" {
+		t.Fatalf("ReadDir(1) = %v, %v, want [k], nil", list, err)
+	}
+	if list, err := rd.ReadDir(1); err != io.EOF {
+		t.Fatalf("ReadDir(1) at end = %v, %v, want EOF", list, err)
+	}
+
+	if _, err := testDirAll.ReadFile("testdata"); err == nil {
+		t.Errorf("ReadFile of directory succeeded")
+	}
+	if _, err := testDirAll.ReadDir("testdata/hello.txt"); err == nil {
+		t.Errorf("ReadDir of file succeeded")
+	}
+	if _, err := testDirAll.Open("testdata/missing.txt"); err == nil {
+		t.Errorf("Open of missing file succeeded")
+	}
+	if _, err := testDirAll.Open("/testdata"); err == nil {
+		t.Errorf("Open of invalid path succeeded")
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	var empty embed.FS
+	testDir(t, empty, ".")
+	if _, err := empty.Open("x"); err == nil {
+		t.Errorf("Open(x) in empty FS succeeded")
+	}
+}
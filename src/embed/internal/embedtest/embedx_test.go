@@ -0,0 +1,37 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embedtest_test
+
+import (
+	"embed"
+	"testing"
+)
+
+//go:embed testdata/hello.txt
+var helloBytes []byte
+
+//go:embed testdata/i/i18n.txt testdata/i/j
+var xtestFS embed.FS
+
+func TestXGlobal(t *testing.T) {
+	if string(helloBytes) != "hello, world\n" {
+		t.Errorf("helloBytes = %q, want %q", helloBytes, "hello, world\n")
+	}
+
+	data, err := xtestFS.ReadFile("testdata/i/j/k/k8s.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "k8s\n" {
+		t.Errorf("k8s.txt = %q, want %q", data, "k8s\n")
+	}
+	list, err := xtestFS.ReadDir("testdata/i")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name() != "i18n.txt" || list[1].Name() != "j" || !list[1].IsDir() {
+		t.Errorf("ReadDir(testdata/i) = %v, want [i18n.txt j/]", list)
+	}
+}
@@ -8,7 +8,6 @@
 package json
 
 import (
-	"bytes"
 	"encoding"
 	"encoding/base64"
 	"fmt"
@@ -42,9 +41,12 @@ import (
 //
 // To unmarshal JSON into a struct, Unmarshal matches incoming object
 // keys to the keys used by Marshal (either the struct field name or its tag),
-// preferring an exact match but also accepting a case-insensitive match. By
+// preferring an exact match but also accepting a case-insensitive match
+// (see Decoder.UseCaseSensitiveFields for an alternative). By
 // default, object keys which don't have a corresponding struct field are
 // ignored (see Decoder.DisallowUnknownFields for an alternative).
+// If an object key appears more than once, the last value wins
+// (see Decoder.DisallowDuplicateFields for an alternative).
 //
 // To unmarshal JSON into an interface value,
 // Unmarshal stores one of these in the interface value:
@@ -90,7 +92,8 @@ import (
 // When unmarshaling quoted strings, invalid UTF-8 or
 // invalid UTF-16 surrogate pairs are not treated as an error.
 // Instead, they are replaced by the Unicode replacement
-// character U+FFFD.
+// character U+FFFD. A Decoder that had DisallowInvalidUTF8
+// called on it rejects invalid UTF-8 instead.
 //
 func Unmarshal(data []byte, v interface{}) error {
 	// Check for well-formedness.
@@ -120,12 +123,16 @@ type Unmarshaler interface {
 
 // An UnmarshalTypeError describes a JSON value that was
 // not appropriate for a value of a specific Go type.
+//
+// For an error returned by a Decoder, Offset counts the bytes read
+// from the start of its input, not of the value being decoded.
 type UnmarshalTypeError struct {
 	Value  string       // description of JSON value - "bool", "array", "number -5"
 	Type   reflect.Type // type of Go value it could not be assigned to
 	Offset int64        // error occurred after reading Offset bytes
 	Struct string       // name of the struct type containing the field
 	Field  string       // name of the field holding the Go value
+	Path   string       // RFC 6901 JSON Pointer to the JSON value within the value being decoded
 }
 
 func (e *UnmarshalTypeError) Error() string {
@@ -172,6 +179,12 @@ func (d *decodeState) unmarshal(v interface{}) error {
 		return &InvalidUnmarshalError{reflect.TypeOf(v)}
 	}
 
+	if d.disallowInvalidUTF8 {
+		if err := checkValidUTF8(d.data); err != nil {
+			return err
+		}
+	}
+
 	d.scan.reset()
 	d.scanWhile(scanSkipSpace)
 	// We decode rv not rv.Elem because the Unmarshaler interface
@@ -269,9 +282,43 @@ type decodeState struct {
 		Struct reflect.Type
 		Field  string
 	}
-	savedError            error
-	useNumber             bool
-	disallowUnknownFields bool
+	errorPath               []pathElem // object members and array elements being decoded
+	savedError              error
+	useNumber               bool
+	disallowUnknownFields   bool
+	caseSensitiveFields     bool
+	disallowDuplicateFields bool
+	disallowInvalidUTF8     bool
+}
+
+// A pathElem is one step in a JSON Pointer: an object key, or an
+// array index if index is not negative.
+type pathElem struct {
+	key   []byte
+	index int
+}
+
+// jsonPointer returns path as an RFC 6901 JSON Pointer.
+func jsonPointer(path []pathElem) string {
+	var b []byte
+	for _, e := range path {
+		b = append(b, '/')
+		if e.index >= 0 {
+			b = strconv.AppendInt(b, int64(e.index), 10)
+			continue
+		}
+		for _, c := range e.key {
+			switch c {
+			case '~':
+				b = append(b, "~0"...)
+			case '/':
+				b = append(b, "~1"...)
+			default:
+				b = append(b, c)
+			}
+		}
+	}
+	return string(b)
 }
 
 // readIndex returns the position of the last byte read.
@@ -279,6 +326,23 @@ func (d *decodeState) readIndex() int {
 	return d.off - 1
 }
 
+// checkValidUTF8 returns a SyntaxError if data, a valid JSON value,
+// contains invalid UTF-8. The scanner only accepts bytes outside the
+// ASCII range within strings, so that is where any must be.
+func checkValidUTF8(data []byte) error {
+	if utf8.Valid(data) {
+		return nil
+	}
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return &SyntaxError{msg: "invalid UTF-8 in string", Offset: int64(i + 1), Path: pathAt(data[:i])}
+		}
+		i += size
+	}
+	return nil
+}
+
 // phasePanicMsg is used as a panic message when we end up with something that
 // shouldn't happen. It can indicate a bug in the JSON decoder, or that
 // something is editing the data slice while the decoder executes.
@@ -290,6 +354,7 @@ func (d *decodeState) init(data []byte) *decodeState {
 	d.savedError = nil
 	d.errorContext.Struct = nil
 	d.errorContext.Field = ""
+	d.errorPath = d.errorPath[:0]
 	return d
 }
 
@@ -302,14 +367,21 @@ func (d *decodeState) saveError(err error) {
 }
 
 // addErrorContext returns a new error enhanced with information from d.errorContext
+// and d.errorPath.
 func (d *decodeState) addErrorContext(err error) error {
-	if d.errorContext.Struct != nil || d.errorContext.Field != "" {
-		switch err := err.(type) {
-		case *UnmarshalTypeError:
-			err.Struct = d.errorContext.Struct.Name()
-			err.Field = d.errorContext.Field
-			return err
-		}
+	switch err := err.(type) {
+	case *UnmarshalTypeError:
+		// The error may come from an Unmarshaler, which may return
+		// the same error value more than once, so modify a copy.
+		e := *err
+		if d.errorContext.Struct != nil || d.errorContext.Field != "" {
+			e.Struct = d.errorContext.Struct.Name()
+			e.Field = d.errorContext.Field
+		}
+		// An error from an Unmarshaler may already hold a path
+		// relative to the value being decoded.
+		e.Path = jsonPointer(d.errorPath) + e.Path
+		return &e
 	}
 	return err
 }
@@ -555,6 +627,7 @@ func (d *decodeState) array(v reflect.Value) error {
 			}
 		}
 
+		d.errorPath = append(d.errorPath, pathElem{index: i})
 		if i < v.Len() {
 			// Decode into element.
 			if err := d.value(v.Index(i)); err != nil {
@@ -566,6 +639,7 @@ func (d *decodeState) array(v reflect.Value) error {
 				return err
 			}
 		}
+		d.errorPath = d.errorPath[:len(d.errorPath)-1]
 		i++
 
 		// Next token must be , or ].
@@ -625,7 +699,7 @@ func (d *decodeState) object(v reflect.Value) error {
 		return nil
 	}
 
-	var fields []field
+	var fields structFields
 
 	// Check type of target:
 	//   struct or
@@ -661,6 +735,10 @@ func (d *decodeState) object(v reflect.Value) error {
 	var mapElem reflect.Value
 	originalErrorContext := d.errorContext
 
+	// seen records the keys, or for a struct the fields, already
+	// decoded, if d.disallowDuplicateFields is set.
+	var seen map[string]bool
+
 	for {
 		// Read opening " of string key or closing }.
 		d.scanWhile(scanSkipSpace)
@@ -680,10 +758,15 @@ func (d *decodeState) object(v reflect.Value) error {
 		if !ok {
 			panic(phasePanicMsg)
 		}
+		d.errorPath = append(d.errorPath, pathElem{key: key, index: -1})
 
 		// Figure out field corresponding to key.
 		var subv reflect.Value
 		destring := false // whether the value is wrapped in a string to be decoded first
+		var seenKey string
+		if d.disallowDuplicateFields {
+			seenKey = string(key)
+		}
 
 		if v.Kind() == reflect.Map {
 			elemType := t.Elem()
@@ -695,17 +778,21 @@ func (d *decodeState) object(v reflect.Value) error {
 			subv = mapElem
 		} else {
 			var f *field
-			for i := range fields {
-				ff := &fields[i]
-				if bytes.Equal(ff.nameBytes, key) {
-					f = ff
-					break
-				}
-				if f == nil && ff.equalFold(ff.nameBytes, key) {
-					f = ff
+			if i, ok := fields.nameIndex[string(key)]; ok {
+				f = &fields.list[i]
+			} else if !d.caseSensitiveFields {
+				for i := range fields.list {
+					ff := &fields.list[i]
+					if ff.equalFold(ff.nameBytes, key) {
+						f = ff
+						break
+					}
 				}
 			}
 			if f != nil {
+				if d.disallowDuplicateFields {
+					seenKey = f.name
+				}
 				subv = v
 				destring = f.quoted
 				for _, i := range f.index {
@@ -736,6 +823,15 @@ func (d *decodeState) object(v reflect.Value) error {
 				d.saveError(fmt.Errorf("json: unknown field %q", key))
 			}
 		}
+		if d.disallowDuplicateFields {
+			if seen[seenKey] {
+				d.saveError(fmt.Errorf("json: duplicate field %q", key))
+			}
+			if seen == nil {
+				seen = make(map[string]bool)
+			}
+			seen[seenKey] = true
+		}
 
 		// Read : before value.
 		if d.opcode == scanSkipSpace {
@@ -805,6 +901,7 @@ func (d *decodeState) object(v reflect.Value) error {
 				v.SetMapIndex(kv, subv)
 			}
 		}
+		d.errorPath = d.errorPath[:len(d.errorPath)-1]
 
 		// Next token must be , or }.
 		if d.opcode == scanSkipSpace {
@@ -1093,6 +1190,10 @@ func (d *decodeState) objectInterface() map[string]interface{} {
 		}
 		d.scanWhile(scanSkipSpace)
 
+		if _, ok := m[key]; ok && d.disallowDuplicateFields {
+			d.saveError(fmt.Errorf("json: duplicate field %q", key))
+		}
+
 		// Read value.
 		m[key] = d.valueInterface()
 
@@ -390,13 +390,16 @@ type mapStringToStringData struct {
 }
 
 type unmarshalTest struct {
-	in                    string
-	ptr                   interface{}
-	out                   interface{}
-	err                   error
-	useNumber             bool
-	golden                bool
-	disallowUnknownFields bool
+	in                      string
+	ptr                     interface{}
+	out                     interface{}
+	err                     error
+	useNumber               bool
+	golden                  bool
+	disallowUnknownFields   bool
+	caseSensitiveFields     bool
+	disallowDuplicateFields bool
+	disallowInvalidUTF8     bool
 }
 
 type B struct {
@@ -418,11 +421,11 @@ var unmarshalTests = []unmarshalTest{
 	{in: `"g-clef: \uD834\uDD1E"`, ptr: new(string), out: "g-clef: \U0001D11E"},
 	{in: `"invalid: \uD834x\uDD1E"`, ptr: new(string), out: "invalid: \uFFFDx\uFFFD"},
 	{in: "null", ptr: new(interface{}), out: nil},
-	{in: `{"X": [1,2,3], "Y": 4}`, ptr: new(T), out: T{Y: 4}, err: &UnmarshalTypeError{"array", reflect.TypeOf(""), 7, "T", "X"}},
-	{in: `{"X": 23}`, ptr: new(T), out: T{}, err: &UnmarshalTypeError{"number", reflect.TypeOf(""), 8, "T", "X"}}, {in: `{"x": 1}`, ptr: new(tx), out: tx{}},
+	{in: `{"X": [1,2,3], "Y": 4}`, ptr: new(T), out: T{Y: 4}, err: &UnmarshalTypeError{"array", reflect.TypeOf(""), 7, "T", "X", "/X"}},
+	{in: `{"X": 23}`, ptr: new(T), out: T{}, err: &UnmarshalTypeError{"number", reflect.TypeOf(""), 8, "T", "X", "/X"}}, {in: `{"x": 1}`, ptr: new(tx), out: tx{}},
 	{in: `{"x": 1}`, ptr: new(tx), out: tx{}},
 	{in: `{"x": 1}`, ptr: new(tx), err: fmt.Errorf("json: unknown field \"x\""), disallowUnknownFields: true},
-	{in: `{"S": 23}`, ptr: new(W), out: W{}, err: &UnmarshalTypeError{"number", reflect.TypeOf(SS("")), 0, "W", "S"}},
+	{in: `{"S": 23}`, ptr: new(W), out: W{}, err: &UnmarshalTypeError{"number", reflect.TypeOf(SS("")), 0, "W", "S", "/S"}},
 	{in: `{"F1":1,"F2":2,"F3":3}`, ptr: new(V), out: V{F1: float64(1), F2: int32(2), F3: Number("3")}},
 	{in: `{"F1":1,"F2":2,"F3":3}`, ptr: new(V), out: V{F1: Number("1"), F2: int32(2), F3: Number("3")}, useNumber: true},
 	{in: `{"k1":1,"k2":"s","k3":[1,2.0,3e-3],"k4":{"kk1":"s","kk2":2}}`, ptr: new(interface{}), out: ifaceNumAsFloat64},
@@ -445,21 +448,49 @@ var unmarshalTests = []unmarshalTest{
 	{in: `{"alphabet": "xyz"}`, ptr: new(U), out: U{}},
 	{in: `{"alphabet": "xyz"}`, ptr: new(U), err: fmt.Errorf("json: unknown field \"alphabet\""), disallowUnknownFields: true},
 
+	// case-sensitive field matching
+	{in: `{"y": 1}`, ptr: new(T), out: T{}, caseSensitiveFields: true},
+	{in: `{"Y": 1}`, ptr: new(T), out: T{Y: 1}, caseSensitiveFields: true},
+	{in: `{"y": 1}`, ptr: new(T), err: fmt.Errorf("json: unknown field \"y\""), caseSensitiveFields: true, disallowUnknownFields: true},
+
+	// duplicate object keys
+	{in: `{"Y": 1, "Y": 2}`, ptr: new(T), out: T{Y: 2}},
+	{in: `{"Y": 1, "Y": 2}`, ptr: new(T), err: fmt.Errorf("json: duplicate field \"Y\""), disallowDuplicateFields: true},
+	{in: `{"Y": 1, "y": 2}`, ptr: new(T), err: fmt.Errorf("json: duplicate field \"y\""), disallowDuplicateFields: true},
+	{in: `{"Y": 1, "y": 2}`, ptr: new(T), out: T{Y: 1}, disallowDuplicateFields: true, caseSensitiveFields: true},
+	{in: `{"a": 1, "a": 2}`, ptr: new(map[string]int), out: map[string]int{"a": 2}},
+	{in: `{"a": 1, "b": 2}`, ptr: new(map[string]int), out: map[string]int{"a": 1, "b": 2}, disallowDuplicateFields: true},
+	{in: `{"a": 1, "a": 2}`, ptr: new(map[string]int), err: fmt.Errorf("json: duplicate field \"a\""), disallowDuplicateFields: true},
+	{in: `{"a": 1, "a": 2}`, ptr: new(interface{}), err: fmt.Errorf("json: duplicate field \"a\""), disallowDuplicateFields: true},
+	{in: `[{"a": 1}, {"a": 2}]`, ptr: new([]map[string]int), out: []map[string]int{{"a": 1}, {"a": 2}}, disallowDuplicateFields: true},
+
+	// invalid UTF-8
+	{in: "\"a\xffb\"", ptr: new(string), out: "a\ufffdb"},
+	{in: "\"a\xffb\"", ptr: new(string), err: &SyntaxError{msg: "invalid UTF-8 in string", Offset: 3}, disallowInvalidUTF8: true},
+	{in: "{\"\xff\": 1}", ptr: new(map[string]int), err: &SyntaxError{msg: "invalid UTF-8 in string", Offset: 3}, disallowInvalidUTF8: true},
+	{in: "\"a\u00ffb\"", ptr: new(string), out: "a\u00ffb", disallowInvalidUTF8: true},
+
+	// error paths
+	{in: `[1, 2, "x"]`, ptr: new([]int), err: &UnmarshalTypeError{Value: "string", Type: reflect.TypeOf(0), Offset: 10, Path: "/2"}},
+	{in: `{"a/b~c": [true]}`, ptr: new(map[string][]int), err: &UnmarshalTypeError{Value: "bool", Type: reflect.TypeOf(0), Offset: 15, Path: "/a~1b~0c/0"}},
+	{in: `{"X": "x", "Y": [{}]}`, ptr: new(T), err: &UnmarshalTypeError{Value: "array", Type: reflect.TypeOf(0), Offset: 17, Struct: "T", Field: "Y", Path: "/Y"}},
+	{in: `[[1], [2, {"a": x}]]`, err: &SyntaxError{msg: "invalid character 'x' looking for beginning of value", Offset: 17, Path: "/1/1/a"}},
+
 	// syntax errors
-	{in: `{"X": "foo", "Y"}`, err: &SyntaxError{"invalid character '}' after object key", 17}},
-	{in: `[1, 2, 3+]`, err: &SyntaxError{"invalid character '+' after array element", 9}},
-	{in: `{"X":12x}`, err: &SyntaxError{"invalid character 'x' after object key:value pair", 8}, useNumber: true},
-	{in: `[2, 3`, err: &SyntaxError{msg: "unexpected end of JSON input", Offset: 5}},
+	{in: `{"X": "foo", "Y"}`, err: &SyntaxError{msg: "invalid character '}' after object key", Offset: 17, Path: "/Y"}},
+	{in: `[1, 2, 3+]`, err: &SyntaxError{msg: "invalid character '+' after array element", Offset: 9, Path: "/2"}},
+	{in: `{"X":12x}`, err: &SyntaxError{msg: "invalid character 'x' after object key:value pair", Offset: 8, Path: "/X"}, useNumber: true},
+	{in: `[2, 3`, err: &SyntaxError{msg: "unexpected end of JSON input", Offset: 5, Path: "/1"}},
 
 	// raw value errors
-	{in: "\x01 42", err: &SyntaxError{"invalid character '\\x01' looking for beginning of value", 1}},
-	{in: " 42 \x01", err: &SyntaxError{"invalid character '\\x01' after top-level value", 5}},
-	{in: "\x01 true", err: &SyntaxError{"invalid character '\\x01' looking for beginning of value", 1}},
-	{in: " false \x01", err: &SyntaxError{"invalid character '\\x01' after top-level value", 8}},
-	{in: "\x01 1.2", err: &SyntaxError{"invalid character '\\x01' looking for beginning of value", 1}},
-	{in: " 3.4 \x01", err: &SyntaxError{"invalid character '\\x01' after top-level value", 6}},
-	{in: "\x01 \"string\"", err: &SyntaxError{"invalid character '\\x01' looking for beginning of value", 1}},
-	{in: " \"string\" \x01", err: &SyntaxError{"invalid character '\\x01' after top-level value", 11}},
+	{in: "\x01 42", err: &SyntaxError{msg: "invalid character '\\x01' looking for beginning of value", Offset: 1}},
+	{in: " 42 \x01", err: &SyntaxError{msg: "invalid character '\\x01' after top-level value", Offset: 5}},
+	{in: "\x01 true", err: &SyntaxError{msg: "invalid character '\\x01' looking for beginning of value", Offset: 1}},
+	{in: " false \x01", err: &SyntaxError{msg: "invalid character '\\x01' after top-level value", Offset: 8}},
+	{in: "\x01 1.2", err: &SyntaxError{msg: "invalid character '\\x01' looking for beginning of value", Offset: 1}},
+	{in: " 3.4 \x01", err: &SyntaxError{msg: "invalid character '\\x01' after top-level value", Offset: 6}},
+	{in: "\x01 \"string\"", err: &SyntaxError{msg: "invalid character '\\x01' looking for beginning of value", Offset: 1}},
+	{in: " \"string\" \x01", err: &SyntaxError{msg: "invalid character '\\x01' after top-level value", Offset: 11}},
 
 	// array tests
 	{in: `[1, 2, 3]`, ptr: new([3]int), out: [3]int{1, 2, 3}},
@@ -541,32 +572,32 @@ var unmarshalTests = []unmarshalTest{
 	{
 		in:  `{"abc":"abc"}`,
 		ptr: new(map[int]string),
-		err: &UnmarshalTypeError{Value: "number abc", Type: reflect.TypeOf(0), Offset: 2},
+		err: &UnmarshalTypeError{Value: "number abc", Type: reflect.TypeOf(0), Offset: 2, Path: "/abc"},
 	},
 	{
 		in:  `{"256":"abc"}`,
 		ptr: new(map[uint8]string),
-		err: &UnmarshalTypeError{Value: "number 256", Type: reflect.TypeOf(uint8(0)), Offset: 2},
+		err: &UnmarshalTypeError{Value: "number 256", Type: reflect.TypeOf(uint8(0)), Offset: 2, Path: "/256"},
 	},
 	{
 		in:  `{"128":"abc"}`,
 		ptr: new(map[int8]string),
-		err: &UnmarshalTypeError{Value: "number 128", Type: reflect.TypeOf(int8(0)), Offset: 2},
+		err: &UnmarshalTypeError{Value: "number 128", Type: reflect.TypeOf(int8(0)), Offset: 2, Path: "/128"},
 	},
 	{
 		in:  `{"-1":"abc"}`,
 		ptr: new(map[uint8]string),
-		err: &UnmarshalTypeError{Value: "number -1", Type: reflect.TypeOf(uint8(0)), Offset: 2},
+		err: &UnmarshalTypeError{Value: "number -1", Type: reflect.TypeOf(uint8(0)), Offset: 2, Path: "/-1"},
 	},
 	{
 		in:  `{"F":{"a":2,"3":4}}`,
 		ptr: new(map[string]map[int]int),
-		err: &UnmarshalTypeError{Value: "number a", Type: reflect.TypeOf(int(0)), Offset: 7},
+		err: &UnmarshalTypeError{Value: "number a", Type: reflect.TypeOf(int(0)), Offset: 7, Path: "/F/a"},
 	},
 	{
 		in:  `{"F":{"a":2,"3":4}}`,
 		ptr: new(map[string]map[uint]int),
-		err: &UnmarshalTypeError{Value: "number a", Type: reflect.TypeOf(uint(0)), Offset: 7},
+		err: &UnmarshalTypeError{Value: "number a", Type: reflect.TypeOf(uint(0)), Offset: 7, Path: "/F/a"},
 	},
 
 	// Map keys can be encoding.TextUnmarshalers.
@@ -819,6 +850,7 @@ var unmarshalTests = []unmarshalTest{
 			Field:  "F2",
 			Type:   reflect.TypeOf(int32(0)),
 			Offset: 20,
+			Path:   "/V/F2",
 		},
 	},
 	{
@@ -830,6 +862,7 @@ var unmarshalTests = []unmarshalTest{
 			Field:  "F2",
 			Type:   reflect.TypeOf(int32(0)),
 			Offset: 30,
+			Path:   "/V/F2",
 		},
 	},
 
@@ -904,12 +937,12 @@ var unmarshalTests = []unmarshalTest{
 	{
 		in:  `{"data":{"test1": "bob", "test2": 123}}`,
 		ptr: new(mapStringToStringData),
-		err: &UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(""), Offset: 37, Struct: "mapStringToStringData", Field: "data"},
+		err: &UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(""), Offset: 37, Struct: "mapStringToStringData", Field: "data", Path: "/data/test2"},
 	},
 	{
 		in:  `{"data":{"test1": 123, "test2": "bob"}}`,
 		ptr: new(mapStringToStringData),
-		err: &UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(""), Offset: 21, Struct: "mapStringToStringData", Field: "data"},
+		err: &UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(""), Offset: 21, Struct: "mapStringToStringData", Field: "data", Path: "/data/test1"},
 	},
 
 	// trying to decode JSON arrays or objects via TextUnmarshaler
@@ -1044,6 +1077,15 @@ func TestUnmarshal(t *testing.T) {
 		if tt.disallowUnknownFields {
 			dec.DisallowUnknownFields()
 		}
+		if tt.caseSensitiveFields {
+			dec.UseCaseSensitiveFields()
+		}
+		if tt.disallowDuplicateFields {
+			dec.DisallowDuplicateFields()
+		}
+		if tt.disallowInvalidUTF8 {
+			dec.DisallowInvalidUTF8()
+		}
 		if err := dec.Decode(v.Interface()); !reflect.DeepEqual(err, tt.err) {
 			t.Errorf("#%d: %v, want %v", i, err, tt.err)
 			continue
@@ -1957,6 +1999,47 @@ func TestUnmarshalTypeError(t *testing.T) {
 	}
 }
 
+var errSharedType = &UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(""), Path: "/v"}
+
+type sharedTypeErrorUnmarshaler struct{}
+
+func (sharedTypeErrorUnmarshaler) UnmarshalJSON([]byte) error { return errSharedType }
+
+func TestUnmarshalTypeErrorNotModified(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		var v struct{ A []sharedTypeErrorUnmarshaler }
+		err := Unmarshal([]byte(`{"A": [1]}`), &v)
+		ute, ok := err.(*UnmarshalTypeError)
+		if !ok || ute.Path != "/A/0/v" || ute.Field != "A" {
+			t.Fatalf("Unmarshal error = %#v; want Path /A/0/v and Field A", err)
+		}
+	}
+	if errSharedType.Path != "/v" || errSharedType.Field != "" {
+		t.Errorf("Unmarshaler's error modified to %#v", errSharedType)
+	}
+}
+
+var errSharedSyntax = &SyntaxError{msg: "shared", Offset: 1}
+
+type sharedSyntaxErrorUnmarshaler struct{}
+
+func (sharedSyntaxErrorUnmarshaler) UnmarshalJSON([]byte) error { return errSharedSyntax }
+
+func TestDecoderSyntaxErrorNotModified(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`[1][2][3]`))
+	for i := 0; i < 3; i++ {
+		var v []sharedSyntaxErrorUnmarshaler
+		err := dec.Decode(&v)
+		se, ok := err.(*SyntaxError)
+		if want := int64(3*i + 1); !ok || se.Offset != want {
+			t.Fatalf("Decode %d error = %#v; want Offset %d", i, err, want)
+		}
+	}
+	if errSharedSyntax.Offset != 1 {
+		t.Errorf("Unmarshaler's error modified to %#v", errSharedSyntax)
+	}
+}
+
 var unmarshalSyntaxTests = []string{
 	"tru",
 	"fals",
@@ -49,11 +49,14 @@ import (
 // to keep some browsers from misinterpreting JSON output as HTML.
 // Ampersand "&" is also escaped to "\u0026" for the same reason.
 // This escaping can be disabled using an Encoder that had SetEscapeHTML(false)
-// called on it.
+// called on it. An Encoder that had SetDisallowInvalidUTF8(true) called on it
+// returns an InvalidUTF8Error instead of coercing strings.
 //
 // Array and slice values encode as JSON arrays, except that
 // []byte encodes as a base64-encoded string, and a nil slice
-// encodes as the null JSON value.
+// encodes as the null JSON value. An Encoder that had SetNilAsEmpty(true)
+// called on it encodes a nil slice as an empty array, or as an empty
+// string for []byte.
 //
 // Struct values encode as JSON objects.
 // Each exported struct field becomes a member of the object, using the
@@ -71,6 +74,14 @@ import (
 // false, 0, a nil pointer, a nil interface value, and any empty array,
 // slice, map, or string.
 //
+// The "omitzero" option specifies that the field should be omitted
+// from the encoding if the field has a zero value. If the field type
+// has an IsZero() bool method, as time.Time does, that method is used
+// to decide; otherwise the field is zero if it is the zero value of its
+// type, which for a struct means that every field is zero. If both
+// "omitempty" and "omitzero" are given, the field is omitted if it is
+// either empty or zero.
+//
 // As a special case, if the field tag is "-", the field is always omitted.
 // Note that a field with name "-" can still be generated using the tag "-,".
 //
@@ -89,6 +100,10 @@ import (
 //   // Note the leading comma.
 //   Field int `json:",omitempty"`
 //
+//   // Field appears in JSON as key "when", and is omitted
+//   // from the object if Field.IsZero() reports true.
+//   Field time.Time `json:"when,omitzero"`
+//
 //   // Field is ignored by this package.
 //   Field int `json:"-"`
 //
@@ -140,6 +155,9 @@ import (
 //   - encoding.TextMarshalers are marshaled
 //   - integer keys are converted to strings
 //
+// A nil map encodes as the null JSON value, or as an empty object
+// by an Encoder that had SetNilAsEmpty(true) called on it.
+//
 // Pointer values encode as the value pointed to.
 // A nil pointer encodes as the null JSON value.
 //
@@ -249,8 +267,8 @@ func (e *UnsupportedValueError) Error() string {
 // attempting to encode a string value with invalid UTF-8 sequences.
 // As of Go 1.2, Marshal instead coerces the string to valid UTF-8 by
 // replacing invalid bytes with the Unicode replacement rune U+FFFD.
-//
-// Deprecated: No longer used; kept for compatibility.
+// An Encoder that had SetDisallowInvalidUTF8(true) called on it
+// returns an InvalidUTF8Error for such strings.
 type InvalidUTF8Error struct {
 	S string // the whole string value that caused the error
 }
@@ -339,6 +357,11 @@ type encOpts struct {
 	quoted bool
 	// escapeHTML causes '<', '>', and '&' to be escaped in JSON strings.
 	escapeHTML bool
+	// nilAsEmpty causes nil slices and maps to be encoded as [] and {}.
+	nilAsEmpty bool
+	// disallowInvalidUTF8 causes strings with invalid UTF-8 to be
+	// rejected rather than coerced.
+	disallowInvalidUTF8 bool
 }
 
 type encoderFunc func(e *encodeState, v reflect.Value, opts encOpts)
@@ -488,7 +511,7 @@ func textMarshalerEncoder(e *encodeState, v reflect.Value, opts encOpts) {
 	if err != nil {
 		e.error(&MarshalerError{v.Type(), err})
 	}
-	e.stringBytes(b, opts.escapeHTML)
+	e.stringBytes(b, opts)
 }
 
 func addrTextMarshalerEncoder(e *encodeState, v reflect.Value, opts encOpts) {
@@ -502,7 +525,7 @@ func addrTextMarshalerEncoder(e *encodeState, v reflect.Value, opts encOpts) {
 	if err != nil {
 		e.error(&MarshalerError{v.Type(), err})
 	}
-	e.stringBytes(b, opts.escapeHTML)
+	e.stringBytes(b, opts)
 }
 
 func boolEncoder(e *encodeState, v reflect.Value, opts encOpts) {
@@ -602,13 +625,16 @@ func stringEncoder(e *encodeState, v reflect.Value, opts encOpts) {
 		return
 	}
 	if opts.quoted {
+		if opts.disallowInvalidUTF8 && !utf8.ValidString(v.String()) {
+			e.error(&InvalidUTF8Error{v.String()})
+		}
 		sb, err := Marshal(v.String())
 		if err != nil {
 			e.error(err)
 		}
-		e.string(string(sb), opts.escapeHTML)
+		e.string(string(sb), opts)
 	} else {
-		e.string(v.String(), opts.escapeHTML)
+		e.string(v.String(), opts)
 	}
 }
 
@@ -625,14 +651,14 @@ func unsupportedTypeEncoder(e *encodeState, v reflect.Value, _ encOpts) {
 }
 
 type structEncoder struct {
-	fields []field
+	fields structFields
 }
 
 func (se structEncoder) encode(e *encodeState, v reflect.Value, opts encOpts) {
 	next := byte('{')
 FieldLoop:
-	for i := range se.fields {
-		f := &se.fields[i]
+	for i := range se.fields.list {
+		f := &se.fields.list[i]
 
 		// Find the nested struct field by following f.index.
 		fv := v
@@ -646,7 +672,8 @@ FieldLoop:
 			fv = fv.Field(i)
 		}
 
-		if f.omitEmpty && isEmptyValue(fv) {
+		if f.omitEmpty && isEmptyValue(fv) ||
+			f.omitZero && f.isZero(fv) {
 			continue
 		}
 		e.WriteByte(next)
@@ -677,7 +704,11 @@ type mapEncoder struct {
 
 func (me mapEncoder) encode(e *encodeState, v reflect.Value, opts encOpts) {
 	if v.IsNil() {
-		e.WriteString("null")
+		if opts.nilAsEmpty {
+			e.WriteString("{}")
+		} else {
+			e.WriteString("null")
+		}
 		return
 	}
 	e.WriteByte('{')
@@ -697,7 +728,7 @@ func (me mapEncoder) encode(e *encodeState, v reflect.Value, opts encOpts) {
 		if i > 0 {
 			e.WriteByte(',')
 		}
-		e.string(kv.s, opts.escapeHTML)
+		e.string(kv.s, opts)
 		e.WriteByte(':')
 		me.elemEnc(e, v.MapIndex(kv.v), opts)
 	}
@@ -718,9 +749,13 @@ func newMapEncoder(t reflect.Type) encoderFunc {
 	return me.encode
 }
 
-func encodeByteSlice(e *encodeState, v reflect.Value, _ encOpts) {
+func encodeByteSlice(e *encodeState, v reflect.Value, opts encOpts) {
 	if v.IsNil() {
-		e.WriteString("null")
+		if opts.nilAsEmpty {
+			e.WriteString(`""`)
+		} else {
+			e.WriteString("null")
+		}
 		return
 	}
 	s := v.Bytes()
@@ -755,7 +790,11 @@ type sliceEncoder struct {
 
 func (se sliceEncoder) encode(e *encodeState, v reflect.Value, opts encOpts) {
 	if v.IsNil() {
-		e.WriteString("null")
+		if opts.nilAsEmpty {
+			e.WriteString("[]")
+		} else {
+			e.WriteString("null")
+		}
 		return
 	}
 	se.arrayEnc(e, v, opts)
@@ -884,12 +923,12 @@ func (w *reflectWithString) resolve() error {
 }
 
 // NOTE: keep in sync with stringBytes below.
-func (e *encodeState) string(s string, escapeHTML bool) {
+func (e *encodeState) string(s string, opts encOpts) {
 	e.WriteByte('"')
 	start := 0
 	for i := 0; i < len(s); {
 		if b := s[i]; b < utf8.RuneSelf {
-			if htmlSafeSet[b] || (!escapeHTML && safeSet[b]) {
+			if htmlSafeSet[b] || (!opts.escapeHTML && safeSet[b]) {
 				i++
 				continue
 			}
@@ -922,6 +961,9 @@ func (e *encodeState) string(s string, escapeHTML bool) {
 		}
 		c, size := utf8.DecodeRuneInString(s[i:])
 		if c == utf8.RuneError && size == 1 {
+			if opts.disallowInvalidUTF8 {
+				e.error(&InvalidUTF8Error{s})
+			}
 			if start < i {
 				e.WriteString(s[start:i])
 			}
@@ -956,12 +998,12 @@ func (e *encodeState) string(s string, escapeHTML bool) {
 }
 
 // NOTE: keep in sync with string above.
-func (e *encodeState) stringBytes(s []byte, escapeHTML bool) {
+func (e *encodeState) stringBytes(s []byte, opts encOpts) {
 	e.WriteByte('"')
 	start := 0
 	for i := 0; i < len(s); {
 		if b := s[i]; b < utf8.RuneSelf {
-			if htmlSafeSet[b] || (!escapeHTML && safeSet[b]) {
+			if htmlSafeSet[b] || (!opts.escapeHTML && safeSet[b]) {
 				i++
 				continue
 			}
@@ -994,6 +1036,9 @@ func (e *encodeState) stringBytes(s []byte, escapeHTML bool) {
 		}
 		c, size := utf8.DecodeRune(s[i:])
 		if c == utf8.RuneError && size == 1 {
+			if opts.disallowInvalidUTF8 {
+				e.error(&InvalidUTF8Error{string(s)})
+			}
 			if start < i {
 				e.Write(s[start:i])
 			}
@@ -1040,9 +1085,18 @@ type field struct {
 	index     []int
 	typ       reflect.Type
 	omitEmpty bool
+	omitZero  bool
 	quoted    bool
 
 	encoder encoderFunc
+	isZero  func(reflect.Value) bool // set if omitZero
+}
+
+// structFields holds the fields of a struct type, as returned by
+// cachedTypeFields.
+type structFields struct {
+	list      []field
+	nameIndex map[string]int // index in list of the field with each name
 }
 
 // byIndex sorts field by index sequence.
@@ -1067,7 +1121,7 @@ func (x byIndex) Less(i, j int) bool {
 // typeFields returns a list of fields that JSON should recognize for the given type.
 // The algorithm is breadth-first search over the set of structs to include - the top struct
 // and then any reachable anonymous structs.
-func typeFields(t reflect.Type) []field {
+func typeFields(t reflect.Type) structFields {
 	// Anonymous fields to explore at the current level and the next.
 	current := []field{}
 	next := []field{{typ: t}}
@@ -1157,6 +1211,7 @@ func typeFields(t reflect.Type) []field {
 						index:     index,
 						typ:       ft,
 						omitEmpty: opts.Contains("omitempty"),
+						omitZero:  opts.Contains("omitzero"),
 						quoted:    quoted,
 					}
 					field.nameBytes = []byte(field.name)
@@ -1238,11 +1293,89 @@ func typeFields(t reflect.Type) []field {
 	fields = out
 	sort.Sort(byIndex(fields))
 
+	nameIndex := make(map[string]int, len(fields))
 	for i := range fields {
 		f := &fields[i]
-		f.encoder = typeEncoder(typeByIndex(t, f.index))
+		ft := typeByIndex(t, f.index)
+		f.encoder = typeEncoder(ft)
+		if f.omitZero {
+			f.isZero = newIsZeroFunc(ft)
+		}
+		nameIndex[f.name] = i
+	}
+	return structFields{list: fields, nameIndex: nameIndex}
+}
+
+var isZeroerType = reflect.TypeOf((*isZeroer)(nil)).Elem()
+
+// isZeroer is implemented by types, such as time.Time, that define
+// their own notion of a zero value for the "omitzero" option.
+type isZeroer interface {
+	IsZero() bool
+}
+
+// newIsZeroFunc returns a func that reports whether a value of type t
+// is zero for the purposes of the "omitzero" option.
+func newIsZeroFunc(t reflect.Type) func(reflect.Value) bool {
+	switch {
+	case (t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface) && t.Implements(isZeroerType):
+		return func(v reflect.Value) bool {
+			// A nil pointer or interface is zero; don't call
+			// a method that may dereference it.
+			return v.IsNil() || v.Interface().(isZeroer).IsZero()
+		}
+	case t.Implements(isZeroerType):
+		return func(v reflect.Value) bool {
+			return v.Interface().(isZeroer).IsZero()
+		}
+	case reflect.PtrTo(t).Implements(isZeroerType):
+		return func(v reflect.Value) bool {
+			if !v.CanAddr() {
+				// Copy v so that the pointer method can be called.
+				v2 := reflect.New(v.Type()).Elem()
+				v2.Set(v)
+				v = v2
+			}
+			return v.Addr().Interface().(isZeroer).IsZero()
+		}
 	}
-	return fields
+	return isZeroValue
+}
+
+// isZeroValue reports whether v is the zero value of its type.
+func isZeroValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return math.Float64bits(v.Float()) == 0
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return math.Float64bits(real(c)) == 0 && math.Float64bits(imag(c)) == 0
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isZeroValue(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isZeroValue(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+	return false
 }
 
 // dominantField looks through the fields, all of which are known to
@@ -1261,13 +1394,13 @@ func dominantField(fields []field) (field, bool) {
 	return fields[0], true
 }
 
-var fieldCache sync.Map // map[reflect.Type][]field
+var fieldCache sync.Map // map[reflect.Type]structFields
 
 // cachedTypeFields is like typeFields but uses a cache to avoid repeated work.
-func cachedTypeFields(t reflect.Type) []field {
+func cachedTypeFields(t reflect.Type) structFields {
 	if f, ok := fieldCache.Load(t); ok {
-		return f.([]field)
+		return f.(structFields)
 	}
 	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
-	return f.([]field)
+	return f.(structFields)
 }
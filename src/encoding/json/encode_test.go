@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"strconv"
 	"testing"
+	"time"
 	"unicode"
 )
 
@@ -70,6 +71,97 @@ func TestOmitEmpty(t *testing.T) {
 	}
 }
 
+type zeroer struct {
+	N int
+}
+
+func (z zeroer) IsZero() bool { return z.N <= 0 }
+
+type ptrZeroer struct {
+	N int
+}
+
+func (z *ptrZeroer) IsZero() bool { return z.N <= 0 }
+
+type OptionalsZero struct {
+	Sr string `json:"sr"`
+	So string `json:"so,omitzero"`
+
+	Slr []int `json:"slr"`
+	Slz []int `json:"slz,omitzero"`
+	Sle []int `json:"sle,omitzero"`
+
+	Str struct{}        `json:"str"`
+	Stz struct{}        `json:"stz,omitzero"`
+	Stn struct{ A int } `json:"stn,omitzero"`
+
+	Ar [2]int `json:"ar"`
+	Az [2]int `json:"az,omitzero"`
+	An [2]int `json:"an,omitzero"`
+
+	Tr time.Time `json:"tr"`
+	Tz time.Time `json:"tz,omitzero"`
+
+	Zr zeroer    `json:"zr"`
+	Zz zeroer    `json:"zz,omitzero"`
+	Zp *zeroer   `json:"zp,omitzero"`
+	Pz ptrZeroer `json:"pz,omitzero"`
+
+	Ez []int `json:"ez,omitempty,omitzero"`
+}
+
+var optionalsZeroExpected = `{
+ "sr": "",
+ "slr": null,
+ "sle": [],
+ "str": {},
+ "stn": {
+  "A": 1
+ },
+ "ar": [
+  0,
+  0
+ ],
+ "an": [
+  0,
+  1
+ ],
+ "tr": "0001-01-01T00:00:00Z",
+ "zr": {
+  "N": -1
+ }
+}`
+
+func TestOmitZero(t *testing.T) {
+	o := OptionalsZero{
+		Sle: []int{},
+		Stn: struct{ A int }{1},
+		An:  [2]int{0, 1},
+		Zr:  zeroer{-1},
+		Zz:  zeroer{-1},
+		Zp:  &zeroer{-1},
+		Pz:  ptrZeroer{-1},
+		Ez:  []int{},
+	}
+
+	got, err := MarshalIndent(&o, "", " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(got); got != optionalsZeroExpected {
+		t.Errorf(" got: %s\nwant: %s\n", got, optionalsZeroExpected)
+	}
+
+	// Pz is not addressable when o is passed by value.
+	got, err = MarshalIndent(o, "", " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(got); got != optionalsZeroExpected {
+		t.Errorf("non-addressable got: %s\nwant: %s\n", got, optionalsZeroExpected)
+	}
+}
+
 type StringTag struct {
 	BoolStr    bool    `json:",string"`
 	IntStr     int64   `json:",string"`
@@ -586,10 +678,10 @@ func TestStringBytes(t *testing.T) {
 
 	for _, escapeHTML := range []bool{true, false} {
 		es := &encodeState{}
-		es.string(s, escapeHTML)
+		es.string(s, encOpts{escapeHTML: escapeHTML})
 
 		esBytes := &encodeState{}
-		esBytes.stringBytes([]byte(s), escapeHTML)
+		esBytes.stringBytes([]byte(s), encOpts{escapeHTML: escapeHTML})
 
 		enc := es.Buffer.String()
 		encBytes := esBytes.Buffer.String()
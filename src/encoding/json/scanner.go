@@ -24,26 +24,109 @@ func Valid(data []byte) bool {
 // scan is passed in for use by checkValid to avoid an allocation.
 func checkValid(data []byte, scan *scanner) error {
 	scan.reset()
-	for _, c := range data {
+	for i, c := range data {
 		scan.bytes++
 		if scan.step(scan, c) == scanError {
-			return scan.err
+			return setErrorPath(scan.err, data[:i])
 		}
 	}
 	if scan.eof() == scanError {
-		return scan.err
+		return setErrorPath(scan.err, data)
 	}
 	return nil
 }
 
 // A SyntaxError is a description of a JSON syntax error.
+//
+// For an error returned by a Decoder, Offset counts the bytes read
+// from the start of its input, not of the value being decoded.
 type SyntaxError struct {
 	msg    string // description of error
 	Offset int64  // error occurred after reading Offset bytes
+	Path   string // RFC 6901 JSON Pointer to the innermost JSON value containing the error
 }
 
 func (e *SyntaxError) Error() string { return e.msg }
 
+// setErrorPath sets the Path of err, a *SyntaxError found while scanning
+// the byte after data, and returns it.
+func setErrorPath(err error, data []byte) error {
+	if serr, ok := err.(*SyntaxError); ok {
+		serr.Path = pathAt(data)
+	}
+	return err
+}
+
+// pathAt returns the JSON Pointer to the innermost value being scanned
+// at the end of data, which must be the valid start of a JSON value.
+// Only called when reporting errors, it rescans data to find the keys
+// and indexes that lead there.
+func pathAt(data []byte) string {
+	type level struct {
+		elem   pathElem
+		array  bool
+		inElem bool // elem names the member or element being scanned
+	}
+	var (
+		scan     scanner
+		levels   []level
+		keyStart = -1
+	)
+	scan.reset()
+	for i, c := range data {
+		op := scan.step(&scan, c)
+		if keyStart >= 0 && op != scanContinue {
+			// The object key started at keyStart ended before c.
+			top := &levels[len(levels)-1]
+			top.elem.key, _ = unquoteBytes(data[keyStart:i])
+			top.inElem = true
+			keyStart = -1
+		}
+		switch op {
+		case scanBeginLiteral, scanBeginObject, scanBeginArray:
+			if len(levels) > 0 {
+				top := &levels[len(levels)-1]
+				if top.array {
+					top.inElem = true
+				} else if !top.inElem {
+					keyStart = i
+				}
+			}
+			switch op {
+			case scanBeginObject:
+				levels = append(levels, level{elem: pathElem{index: -1}})
+			case scanBeginArray:
+				levels = append(levels, level{array: true})
+			}
+		case scanObjectValue, scanArrayValue:
+			top := &levels[len(levels)-1]
+			top.inElem = false
+			if top.array {
+				top.elem.index++
+			}
+		case scanEndObject, scanEndArray:
+			levels = levels[:len(levels)-1]
+		case scanError:
+			return ""
+		}
+	}
+	if keyStart >= 0 {
+		// data may end with a complete key.
+		if key, ok := unquoteBytes(data[keyStart:]); ok {
+			top := &levels[len(levels)-1]
+			top.elem.key = key
+			top.inElem = true
+		}
+	}
+	var path []pathElem
+	for _, l := range levels {
+		if l.inElem {
+			path = append(path, l.elem)
+		}
+	}
+	return jsonPointer(path)
+}
+
 // A scanner is a JSON scanning state machine.
 // Callers call scan.reset() and then pass bytes in one at a time
 // by calling scan.step(&scan, c) for each byte.
@@ -134,7 +217,7 @@ func (s *scanner) eof() int {
 		return scanEnd
 	}
 	if s.err == nil {
-		s.err = &SyntaxError{"unexpected end of JSON input", s.bytes}
+		s.err = &SyntaxError{msg: "unexpected end of JSON input", Offset: s.bytes}
 	}
 	return scanError
 }
@@ -553,7 +636,7 @@ func stateError(s *scanner, c byte) int {
 // error records an error and switches to the error state.
 func (s *scanner) error(c byte, context string) int {
 	s.step = stateError
-	s.err = &SyntaxError{"invalid character " + quoteChar(c) + " " + context, s.bytes}
+	s.err = &SyntaxError{msg: "invalid character " + quoteChar(c) + " " + context, Offset: s.bytes}
 	return scanError
 }
 
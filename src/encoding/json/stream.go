@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"unicode/utf8"
 )
 
 // A Decoder reads and decodes JSON values from an input stream.
@@ -41,41 +42,98 @@ func (dec *Decoder) UseNumber() { dec.d.useNumber = true }
 // non-ignored, exported fields in the destination.
 func (dec *Decoder) DisallowUnknownFields() { dec.d.disallowUnknownFields = true }
 
+// UseCaseSensitiveFields causes the Decoder to match object keys to struct
+// fields only if they are equal, rather than also accepting a
+// case-insensitive match.
+func (dec *Decoder) UseCaseSensitiveFields() { dec.d.caseSensitiveFields = true }
+
+// DisallowDuplicateFields causes the Decoder to return an error when the
+// input contains an object with the same key more than once, or, when the
+// destination is a struct, with several keys that match the same field.
+func (dec *Decoder) DisallowDuplicateFields() { dec.d.disallowDuplicateFields = true }
+
+// DisallowInvalidUTF8 causes the Decoder to return a SyntaxError when the
+// input contains a string with invalid UTF-8, rather than replacing the
+// invalid bytes with the Unicode replacement character U+FFFD.
+func (dec *Decoder) DisallowInvalidUTF8() { dec.d.disallowInvalidUTF8 = true }
+
 // Decode reads the next JSON-encoded value from its
 // input and stores it in the value pointed to by v.
 //
 // See the documentation for Unmarshal for details about
 // the conversion of JSON into a Go value.
 func (dec *Decoder) Decode(v interface{}) error {
+	data, start, err := dec.nextValue()
+	if err != nil {
+		return err
+	}
+	dec.d.init(data)
+
+	// Don't save err from unmarshal into dec.err:
+	// the connection is still usable since we read a complete JSON
+	// object from it before the error happened.
+	err = dec.d.unmarshal(v)
+
+	// fixup token streaming state
+	dec.tokenValueEnd()
+
+	// Report offsets from the start of the input. The error may come
+	// from an Unmarshaler, which may return the same error value more
+	// than once, so modify a copy.
+	switch err := err.(type) {
+	case *UnmarshalTypeError:
+		e := *err
+		e.Offset += start
+		return &e
+	case *SyntaxError:
+		e := *err
+		e.Offset += start
+		return &e
+	}
+	return err
+}
+
+// ReadRaw reads the next JSON-encoded value from its input and
+// returns it without decoding it, as Decode does for a RawMessage.
+// Leading space is not included. Like Decode, ReadRaw may be called
+// between calls to Token wherever a value is allowed.
+func (dec *Decoder) ReadRaw() (RawMessage, error) {
+	data, _, err := dec.nextValue()
+	if err != nil {
+		return nil, err
+	}
+	dec.tokenValueEnd()
+	for len(data) > 0 && isSpace(data[0]) {
+		data = data[1:]
+	}
+	return append(RawMessage(nil), data...), nil
+}
+
+// nextValue reads the next JSON value from the input for Decode and
+// ReadRaw. It returns the value, which is only valid until the next
+// read, and its offset in the input.
+func (dec *Decoder) nextValue() (data []byte, start int64, err error) {
 	if dec.err != nil {
-		return dec.err
+		return nil, 0, dec.err
 	}
 
 	if err := dec.tokenPrepareForDecode(); err != nil {
-		return err
+		return nil, 0, err
 	}
 
 	if !dec.tokenValueAllowed() {
-		return &SyntaxError{msg: "not at beginning of value", Offset: dec.offset()}
+		return nil, 0, &SyntaxError{msg: "not at beginning of value", Offset: dec.offset()}
 	}
 
 	// Read whole value into buffer.
 	n, err := dec.readValue()
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	dec.d.init(dec.buf[dec.scanp : dec.scanp+n])
+	start = dec.offset()
+	data = dec.buf[dec.scanp : dec.scanp+n]
 	dec.scanp += n
-
-	// Don't save err from unmarshal into dec.err:
-	// the connection is still usable since we read a complete JSON
-	// object from it before the error happened.
-	err = dec.d.unmarshal(v)
-
-	// fixup token streaming state
-	dec.tokenValueEnd()
-
-	return err
+	return data, start, nil
 }
 
 // Buffered returns a reader of the data remaining in the Decoder's
@@ -109,8 +167,11 @@ Input:
 					break Input
 				}
 			case scanError:
-				dec.err = dec.scan.err
-				return 0, dec.scan.err
+				if serr, ok := dec.scan.err.(*SyntaxError); ok {
+					serr.Offset = dec.scanned + int64(scanp+i) + 1
+				}
+				dec.err = setErrorPath(dec.scan.err, dec.buf[dec.scanp:scanp+i])
+				return 0, dec.err
 			}
 		}
 		scanp = len(dec.buf)
@@ -173,13 +234,18 @@ func nonSpace(b []byte) bool {
 
 // An Encoder writes JSON values to an output stream.
 type Encoder struct {
-	w          io.Writer
-	err        error
-	escapeHTML bool
+	w                   io.Writer
+	err                 error
+	escapeHTML          bool
+	nilAsEmpty          bool
+	disallowInvalidUTF8 bool
 
 	indentBuf    *bytes.Buffer
 	indentPrefix string
 	indentValue  string
+
+	tokenState int
+	tokenStack []int
 }
 
 // NewEncoder returns a new encoder that writes to w.
@@ -189,6 +255,8 @@ func NewEncoder(w io.Writer) *Encoder {
 
 // Encode writes the JSON encoding of v to the stream,
 // followed by a newline character.
+// Inside an array or object begun with BeginArray or BeginObject,
+// Encode is equivalent to WriteValue.
 //
 // See the documentation for Marshal for details about the
 // conversion of Go values to JSON.
@@ -196,8 +264,11 @@ func (enc *Encoder) Encode(v interface{}) error {
 	if enc.err != nil {
 		return enc.err
 	}
+	if enc.tokenState != tokenTopValue {
+		return enc.WriteValue(v)
+	}
 	e := newEncodeState()
-	err := e.marshal(v, encOpts{escapeHTML: enc.escapeHTML})
+	err := e.marshal(v, enc.marshalOpts())
 	if err != nil {
 		return err
 	}
@@ -248,6 +319,255 @@ func (enc *Encoder) SetEscapeHTML(on bool) {
 	enc.escapeHTML = on
 }
 
+// SetNilAsEmpty specifies whether nil slices and maps should be encoded
+// as the empty JSON array [] and object {} rather than as null.
+// A nil []byte is encoded as the empty string "".
+// Nil pointers and interface values are still encoded as null.
+func (enc *Encoder) SetNilAsEmpty(on bool) {
+	enc.nilAsEmpty = on
+}
+
+// SetDisallowInvalidUTF8 specifies whether a string containing invalid
+// UTF-8 should cause Encode to return an InvalidUTF8Error, rather than
+// being coerced to valid UTF-8 by replacing the invalid bytes with the
+// Unicode replacement character U+FFFD.
+func (enc *Encoder) SetDisallowInvalidUTF8(on bool) {
+	enc.disallowInvalidUTF8 = on
+}
+
+func (enc *Encoder) marshalOpts() encOpts {
+	return encOpts{
+		escapeHTML:          enc.escapeHTML,
+		nilAsEmpty:          enc.nilAsEmpty,
+		disallowInvalidUTF8: enc.disallowInvalidUTF8,
+	}
+}
+
+// BeginArray writes the start of a JSON array, [, to the stream.
+// Its elements are written with further calls, up to a matching
+// call to EndArray.
+//
+// BeginArray, BeginObject, EndArray, EndObject, WriteName,
+// WriteValue and WriteRaw let a JSON value be written a piece at a
+// time, such as to stream an array too large to hold in memory.
+// The Encoder inserts the commas, colons and indentation between the
+// pieces, and returns an error, writing nothing, if a piece is not
+// allowed where it is written: for instance, a value in an object
+// must be preceded by a call to WriteName. As with Encode, each
+// top-level value is followed by a newline character.
+func (enc *Encoder) BeginArray() error {
+	return enc.tokenBegin("BeginArray", '[', tokenArrayStart)
+}
+
+// BeginObject writes the start of a JSON object, {, to the stream.
+// Its members are written with pairs of calls to WriteName and to a
+// method that writes a value, up to a matching call to EndObject.
+// See BeginArray for details.
+func (enc *Encoder) BeginObject() error {
+	return enc.tokenBegin("BeginObject", '{', tokenObjectStart)
+}
+
+// EndArray writes the end of the JSON array begun by the matching call
+// to BeginArray, ], to the stream.
+func (enc *Encoder) EndArray() error {
+	return enc.tokenEnd("EndArray", ']', tokenArrayStart, tokenArrayComma)
+}
+
+// EndObject writes the end of the JSON object begun by the matching
+// call to BeginObject, }, to the stream.
+func (enc *Encoder) EndObject() error {
+	return enc.tokenEnd("EndObject", '}', tokenObjectStart, tokenObjectComma)
+}
+
+// WriteName writes name as the key of the next member of the current
+// object to the stream. It must be followed by a value.
+func (enc *Encoder) WriteName(name string) error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if enc.tokenState != tokenObjectStart && enc.tokenState != tokenObjectComma {
+		return enc.tokenError("WriteName")
+	}
+	if enc.disallowInvalidUTF8 && !utf8.ValidString(name) {
+		return &InvalidUTF8Error{name}
+	}
+	e := newEncodeState()
+	if enc.tokenState == tokenObjectComma {
+		e.WriteByte(',')
+	}
+	enc.tokenNewline(e, len(enc.tokenStack))
+	e.string(name, enc.marshalOpts())
+	e.WriteByte(':')
+	if enc.indentPrefix != "" || enc.indentValue != "" {
+		e.WriteByte(' ')
+	}
+	enc.tokenState = tokenObjectValue
+	return enc.tokenWrite(e)
+}
+
+// WriteValue writes the JSON encoding of v to the stream as the next
+// value, in the way Encode does.
+func (enc *Encoder) WriteValue(v interface{}) error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if !enc.tokenValueAllowed() {
+		return enc.tokenError("WriteValue")
+	}
+	ve := newEncodeState()
+	defer encodeStatePool.Put(ve)
+	if err := ve.marshal(v, enc.marshalOpts()); err != nil {
+		return err
+	}
+	return enc.tokenValue(ve.Bytes())
+}
+
+// WriteRaw writes raw, which must be a valid JSON encoding, to the
+// stream as the next value. It is compacted and, if the Encoder has
+// an indent set, reindented to fit the surrounding output.
+func (enc *Encoder) WriteRaw(raw RawMessage) error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if !enc.tokenValueAllowed() {
+		return enc.tokenError("WriteRaw")
+	}
+	ve := newEncodeState()
+	defer encodeStatePool.Put(ve)
+	if err := compact(&ve.Buffer, raw, enc.escapeHTML); err != nil {
+		return err
+	}
+	return enc.tokenValue(ve.Bytes())
+}
+
+func (enc *Encoder) tokenValueAllowed() bool {
+	switch enc.tokenState {
+	case tokenTopValue, tokenArrayStart, tokenArrayComma, tokenObjectValue:
+		return true
+	}
+	return false
+}
+
+// tokenError returns the error for a call to the method op when the
+// current token state does not allow it.
+func (enc *Encoder) tokenError(op string) error {
+	var want string
+	switch enc.tokenState {
+	case tokenTopValue:
+		want = "a value"
+	case tokenArrayStart, tokenArrayComma:
+		want = "an array element or the end of the array"
+	case tokenObjectStart, tokenObjectComma:
+		want = "an object key or the end of the object"
+	case tokenObjectValue:
+		want = "an object value"
+	}
+	return errors.New("json: invalid call to Encoder." + op + ": expecting " + want)
+}
+
+// tokenValueStart writes to e the comma and indentation needed before
+// a value in the current token state.
+func (enc *Encoder) tokenValueStart(e *encodeState) {
+	switch enc.tokenState {
+	case tokenArrayComma:
+		e.WriteByte(',')
+		fallthrough
+	case tokenArrayStart:
+		enc.tokenNewline(e, len(enc.tokenStack))
+	}
+}
+
+// tokenValueEnd updates the token state after a complete value, and
+// writes to e the newline that follows each top-level value.
+func (enc *Encoder) tokenValueEnd(e *encodeState) {
+	switch enc.tokenState {
+	case tokenTopValue:
+		e.WriteByte('\n')
+	case tokenArrayStart:
+		enc.tokenState = tokenArrayComma
+	case tokenObjectValue:
+		enc.tokenState = tokenObjectComma
+	}
+}
+
+// tokenNewline writes to e a newline indented for depth, if the Encoder
+// has an indent set.
+func (enc *Encoder) tokenNewline(e *encodeState, depth int) {
+	if enc.indentPrefix == "" && enc.indentValue == "" {
+		return
+	}
+	e.WriteByte('\n')
+	e.WriteString(enc.indentPrefix)
+	for i := 0; i < depth; i++ {
+		e.WriteString(enc.indentValue)
+	}
+}
+
+// tokenValue writes the compact encoding of a value, b, to the stream.
+func (enc *Encoder) tokenValue(b []byte) error {
+	e := newEncodeState()
+	enc.tokenValueStart(e)
+	if enc.indentPrefix != "" || enc.indentValue != "" {
+		prefix := enc.indentPrefix
+		for i := 0; i < len(enc.tokenStack); i++ {
+			prefix += enc.indentValue
+		}
+		if err := Indent(&e.Buffer, b, prefix, enc.indentValue); err != nil {
+			encodeStatePool.Put(e)
+			return err
+		}
+	} else {
+		e.Write(b)
+	}
+	enc.tokenValueEnd(e)
+	return enc.tokenWrite(e)
+}
+
+func (enc *Encoder) tokenBegin(op string, delim byte, state int) error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if !enc.tokenValueAllowed() {
+		return enc.tokenError(op)
+	}
+	e := newEncodeState()
+	enc.tokenValueStart(e)
+	e.WriteByte(delim)
+	enc.tokenStack = append(enc.tokenStack, enc.tokenState)
+	enc.tokenState = state
+	return enc.tokenWrite(e)
+}
+
+// tokenEnd ends the array or object in which start and comma are the
+// states before and after its first element.
+func (enc *Encoder) tokenEnd(op string, delim byte, start, comma int) error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if enc.tokenState != start && enc.tokenState != comma {
+		return enc.tokenError(op)
+	}
+	e := newEncodeState()
+	if enc.tokenState == comma {
+		enc.tokenNewline(e, len(enc.tokenStack)-1)
+	}
+	e.WriteByte(delim)
+	enc.tokenState = enc.tokenStack[len(enc.tokenStack)-1]
+	enc.tokenStack = enc.tokenStack[:len(enc.tokenStack)-1]
+	enc.tokenValueEnd(e)
+	return enc.tokenWrite(e)
+}
+
+// tokenWrite writes the contents of e to the stream and releases e.
+func (enc *Encoder) tokenWrite(e *encodeState) error {
+	_, err := enc.w.Write(e.Bytes())
+	if err != nil {
+		enc.err = err
+	}
+	encodeStatePool.Put(e)
+	return err
+}
+
 // RawMessage is a raw encoded JSON value.
 // It implements Marshaler and Unmarshaler and can
 // be used to delay JSON decoding or precompute a JSON encoding.
@@ -308,7 +628,7 @@ func (dec *Decoder) tokenPrepareForDecode() error {
 			return err
 		}
 		if c != ',' {
-			return &SyntaxError{"expected comma after array element", dec.offset()}
+			return &SyntaxError{msg: "expected comma after array element", Offset: dec.offset()}
 		}
 		dec.scanp++
 		dec.tokenState = tokenArrayValue
@@ -318,7 +638,7 @@ func (dec *Decoder) tokenPrepareForDecode() error {
 			return err
 		}
 		if c != ':' {
-			return &SyntaxError{"expected colon after object key", dec.offset()}
+			return &SyntaxError{msg: "expected colon after object key", Offset: dec.offset()}
 		}
 		dec.scanp++
 		dec.tokenState = tokenObjectValue
@@ -471,7 +791,7 @@ func (dec *Decoder) tokenError(c byte) (Token, error) {
 	case tokenObjectComma:
 		context = " after object key:value pair"
 	}
-	return nil, &SyntaxError{"invalid character " + quoteChar(c) + context, dec.offset()}
+	return nil, &SyntaxError{msg: "invalid character " + quoteChar(c) + context, Offset: dec.offset()}
 }
 
 // More reports whether there is another element in the
@@ -6,6 +6,7 @@ package json
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
@@ -132,6 +133,244 @@ func TestEncoderSetEscapeHTML(t *testing.T) {
 	}
 }
 
+func TestEncoderSetNilAsEmpty(t *testing.T) {
+	v := struct {
+		S  []int
+		M  map[string]int
+		B  []byte
+		P  *int
+		I  interface{}
+		SS [][]int
+	}{SS: [][]int{nil}}
+	for _, tt := range []struct {
+		on   bool
+		want string
+	}{
+		{false, `{"S":null,"M":null,"B":null,"P":null,"I":null,"SS":[null]}`},
+		{true, `{"S":[],"M":{},"B":"","P":null,"I":null,"SS":[[]]}`},
+	} {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		enc.SetNilAsEmpty(tt.on)
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("SetNilAsEmpty(%v) Encode: %v", tt.on, err)
+		}
+		if got := strings.TrimSpace(buf.String()); got != tt.want {
+			t.Errorf("SetNilAsEmpty(%v) Encode = %#q, want %#q", tt.on, got, tt.want)
+		}
+	}
+}
+
+func TestEncoderSetDisallowInvalidUTF8(t *testing.T) {
+	type S struct {
+		Str string
+		Q   string `json:",string"`
+	}
+	for _, tt := range []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{"string", "a\xffb", `"a\ufffdb"`},
+		{"map key", map[string]int{"a\xffb": 1}, `{"a\ufffdb":1}`},
+		{"field", S{Str: "a\xffb"}, `{"Str":"a\ufffdb","Q":"\"\""}`},
+		{"string option", S{Q: "a\xffb"}, `{"Str":"","Q":"\"a\\ufffdb\""}`},
+	} {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		if err := enc.Encode(tt.v); err != nil {
+			t.Fatalf("Encode(%s): %v", tt.name, err)
+		}
+		if got := strings.TrimSpace(buf.String()); got != tt.want {
+			t.Errorf("Encode(%s) = %#q, want %#q", tt.name, got, tt.want)
+		}
+		buf.Reset()
+		enc.SetDisallowInvalidUTF8(true)
+		err := enc.Encode(tt.v)
+		if _, ok := err.(*InvalidUTF8Error); !ok {
+			t.Errorf("SetDisallowInvalidUTF8(true) Encode(%s) error = %v, want InvalidUTF8Error", tt.name, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("SetDisallowInvalidUTF8(true) Encode(%s) wrote %#q", tt.name, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetDisallowInvalidUTF8(true)
+	if err := enc.Encode("a\u00ffb"); err != nil {
+		t.Errorf("Encode of valid UTF-8: %v", err)
+	}
+}
+
+// encodeTokens writes a value to enc using the token API: an object
+// holding every kind of member, including nested and empty values.
+func encodeTokens(enc *Encoder) error {
+	steps := []func() error{
+		enc.BeginObject,
+		func() error { return enc.WriteName("a") },
+		enc.BeginArray,
+		func() error { return enc.WriteValue(1) },
+		func() error { return enc.WriteValue("<b>") },
+		func() error { return enc.WriteRaw(RawMessage(` {"x": [true, null]} `)) },
+		enc.BeginArray,
+		enc.EndArray,
+		enc.EndArray,
+		func() error { return enc.WriteName("c") },
+		enc.BeginObject,
+		enc.EndObject,
+		func() error { return enc.WriteName("d") },
+		func() error { return enc.Encode(map[string]int{"e": 2}) },
+		enc.EndObject,
+		func() error { return enc.WriteValue(3) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			return fmt.Errorf("step %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func TestEncoderTokens(t *testing.T) {
+	const want = `{"a":[1,"<b>",{"x":[true,null]},[]],"c":{},"d":{"e":2}}` + "\n3\n"
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := encodeTokens(enc); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("got %#q, want %#q", got, want)
+	}
+
+	// With indentation, the output matches Encode's.
+	var indented bytes.Buffer
+	Indent(&indented, []byte(want[:strings.IndexByte(want, '\n')]), ">", "\t")
+	wantIndent := indented.String() + "\n3\n"
+	buf.Reset()
+	enc = NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(">", "\t")
+	if err := encodeTokens(enc); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != wantIndent {
+		t.Errorf("indented got:\n%s\nwant:\n%s", got, wantIndent)
+	}
+}
+
+func TestEncoderTokenErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		steps func(enc *Encoder) error
+		want  string
+	}{
+		{"top-level name", func(enc *Encoder) error {
+			return enc.WriteName("a")
+		}, "json: invalid call to Encoder.WriteName: expecting a value"},
+		{"top-level end", func(enc *Encoder) error {
+			return enc.EndArray()
+		}, "json: invalid call to Encoder.EndArray: expecting a value"},
+		{"value without name", func(enc *Encoder) error {
+			enc.BeginObject()
+			return enc.WriteValue(1)
+		}, "json: invalid call to Encoder.WriteValue: expecting an object key or the end of the object"},
+		{"end after name", func(enc *Encoder) error {
+			enc.BeginObject()
+			enc.WriteName("a")
+			return enc.EndObject()
+		}, "json: invalid call to Encoder.EndObject: expecting an object value"},
+		{"mismatched end", func(enc *Encoder) error {
+			enc.BeginArray()
+			return enc.EndObject()
+		}, "json: invalid call to Encoder.EndObject: expecting an array element or the end of the array"},
+		{"name in array", func(enc *Encoder) error {
+			enc.BeginArray()
+			enc.WriteValue(1)
+			return enc.WriteName("a")
+		}, "json: invalid call to Encoder.WriteName: expecting an array element or the end of the array"},
+		{"invalid raw", func(enc *Encoder) error {
+			return enc.WriteRaw(RawMessage(`{"a"}`))
+		}, "invalid character '}' after object key"},
+	} {
+		err := tt.steps(NewEncoder(ioutil.Discard))
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	// An invalid call writes nothing, and the Encoder stays usable.
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.BeginArray()
+	if err := enc.WriteName("a"); err == nil {
+		t.Fatal("WriteName in array succeeded")
+	}
+	enc.WriteValue(1)
+	enc.EndArray()
+	if got, want := buf.String(), "[1]\n"; got != want {
+		t.Errorf("got %#q, want %#q", got, want)
+	}
+}
+
+func TestDecoderReadRaw(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(` {"a": [1, 2]} [ "x" , {"b" : null} ] 3`))
+	raw, err := dec.ReadRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(raw), `{"a": [1, 2]}`; got != want {
+		t.Errorf("ReadRaw = %#q, want %#q", got, want)
+	}
+
+	// ReadRaw works between tokens.
+	if tok, err := dec.Token(); err != nil || tok != Delim('[') {
+		t.Fatalf("Token = %v, %v; want [", tok, err)
+	}
+	var vals []string
+	for dec.More() {
+		raw, err := dec.ReadRaw()
+		if err != nil {
+			t.Fatal(err)
+		}
+		vals = append(vals, string(raw))
+	}
+	if want := []string{`"x"`, `{"b" : null}`}; !reflect.DeepEqual(vals, want) {
+		t.Errorf("ReadRaw in array = %#q, want %#q", vals, want)
+	}
+	if tok, err := dec.Token(); err != nil || tok != Delim(']') {
+		t.Fatalf("Token = %v, %v; want ]", tok, err)
+	}
+
+	raw, err = dec.ReadRaw()
+	if err != nil || string(raw) != "3" {
+		t.Errorf("ReadRaw = %#q, %v; want 3", raw, err)
+	}
+	if _, err := dec.ReadRaw(); err != io.EOF {
+		t.Errorf("ReadRaw at end = %v, want EOF", err)
+	}
+}
+
+func TestDecoderErrorOffsets(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`1 {"a": [1, "x"]} [true, fals]`))
+	var n int
+	if err := dec.Decode(&n); err != nil {
+		t.Fatal(err)
+	}
+	var v struct{ A []int }
+	err := dec.Decode(&v)
+	want := &UnmarshalTypeError{Value: "string", Type: reflect.TypeOf(0), Offset: 15, Field: "A", Path: "/a/1"}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("Decode error = %#v, want %#v", err, want)
+	}
+	err = dec.Decode(new(interface{}))
+	wantSyntax := &SyntaxError{msg: "invalid character ']' in literal false (expecting 'e')", Offset: 30, Path: "/1"}
+	if !reflect.DeepEqual(err, wantSyntax) {
+		t.Errorf("Decode error = %#v, want %#v", err, wantSyntax)
+	}
+}
+
 func TestDecoder(t *testing.T) {
 	for i := 0; i <= len(streamTest); i++ {
 		// Use stream without newlines as input,
@@ -352,18 +591,18 @@ var tokenStreamCases []tokenStreamCase = []tokenStreamCase{
 	{json: ` [{"a": 1} {"a": 2}] `, expTokens: []interface{}{
 		Delim('['),
 		decodeThis{map[string]interface{}{"a": float64(1)}},
-		decodeThis{&SyntaxError{"expected comma after array element", 11}},
+		decodeThis{&SyntaxError{msg: "expected comma after array element", Offset: 11}},
 	}},
 	{json: `{ "` + strings.Repeat("a", 513) + `" 1 }`, expTokens: []interface{}{
 		Delim('{'), strings.Repeat("a", 513),
-		decodeThis{&SyntaxError{"expected colon after object key", 518}},
+		decodeThis{&SyntaxError{msg: "expected colon after object key", Offset: 518}},
 	}},
 	{json: `{ "\a" }`, expTokens: []interface{}{
 		Delim('{'),
-		&SyntaxError{"invalid character 'a' in string escape code", 3},
+		&SyntaxError{msg: "invalid character 'a' in string escape code", Offset: 5},
 	}},
 	{json: ` \a`, expTokens: []interface{}{
-		&SyntaxError{"invalid character '\\\\' looking for beginning of value", 1},
+		&SyntaxError{msg: "invalid character '\\\\' looking for beginning of value", Offset: 2},
 	}},
 }
 
@@ -21,6 +21,9 @@
 	pointer receivers) and couple them to flag parsing by
 		flag.Var(&flagVal, "name", "help message for flagname")
 	For such flags, the default value is just the initial value of the variable.
+	For one-off flags, Func and BoolFunc call a function with each value
+	instead, and TextVar binds a flag to any type implementing
+	encoding.TextUnmarshaler.
 
 	After all flags are defined, call
 		flag.Parse()
@@ -58,6 +61,9 @@
 		1, 0, t, f, T, F, true, false, TRUE, FALSE, True, False
 	Duration flags accept any input valid for time.ParseDuration.
 
+	Flags that are not set on the command line may instead be read from
+	environment variables; see SetEnvPrefix.
+
 	The default set of command-line flags is controlled by
 	top-level functions.  The FlagSet type allows one to define
 	independent sets of flags, such as to implement subcommands
@@ -68,6 +74,7 @@
 package flag
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
 	"io"
@@ -278,6 +285,58 @@ func (d *durationValue) Get() interface{} { return time.Duration(*d) }
 
 func (d *durationValue) String() string { return (*time.Duration)(d).String() }
 
+// -- encoding.TextUnmarshaler Value
+type textValue struct{ p encoding.TextUnmarshaler }
+
+func newTextValue(val encoding.TextMarshaler, p encoding.TextUnmarshaler) textValue {
+	ptrVal := reflect.ValueOf(p)
+	if ptrVal.Kind() != reflect.Ptr {
+		panic("variable value type must be a pointer")
+	}
+	defVal := reflect.ValueOf(val)
+	if defVal.Kind() == reflect.Ptr {
+		defVal = defVal.Elem()
+	}
+	if defVal.Type() != ptrVal.Type().Elem() {
+		panic(fmt.Sprintf("default type does not match variable type: %v != %v", defVal.Type(), ptrVal.Type().Elem()))
+	}
+	ptrVal.Elem().Set(defVal)
+	return textValue{p}
+}
+
+func (v textValue) Set(s string) error {
+	return v.p.UnmarshalText([]byte(s))
+}
+
+func (v textValue) Get() interface{} {
+	return v.p
+}
+
+func (v textValue) String() string {
+	if m, ok := v.p.(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return ""
+}
+
+// -- func Value
+type funcValue func(string) error
+
+func (f funcValue) Set(s string) error { return f(s) }
+
+func (f funcValue) String() string { return "" }
+
+// -- boolFunc Value
+type boolFuncValue func(string) error
+
+func (f boolFuncValue) Set(s string) error { return f(s) }
+
+func (f boolFuncValue) String() string { return "" }
+
+func (f boolFuncValue) IsBoolFlag() bool { return true }
+
 // Value is the interface to the dynamic value stored in a flag.
 // (The default value is represented as a string.)
 //
@@ -329,6 +388,7 @@ type FlagSet struct {
 	args          []string // arguments after flags
 	errorHandling ErrorHandling
 	output        io.Writer // nil means stderr; use out() accessor
+	envPrefix     string    // "" means flags are not read from the environment
 }
 
 // A Flag represents the state of a flag.
@@ -444,6 +504,17 @@ func Set(name, value string) error {
 // isZeroValue determines whether the string represents the zero
 // value for a flag.
 func isZeroValue(flag *Flag, value string) bool {
+	if tv, ok := flag.Value.(textValue); ok {
+		// A zero textValue has no variable to marshal, so marshal
+		// the zero value of the variable's type instead.
+		z := reflect.New(reflect.TypeOf(tv.p).Elem())
+		if m, ok := z.Interface().(encoding.TextMarshaler); ok {
+			if b, err := m.MarshalText(); err == nil {
+				return value == string(b)
+			}
+		}
+		return value == ""
+	}
 	// Build a zero value of the flag's Value type, and see if the
 	// result of calling its String method equals the value passed in.
 	// This works unless the Value type is itself an interface type.
@@ -829,6 +900,56 @@ func Duration(name string, value time.Duration, usage string) *time.Duration {
 	return CommandLine.Duration(name, value, usage)
 }
 
+// TextVar defines a flag with a specified name, default value, and usage string.
+// The argument p must be a pointer to a variable that will hold the value
+// of the flag, and p must implement encoding.TextUnmarshaler.
+// If the flag is used, the flag value will be passed to p's UnmarshalText method.
+// The type of the default value must be the same as the type of p.
+func (f *FlagSet) TextVar(p encoding.TextUnmarshaler, name string, value encoding.TextMarshaler, usage string) {
+	f.Var(newTextValue(value, p), name, usage)
+}
+
+// TextVar defines a flag with a specified name, default value, and usage string.
+// The argument p must be a pointer to a variable that will hold the value
+// of the flag, and p must implement encoding.TextUnmarshaler.
+// If the flag is used, the flag value will be passed to p's UnmarshalText method.
+// The type of the default value must be the same as the type of p.
+func TextVar(p encoding.TextUnmarshaler, name string, value encoding.TextMarshaler, usage string) {
+	CommandLine.Var(newTextValue(value, p), name, usage)
+}
+
+// Func defines a flag with the specified name and usage string.
+// Each time the flag is seen, fn is called with the value of the flag.
+// If fn returns a non-nil error, it will be treated as a flag value parsing error.
+func (f *FlagSet) Func(name, usage string, fn func(string) error) {
+	f.Var(funcValue(fn), name, usage)
+}
+
+// Func defines a flag with the specified name and usage string.
+// Each time the flag is seen, fn is called with the value of the flag.
+// If fn returns a non-nil error, it will be treated as a flag value parsing error.
+func Func(name, usage string, fn func(string) error) {
+	CommandLine.Func(name, usage, fn)
+}
+
+// BoolFunc defines a flag with the specified name and usage string without
+// requiring values. Each time the flag is seen, fn is called with the value
+// of the flag, which is "true" unless one is given explicitly as in
+// -name=value.
+// If fn returns a non-nil error, it will be treated as a flag value parsing error.
+func (f *FlagSet) BoolFunc(name, usage string, fn func(string) error) {
+	f.Var(boolFuncValue(fn), name, usage)
+}
+
+// BoolFunc defines a flag with the specified name and usage string without
+// requiring values. Each time the flag is seen, fn is called with the value
+// of the flag, which is "true" unless one is given explicitly as in
+// -name=value.
+// If fn returns a non-nil error, it will be treated as a flag value parsing error.
+func BoolFunc(name, usage string, fn func(string) error) {
+	CommandLine.BoolFunc(name, usage, fn)
+}
+
 // Var defines a flag with the specified name and usage string. The type and
 // value of the flag are represented by the first argument, of type Value, which
 // typically holds a user-defined implementation of Value. For instance, the
@@ -959,31 +1080,98 @@ func (f *FlagSet) parseOne() (bool, error) {
 	return true, nil
 }
 
+// parseEnv sets each flag that was not set on the command line from
+// its environment variable, if that variable is present.
+func (f *FlagSet) parseEnv() error {
+	if f.envPrefix == "" {
+		return nil
+	}
+	for _, flag := range sortFlags(f.formal) {
+		if _, ok := f.actual[flag.Name]; ok {
+			continue
+		}
+		key := envName(f.envPrefix, flag.Name)
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		if err := f.Set(flag.Name, value); err != nil {
+			return f.failf("invalid value %q for environment variable %s: %v", value, key, err)
+		}
+	}
+	return nil
+}
+
+// envName returns the name of the environment variable for the named
+// flag: prefix followed by name in upper case, with each character
+// other than an ASCII letter or digit replaced by an underscore.
+func envName(prefix, name string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// SetEnvPrefix arranges for Parse to read any flag that is not set on the
+// command line from an environment variable, if the variable is present.
+// The variable's name is prefix followed by the flag name in upper case,
+// with each character other than an ASCII letter or digit replaced by an
+// underscore. For example, with prefix "MYAPP_" the flag -listen-addr is
+// read from MYAPP_LISTEN_ADDR. Values from the environment are parsed like
+// values given on the command line, and flags set from the environment are
+// visited by Visit. An empty prefix, the default, disables the lookup.
+func (f *FlagSet) SetEnvPrefix(prefix string) {
+	f.envPrefix = prefix
+}
+
+// SetEnvPrefix arranges for Parse to read any command-line flag that is not
+// set on the command line from an environment variable whose name is prefix
+// followed by the flag name. See the FlagSet method SetEnvPrefix for details.
+func SetEnvPrefix(prefix string) {
+	CommandLine.SetEnvPrefix(prefix)
+}
+
 // Parse parses flag definitions from the argument list, which should not
 // include the command name. Must be called after all flags in the FlagSet
 // are defined and before flags are accessed by the program.
 // The return value will be ErrHelp if -help or -h were set but not defined.
+//
+// If an environment prefix has been set with SetEnvPrefix, Parse then reads
+// the flags that were not set by the arguments from the environment.
 func (f *FlagSet) Parse(arguments []string) error {
 	f.parsed = true
 	f.args = arguments
+	err := f.parseArgs()
+	if err == nil {
+		err = f.parseEnv()
+	}
+	if err == nil {
+		return nil
+	}
+	switch f.errorHandling {
+	case ContinueOnError:
+		return err
+	case ExitOnError:
+		os.Exit(2)
+	case PanicOnError:
+		panic(err)
+	}
+	return nil
+}
+
+// parseArgs parses flags from f.args until the first non-flag argument.
+func (f *FlagSet) parseArgs() error {
 	for {
 		seen, err := f.parseOne()
-		if seen {
-			continue
-		}
-		if err == nil {
-			break
-		}
-		switch f.errorHandling {
-		case ContinueOnError:
+		if !seen {
 			return err
-		case ExitOnError:
-			os.Exit(2)
-		case PanicOnError:
-			panic(err)
 		}
 	}
-	return nil
 }
 
 // Parsed reports whether f.Parse has been called.
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"sort"
 	"strconv"
@@ -305,6 +306,181 @@ func TestUserDefinedBool(t *testing.T) {
 	}
 }
 
+func TestUserDefinedFunc(t *testing.T) {
+	var flags FlagSet
+	flags.Init("test", ContinueOnError)
+	var ss []string
+	flags.Func("v", "usage", func(s string) error {
+		ss = append(ss, s)
+		return nil
+	})
+	if err := flags.Parse([]string{"-v", "1", "-v", "2", "-v=3"}); err != nil {
+		t.Error(err)
+	}
+	if len(ss) != 3 {
+		t.Fatal("expected 3 args; got ", len(ss))
+	}
+	expect := "[1 2 3]"
+	if got := fmt.Sprint(ss); got != expect {
+		t.Errorf("expected value %q got %q", expect, got)
+	}
+	// test usage
+	var buf strings.Builder
+	flags.SetOutput(&buf)
+	flags.Parse([]string{"-h"})
+	if usage := buf.String(); !strings.Contains(usage, "usage") {
+		t.Errorf("usage string not included: %q", usage)
+	}
+	// test Func error
+	flags = *NewFlagSet("test", ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.Func("v", "usage", func(s string) error {
+		return fmt.Errorf("test error")
+	})
+	// flag not set, so no error
+	if err := flags.Parse(nil); err != nil {
+		t.Error(err)
+	}
+	// flag set, expect error
+	if err := flags.Parse([]string{"-v", "1"}); err == nil {
+		t.Error("expected error; got none")
+	} else if errMsg := err.Error(); !strings.Contains(errMsg, "test error") {
+		t.Errorf(`error should contain "test error"; got %q`, errMsg)
+	}
+}
+
+func TestUserDefinedBoolFunc(t *testing.T) {
+	var flags FlagSet
+	flags.Init("test", ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	var ss []string
+	flags.BoolFunc("v", "usage", func(s string) error {
+		ss = append(ss, s)
+		return nil
+	})
+	if err := flags.Parse([]string{"-v", "-v", "-v=false", "arg"}); err != nil {
+		t.Error(err)
+	}
+	if got, want := fmt.Sprint(ss), "[true true false]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := flags.Args(), []string{"arg"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Args() = %q, want %q", got, want)
+	}
+
+	flags = *NewFlagSet("test", ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.BoolFunc("v", "usage", func(s string) error {
+		return fmt.Errorf("test error")
+	})
+	if err := flags.Parse([]string{"-v"}); err == nil {
+		t.Error("expected error; got none")
+	} else if errMsg := err.Error(); !strings.Contains(errMsg, "test error") {
+		t.Errorf(`error should contain "test error"; got %q`, errMsg)
+	}
+}
+
+func TestTextVar(t *testing.T) {
+	fs := NewFlagSet(t.Name(), ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	var ip net.IP
+	fs.TextVar(&ip, "ip", net.IPv4(192, 168, 0, 100), "usage")
+	if got, want := ip.String(), "192.168.0.100"; got != want {
+		t.Errorf("default: got %v, want %v", got, want)
+	}
+	if err := fs.Parse([]string{"-ip", "10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ip.String(), "10.0.0.1"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := fs.Lookup("ip").Value.String(), "10.0.0.1"; got != want {
+		t.Errorf("Value.String() = %q, want %q", got, want)
+	}
+	if got := fs.Lookup("ip").Value.(Getter).Get(); got != &ip {
+		t.Errorf("Get() = %v, want %v", got, &ip)
+	}
+	if err := fs.Parse([]string{"-ip", "not an ip"}); err == nil {
+		t.Error("expected error; got none")
+	}
+}
+
+func TestTextVarMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for mismatched default type")
+		}
+	}()
+	var ip net.IP
+	NewFlagSet(t.Name(), ContinueOnError).TextVar(&ip, "ip", time.Time{}, "usage")
+}
+
+func TestEnvPrefix(t *testing.T) {
+	t.Setenv("TEST_FLAG_NAME", "from-env")
+	t.Setenv("TEST_FLAG_COUNT", "7")
+	t.Setenv("TEST_FLAG_LISTEN_ADDR", "env-addr")
+	t.Setenv("TEST_FLAG_VERBOSE", "true")
+
+	fs := NewFlagSet(t.Name(), ContinueOnError)
+	fs.SetEnvPrefix("TEST_FLAG_")
+	name := fs.String("name", "default", "")
+	count := fs.Int("count", 0, "")
+	addr := fs.String("listen-addr", "", "")
+	verbose := fs.Bool("verbose", false, "")
+	other := fs.String("other", "default", "")
+	if err := fs.Parse([]string{"-listen-addr", "arg-addr", "rest"}); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "from-env" {
+		t.Errorf("name = %q, want %q", *name, "from-env")
+	}
+	if *count != 7 {
+		t.Errorf("count = %d, want 7", *count)
+	}
+	if *addr != "arg-addr" {
+		t.Errorf("listen-addr = %q, want the command-line value %q", *addr, "arg-addr")
+	}
+	if !*verbose {
+		t.Error("verbose = false, want true")
+	}
+	if *other != "default" {
+		t.Errorf("other = %q, want %q", *other, "default")
+	}
+	if fs.NFlag() != 4 {
+		t.Errorf("NFlag() = %d, want 4", fs.NFlag())
+	}
+	if args := fs.Args(); len(args) != 1 || args[0] != "rest" {
+		t.Errorf("Args() = %q, want [rest]", args)
+	}
+}
+
+func TestEnvPrefixInvalid(t *testing.T) {
+	t.Setenv("TEST_FLAG_COUNT", "seven")
+	fs := NewFlagSet(t.Name(), ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.SetEnvPrefix("TEST_FLAG_")
+	fs.Int("count", 0, "")
+	err := fs.Parse(nil)
+	if err == nil {
+		t.Fatal("expected error; got none")
+	}
+	if !strings.Contains(err.Error(), "TEST_FLAG_COUNT") {
+		t.Errorf("error %q does not name the environment variable", err)
+	}
+}
+
+func TestEnvPrefixUnset(t *testing.T) {
+	t.Setenv("COUNT", "7")
+	fs := NewFlagSet(t.Name(), ContinueOnError)
+	count := fs.Int("count", 0, "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if *count != 0 {
+		t.Errorf("count = %d, want 0 without an environment prefix", *count)
+	}
+}
+
 func TestSetOutput(t *testing.T) {
 	var flags FlagSet
 	var buf bytes.Buffer
@@ -401,10 +577,19 @@ const defaultOutput = `  -A	for bootstrapping, allow 'any' type
     	a non-zero int (default 27)
   -O	a flag
     	multiline help string (default true)
+  -V function
+    	a function-valued flag
+  -W	a boolean function
   -Z int
     	an int that defaults to zero
+  -ip address
+    	listen address (default 127.0.0.1)
   -maxT timeout
     	set timeout for dial
+  -since time
+    	only report changes after time
+  -zeroip value
+    	an IP that defaults to zero
 `
 
 func TestPrintDefaults(t *testing.T) {
@@ -421,8 +606,15 @@ func TestPrintDefaults(t *testing.T) {
 	fs.String("M", "", "a multiline\nhelp\nstring")
 	fs.Int("N", 27, "a non-zero int")
 	fs.Bool("O", true, "a flag\nmultiline help string")
+	fs.Func("V", "a `function`-valued flag", func(string) error { return nil })
+	fs.BoolFunc("W", "a boolean function", func(string) error { return nil })
 	fs.Int("Z", 0, "an int that defaults to zero")
+	var ip, zeroIP net.IP
+	fs.TextVar(&ip, "ip", net.IPv4(127, 0, 0, 1), "listen `address`")
+	fs.TextVar(&zeroIP, "zeroip", net.IP(nil), "an IP that defaults to zero")
 	fs.Duration("maxT", 0, "set `timeout` for dial")
+	var since time.Time
+	fs.TextVar(&since, "since", time.Time{}, "only report changes after `time`")
 	fs.PrintDefaults()
 	got := buf.String()
 	if got != defaultOutput {
@@ -163,6 +163,14 @@
 	will be invoked to convert the object to a string, which will then
 	be formatted as required by the verb (if any).
 
+	When passed to Errorf, the %w verb formats an error operand the
+	same way as %v, and the error returned by Errorf records the operand
+	so that it can be retrieved with errors.Unwrap. It is invalid to use
+	%w more than once in a format string, to use it with an operand that
+	does not implement the error interface, or to use it with any
+	function other than Errorf; such uses print %!w(...) like other
+	bad verbs.
+
 	For compound operands such as slices and structs, the format
 	applies to the elements of each operand, recursively, not to the
 	operand as a whole. Thus %q will quote each element of a slice
@@ -194,6 +194,21 @@ func (ctxt *Context) openFile(path string) (io.ReadCloser, error) {
 	return f, nil
 }
 
+// readEmbeds reads the Go source file at path and returns
+// the //go:embed patterns it contains.
+func (ctxt *Context) readEmbeds(path string) ([]fileEmbed, error) {
+	f, err := ctxt.openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %v", path, err)
+	}
+	return findEmbeds(path, data)
+}
+
 // isFile determines whether path is a file by trying to open it.
 // It reuses openFile instead of adding another function to the
 // list in Context.
@@ -434,6 +449,18 @@ type Package struct {
 	XTestGoFiles   []string                    // _test.go files outside package
 	XTestImports   []string                    // import paths from XTestGoFiles
 	XTestImportPos map[string][]token.Position // line information for XTestImports
+
+	// //go:embed patterns found in Go source files
+	// For example, if a source file says
+	//	//go:embed a* b.c
+	// then the list will contain those two strings as separate entries.
+	// (See package embed for more details about //go:embed.)
+	EmbedPatterns        []string                    // patterns from GoFiles, CgoFiles
+	EmbedPatternPos      map[string][]token.Position // line information for EmbedPatterns
+	TestEmbedPatterns    []string                    // patterns from TestGoFiles
+	TestEmbedPatternPos  map[string][]token.Position // line information for TestEmbedPatterns
+	XTestEmbedPatterns   []string                    // patterns from XTestGoFiles
+	XTestEmbedPatternPos map[string][]token.Position // line information for XTestEmbedPatterns
 }
 
 // IsCommand reports whether the package is considered a
@@ -750,6 +777,9 @@ Found:
 	imported := make(map[string][]token.Position)
 	testImported := make(map[string][]token.Position)
 	xTestImported := make(map[string][]token.Position)
+	embedPos := make(map[string][]token.Position)
+	testEmbedPos := make(map[string][]token.Position)
+	xTestEmbedPos := make(map[string][]token.Position)
 	allTags := make(map[string]bool)
 	fset := token.NewFileSet()
 	for _, d := range dirs {
@@ -868,6 +898,7 @@ Found:
 
 		// Record imports and information about cgo.
 		isCgo := false
+		isEmbed := false
 		for _, decl := range pf.Decls {
 			d, ok := decl.(*ast.GenDecl)
 			if !ok {
@@ -890,6 +921,9 @@ Found:
 				} else {
 					imported[path] = append(imported[path], fset.Position(spec.Pos()))
 				}
+				if path == "embed" {
+					isEmbed = true
+				}
 				if path == "C" {
 					if isTest {
 						badFile(fmt.Errorf("use of cgo in test %s not supported", filename))
@@ -908,6 +942,27 @@ Found:
 				}
 			}
 		}
+		if isEmbed {
+			// The file imports "embed", so we have to look for
+			// //go:embed comments in the remainder of the file.
+			// The compiler will enforce the mapping of comments to
+			// declared variables. We just need to know the patterns.
+			embeds, err := ctxt.readEmbeds(filename)
+			if err != nil {
+				badFile(err)
+				continue
+			}
+			m := embedPos
+			if isXTest {
+				m = xTestEmbedPos
+			} else if isTest {
+				m = testEmbedPos
+			}
+			for _, e := range embeds {
+				m[e.pattern] = append(m[e.pattern], e.pos)
+			}
+		}
+
 		if isCgo {
 			allTags["cgo"] = true
 			if ctxt.CgoEnabled {
@@ -935,9 +990,12 @@ Found:
 	}
 	sort.Strings(p.AllTags)
 
-	p.Imports, p.ImportPos = cleanImports(imported)
-	p.TestImports, p.TestImportPos = cleanImports(testImported)
-	p.XTestImports, p.XTestImportPos = cleanImports(xTestImported)
+	p.Imports, p.ImportPos = cleanDecls(imported)
+	p.TestImports, p.TestImportPos = cleanDecls(testImported)
+	p.XTestImports, p.XTestImportPos = cleanDecls(xTestImported)
+	p.EmbedPatterns, p.EmbedPatternPos = cleanDecls(embedPos)
+	p.TestEmbedPatterns, p.TestEmbedPatternPos = cleanDecls(testEmbedPos)
+	p.XTestEmbedPatterns, p.XTestEmbedPatternPos = cleanDecls(xTestEmbedPos)
 
 	// add the .S files only if we are using cgo
 	// (which means gcc will compile them).
@@ -1266,7 +1324,7 @@ func (ctxt *Context) matchFile(dir, name string, allTags map[string]bool, binary
 	return
 }
 
-func cleanImports(m map[string][]token.Position) ([]string, map[string][]token.Position) {
+func cleanDecls(m map[string][]token.Position) ([]string, map[string][]token.Position) {
 	all := make([]string, 0, len(m))
 	for path := range m {
 		all = append(all, path)
@@ -34,7 +34,7 @@ import (
 //
 var pkgDeps = map[string][]string{
 	// L0 is the lowest level, core, nearly unavoidable packages.
-	"errors":                  {},
+	"errors":                  {"runtime", "internal/reflectlite"},
 	"io":                      {"errors", "sync", "sync/atomic"},
 	"runtime":                 {"unsafe", "runtime/internal/atomic", "runtime/internal/sys", "runtime/internal/math", "internal/cpu", "internal/bytealg"},
 	"runtime/internal/sys":    {},
@@ -46,6 +46,8 @@ var pkgDeps = map[string][]string{
 	"unsafe":                  {},
 	"internal/cpu":            {},
 	"internal/bytealg":        {"unsafe", "internal/cpu"},
+	"internal/reflectlite":    {"runtime", "unsafe"},
+	"internal/oserror":        {"errors"},
 
 	"L0": {
 		"errors",
@@ -57,6 +59,8 @@ var pkgDeps = map[string][]string{
 		"unsafe",
 		"internal/cpu",
 		"internal/bytealg",
+		"internal/reflectlite",
+		"internal/oserror",
 	},
 
 	// L1 adds simple functions and strings processing,
@@ -164,15 +168,18 @@ var pkgDeps = map[string][]string{
 
 	"internal/poll":    {"L0", "internal/race", "syscall", "time", "unicode/utf16", "unicode/utf8", "internal/syscall/windows"},
 	"internal/testlog": {"L0"},
-	"os":               {"L1", "os", "syscall", "time", "internal/poll", "internal/syscall/windows", "internal/syscall/unix", "internal/testlog"},
+	"io/fs":            {"L2", "internal/oserror", "time"},
+	"embed":            {"L2", "io/fs", "time"},
+	"os":               {"L1", "os", "syscall", "time", "internal/poll", "internal/syscall/windows", "internal/syscall/unix", "internal/testlog", "io/fs"},
 	"path/filepath":    {"L2", "os", "syscall", "internal/syscall/windows"},
 	"io/ioutil":        {"L2", "os", "path/filepath", "time"},
-	"os/exec":          {"L2", "os", "context", "path/filepath", "syscall"},
-	"os/signal":        {"L2", "os", "syscall"},
+	"os/exec":          {"L2", "os", "context", "path/filepath", "syscall", "time"},
+	"os/signal":        {"L2", "context", "os", "syscall"},
 
 	// OS enables basic operating system functionality,
 	// but not direct use of package syscall, nor os/signal.
 	"OS": {
+		"io/fs",
 		"io/ioutil",
 		"os",
 		"os/exec",
@@ -192,7 +199,8 @@ var pkgDeps = map[string][]string{
 	"runtime/trace":  {"L0", "context", "fmt"},
 	"text/tabwriter": {"L2"},
 
-	"testing":          {"L2", "flag", "fmt", "internal/race", "os", "runtime/debug", "runtime/pprof", "runtime/trace", "time"},
+	"testing":          {"L2", "flag", "fmt", "internal/race", "os", "path/filepath", "reflect", "runtime/debug", "runtime/pprof", "runtime/trace", "time"},
+	"testing/fstest":   {"L2", "io/fs", "time"},
 	"testing/iotest":   {"L2", "log"},
 	"testing/quick":    {"L2", "flag", "fmt", "reflect", "time"},
 	"internal/testenv": {"L2", "OS", "flag", "testing", "syscall"},
@@ -261,7 +269,7 @@ var pkgDeps = map[string][]string{
 	"encoding/json":                  {"L4", "encoding"},
 	"encoding/pem":                   {"L4"},
 	"encoding/xml":                   {"L4", "encoding"},
-	"flag":                           {"L4", "OS"},
+	"flag":                           {"L4", "OS", "encoding"},
 	"go/build":                       {"L4", "OS", "GOPARSER", "internal/goroot"},
 	"html":                           {"L4"},
 	"image/draw":                     {"L4", "image/internal/imageutil"},
@@ -270,10 +278,12 @@ var pkgDeps = map[string][]string{
 	"image/jpeg":                     {"L4", "image/internal/imageutil"},
 	"image/png":                      {"L4", "compress/zlib"},
 	"index/suffixarray":              {"L4", "regexp"},
+	"internal/fuzz":                  {"L4", "OS", "context", "crypto/sha256"},
 	"internal/goroot":                {"L4", "OS"},
 	"internal/singleflight":          {"sync"},
 	"internal/trace":                 {"L4", "OS", "container/heap"},
 	"internal/xcoff":                 {"L4", "OS", "debug/dwarf"},
+	"log/slog":                       {"L4", "context", "encoding", "encoding/json"},
 	"math/big":                       {"L4"},
 	"mime":                           {"L4", "OS", "syscall", "internal/syscall/windows/registry"},
 	"mime/quotedprintable":           {"L4"},
@@ -281,7 +291,7 @@ var pkgDeps = map[string][]string{
 	"net/url":                        {"L4"},
 	"plugin":                         {"L0", "OS", "CGO"},
 	"runtime/pprof/internal/profile": {"L4", "OS", "compress/gzip", "regexp"},
-	"testing/internal/testdeps":      {"L4", "internal/testlog", "runtime/pprof", "regexp"},
+	"testing/internal/testdeps":      {"L4", "context", "internal/fuzz", "internal/testlog", "os", "os/signal", "reflect", "runtime/pprof", "regexp", "time"},
 	"text/scanner":                   {"L4", "OS"},
 	"text/template/parse":            {"L4"},
 
@@ -316,9 +326,10 @@ var pkgDeps = map[string][]string{
 	// Basic networking.
 	// Because net must be used by any package that wants to
 	// do networking portably, it must have a small dependency set: just L0+basic os.
+	"net/netip": {"L1", "internal/bytealg"},
 	"net": {
 		"L0", "CGO",
-		"context", "math/rand", "os", "reflect", "sort", "syscall", "time",
+		"context", "math/rand", "net/netip", "os", "reflect", "sort", "syscall", "time",
 		"internal/nettrace", "internal/poll", "internal/syscall/unix",
 		"internal/syscall/windows", "internal/singleflight", "internal/race",
 		"internal/x/net/dns/dnsmessage", "internal/x/net/lif", "internal/x/net/route",
@@ -375,10 +386,15 @@ var pkgDeps = map[string][]string{
 	"crypto/elliptic": {"L4", "CRYPTO", "math/big"},
 	"crypto/rsa":      {"L4", "CRYPTO", "crypto/rand", "math/big"},
 
+	// Ed25519 needs crypto/rand only to generate keys.
+	"crypto/ed25519":                       {"L3", "CRYPTO", "crypto/rand", "crypto/ed25519/internal/edwards25519"},
+	"crypto/ed25519/internal/edwards25519": {"L3"},
+
 	"CRYPTO-MATH": {
 		"CRYPTO",
 		"crypto/dsa",
 		"crypto/ecdsa",
+		"crypto/ed25519",
 		"crypto/elliptic",
 		"crypto/rand",
 		"crypto/rsa",
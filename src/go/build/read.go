@@ -6,8 +6,14 @@ package build
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
+	"fmt"
+	"go/token"
 	"io"
+	"strconv"
+	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -245,3 +251,175 @@ func readImports(f io.Reader, reportSyntaxError bool, imports *[]string) ([]byte
 
 	return r.buf, r.err
 }
+
+// fileEmbed is a single //go:embed pattern and its position.
+type fileEmbed struct {
+	pattern string
+	pos     token.Position
+}
+
+// findEmbeds scans the Go source data for //go:embed comments
+// and returns the patterns they list. Like other compiler directives,
+// the comments must begin at the start of a line; text inside
+// string literals and other comments is skipped.
+// The compiler enforces the placement of the comments and reports
+// malformed ones; findEmbeds only needs the patterns, so it
+// returns an error only for comments it cannot parse.
+func findEmbeds(filename string, data []byte) ([]fileEmbed, error) {
+	var list []fileEmbed
+	line, lineStart := 1, 0
+	startOfLine := true
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == '\n':
+			i++
+			line, lineStart = line+1, i
+			startOfLine = true
+			continue
+
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			end := i + bytes.IndexByte(data[i:], '\n')
+			if end < i {
+				end = len(data)
+			}
+			text := string(data[i:end])
+			if startOfLine && strings.HasPrefix(text, "//go:embed") && len(text) > len("//go:embed") && (text[len("//go:embed")] == ' ' || text[len("//go:embed")] == '\t') {
+				pos := token.Position{
+					Filename: filename,
+					Offset:   i,
+					Line:     line,
+					Column:   i - lineStart + 1,
+				}
+				embeds, err := parseGoEmbed(text[len("//go:embed"):], pos)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, embeds...)
+			}
+			i = end
+			continue
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return list, nil
+			}
+			end += i + 2 + 2
+			for j := i; j < end; j++ {
+				if data[j] == '\n' {
+					line, lineStart = line+1, j+1
+				}
+			}
+			i = end
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(data) && data[j] != c && data[j] != '\n' {
+				if data[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			i = j + 1
+
+		case c == '`':
+			end := bytes.IndexByte(data[i+1:], '`')
+			if end < 0 {
+				return list, nil
+			}
+			end += i + 1 + 1
+			for j := i; j < end; j++ {
+				if data[j] == '\n' {
+					line, lineStart = line+1, j+1
+				}
+			}
+			i = end
+
+		default:
+			i++
+		}
+		startOfLine = false
+	}
+	return list, nil
+}
+
+// parseGoEmbed parses the text following "//go:embed" to extract the glob patterns.
+// It accepts unquoted space-separated patterns as well as double-quoted and back-quoted Go strings.
+// There is a copy of this code in cmd/compile/internal/gc/embed.go.
+// This version records the position of each pattern; pos is the position of the comment.
+func parseGoEmbed(args string, pos token.Position) ([]fileEmbed, error) {
+	advance := func(consumed string) {
+		pos.Offset += len(consumed)
+		pos.Column += utf8.RuneCountInString(consumed)
+	}
+	trimSpace := func(s string) string {
+		trim := strings.TrimLeftFunc(s, unicode.IsSpace)
+		advance(s[:len(s)-len(trim)])
+		return trim
+	}
+	pos.Offset += len("//go:embed")
+	pos.Column += len("//go:embed")
+
+	var list []fileEmbed
+	for args = trimSpace(args); args != ""; args = trimSpace(args) {
+		var path string
+		pathPos := pos
+		before := args
+	Switch:
+		switch args[0] {
+		default:
+			i := len(args)
+			for j, c := range args {
+				if unicode.IsSpace(c) {
+					i = j
+					break
+				}
+			}
+			path = args[:i]
+			args = args[i:]
+
+		case '`':
+			i := strings.Index(args[1:], "`")
+			if i < 0 {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+			}
+			path = args[1 : 1+i]
+			args = args[1+i+1:]
+
+		case '"':
+			i := 1
+			for ; i < len(args); i++ {
+				if args[i] == '\\' {
+					i++
+					continue
+				}
+				if args[i] == '"' {
+					q, err := strconv.Unquote(args[:i+1])
+					if err != nil {
+						return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args[:i+1])
+					}
+					path = q
+					args = args[i+1:]
+					break Switch
+				}
+			}
+			if i >= len(args) {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+			}
+		}
+
+		if args != "" {
+			r, _ := utf8.DecodeRuneInString(args)
+			if !unicode.IsSpace(r) {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+			}
+		}
+		list = append(list, fileEmbed{path, pathPos})
+		advance(before[:len(before)-len(args)])
+	}
+	return list, nil
+}
@@ -5,6 +5,7 @@
 package build
 
 import (
+	"fmt"
 	"io"
 	"strings"
 	"testing"
@@ -221,3 +222,84 @@ func TestReadFailuresIgnored(t *testing.T) {
 	}
 	testRead(t, tests, func(r io.Reader) ([]byte, error) { return readImports(r, false, nil) })
 }
+
+var readEmbedTests = []struct {
+	in  string
+	out []string
+}{
+	{
+		"package p\n",
+		nil,
+	},
+	{
+		"package p\nimport \"embed\"\nvar i int\n//go:embed x y z\nvar files embed.FS",
+		[]string{
+			`test:4:12:x`,
+			`test:4:14:y`,
+			`test:4:16:z`,
+		},
+	},
+	{
+		"package p\nimport \"embed\"\nvar i int\n//go:embed x \"\\x79\" `z`\nvar files embed.FS",
+		[]string{
+			`test:4:12:x`,
+			`test:4:14:y`,
+			`test:4:21:z`,
+		},
+	},
+	{
+		"package p\nimport \"embed\"\nvar i int\n//go:embed x\ty\n//go:embed\tz\nvar files embed.FS",
+		[]string{
+			`test:4:12:x`,
+			`test:4:14:y`,
+			`test:5:12:z`,
+		},
+	},
+	{
+		"package p\nimport \"embed\"\nvar (\n\t//go:embed x\n\tfiles embed.FS\n)\n",
+		nil,
+	},
+	{
+		"package p\nimport \"embed\"\nvar s = \"//go:embed x\"\nvar r = `\n//go:embed y\n`\n/*\n//go:embed z\n*/\nvar i int // //go:embed w\n//go:embedded v\n",
+		nil,
+	},
+	{
+		"package p\nimport \"embed\"\n/* a\nb */ //go:embed x\nvar s = `a\nb`\n//go:embed y\nvar files embed.FS",
+		[]string{
+			`test:7:12:y`,
+		},
+	},
+}
+
+func TestReadEmbed(t *testing.T) {
+	for i, tt := range readEmbedTests {
+		embeds, err := findEmbeds("test", []byte(tt.in))
+		if err != nil {
+			t.Errorf("#%d: %v", i, err)
+			continue
+		}
+		var got []string
+		for _, e := range embeds {
+			got = append(got, fmt.Sprintf("%s:%d:%d:%s", e.pos.Filename, e.pos.Line, e.pos.Column, e.pattern))
+		}
+		if !stringsEqual(got, tt.out) {
+			t.Errorf("#%d: findEmbeds:\nhave %q\nwant %q", i, got, tt.out)
+		}
+	}
+
+	if _, err := findEmbeds("test", []byte("package p\n//go:embed \"x\n")); err == nil {
+		t.Errorf("findEmbeds with bad quoted string: no error")
+	}
+}
+
+func stringsEqual(x, y []string) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
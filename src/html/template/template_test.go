@@ -9,6 +9,7 @@ import (
 	. "html/template"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestTemplateClone(t *testing.T) {
@@ -110,11 +111,38 @@ func TestRedefineOtherParsers(t *testing.T) {
 	if _, err := c.root.ParseGlob("*.no.template"); err == nil || !strings.Contains(err.Error(), "Execute") {
 		t.Errorf("ParseGlob: %v\nwanted error about already having Executed", err)
 	}
+	if _, err := c.root.ParseFS(fstest.MapFS{}, "*.no.template"); err == nil || !strings.Contains(err.Error(), "Execute") {
+		t.Errorf("ParseFS: %v\nwanted error about already having Executed", err)
+	}
 	if _, err := c.root.AddParseTree("t1", c.root.Tree); err == nil || !strings.Contains(err.Error(), "Execute") {
 		t.Errorf("AddParseTree: %v\nwanted error about already having Executed", err)
 	}
 }
 
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.tmpl":     {Data: []byte(`{{define "a"}}<a href="{{.}}">{{template "b" .}}</a>{{end}}`)},
+		"sub/b.tmpl": {Data: []byte(`{{define "b"}}{{.}}{{end}}`)},
+	}
+	tmpl, err := ParseFS(fsys, "a.tmpl", "sub/*.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tmpl.Name(), "a.tmpl"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	var b bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&b, "a", "x y"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.String(), `<a href="x%20y">x y</a>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if _, err := ParseFS(fsys, "missing.tmpl"); err == nil {
+		t.Error("expected error for non-existent file; got none")
+	}
+}
+
 type testCase struct {
 	t    *testing.T
 	root *Template
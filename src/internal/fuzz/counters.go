@@ -0,0 +1,81 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fuzz
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// _counters and _ecounters mark the start and end, respectively, of where
+// the 8-bit coverage counters reside in memory. They're known to cmd/link,
+// which specially assigns their addresses for this purpose.
+var _counters, _ecounters [0]byte
+
+// coverage returns a []byte containing unique 8-bit counters for each edge of
+// the instrumented source code. This coverage data will only be generated if
+// -d=libfuzzer is set at build time. This can be used to understand the code
+// coverage of a test execution.
+func coverage() []byte {
+	addr := unsafe.Pointer(&_counters)
+	size := uintptr(unsafe.Pointer(&_ecounters)) - uintptr(addr)
+
+	var res []byte
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&res))
+	hdr.Data = uintptr(addr)
+	hdr.Len = int(size)
+	hdr.Cap = int(size)
+	return res
+}
+
+// resetCoverage sets all of the counters for each edge of the instrumented
+// source code to 0.
+func resetCoverage() {
+	cov := coverage()
+	for i := range cov {
+		cov[i] = 0
+	}
+}
+
+// coverageBucket maps a raw edge counter to a single bit standing for a
+// coarse range of hit counts, so that a loop running a few more times
+// than before is not mistaken for new behavior.
+func coverageBucket(n byte) byte {
+	switch {
+	case n == 0:
+		return 0
+	case n == 1:
+		return 1
+	case n == 2:
+		return 2
+	case n == 3:
+		return 4
+	case n <= 7:
+		return 8
+	case n <= 15:
+		return 16
+	case n <= 31:
+		return 32
+	case n <= 127:
+		return 64
+	default:
+		return 128
+	}
+}
+
+// updateCoverage merges the bucketed counters in snapshot into seen.
+// It reports whether snapshot contained a bucket not already present
+// in seen.
+func updateCoverage(seen, snapshot []byte) bool {
+	newCoverage := false
+	for i, n := range snapshot {
+		b := coverageBucket(n)
+		if b&^seen[i] != 0 {
+			seen[i] |= b
+			newCoverage = true
+		}
+	}
+	return newCoverage
+}
@@ -0,0 +1,192 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fuzz
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// encVersion1 will be the first line of a file with version 1 encoding.
+var encVersion1 = "go test fuzz v1"
+
+// marshalCorpusFile encodes an arbitrary number of arguments into the file
+// format for the corpus. Each value is written on its own line as a Go
+// conversion expression, such as []byte("abc") or int(-1).
+func marshalCorpusFile(vals ...interface{}) []byte {
+	if len(vals) == 0 {
+		panic("must have at least one value to marshal")
+	}
+	b := bytes.NewBuffer([]byte(encVersion1 + "\n"))
+	for _, val := range vals {
+		switch t := val.(type) {
+		case int, int8, int16, int64, uint, uint16, uint32, uint64, bool:
+			fmt.Fprintf(b, "%T(%v)\n", t, t)
+		case float32:
+			fmt.Fprintf(b, "float32(%s)\n", strconv.FormatFloat(float64(t), 'g', -1, 32))
+		case float64:
+			fmt.Fprintf(b, "float64(%s)\n", strconv.FormatFloat(t, 'g', -1, 64))
+		case string:
+			fmt.Fprintf(b, "string(%q)\n", t)
+		case rune: // int32
+			if utf8.ValidRune(t) {
+				fmt.Fprintf(b, "rune(%q)\n", t)
+			} else {
+				fmt.Fprintf(b, "int32(%d)\n", t)
+			}
+		case byte: // uint8
+			fmt.Fprintf(b, "byte(%q)\n", t)
+		case []byte: // []uint8
+			fmt.Fprintf(b, "[]byte(%q)\n", t)
+		default:
+			panic(fmt.Sprintf("unsupported type: %T", t))
+		}
+	}
+	return b.Bytes()
+}
+
+// unmarshalCorpusFile decodes corpus bytes into their respective values.
+func unmarshalCorpusFile(b []byte) ([]interface{}, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("cannot unmarshal empty string")
+	}
+	lines := bytes.Split(b, []byte("\n"))
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("must include version and at least one value")
+	}
+	if string(bytes.TrimSpace(lines[0])) != encVersion1 {
+		return nil, fmt.Errorf("unknown encoding version: %s", lines[0])
+	}
+	var vals []interface{}
+	for _, line := range lines[1:] {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		v, err := parseCorpusValue(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("malformed line %q: %v", line, err)
+		}
+		vals = append(vals, v)
+	}
+	if len(vals) == 0 {
+		return nil, fmt.Errorf("must include version and at least one value")
+	}
+	return vals, nil
+}
+
+// parseCorpusValue parses a single line of a corpus file, which must
+// have the form typ(literal).
+func parseCorpusValue(line string) (interface{}, error) {
+	i := strings.IndexByte(line, '(')
+	if i < 0 || line[len(line)-1] != ')' {
+		return nil, fmt.Errorf("expected conversion expression")
+	}
+	typ, arg := line[:i], line[i+1:len(line)-1]
+	switch typ {
+	case "[]byte":
+		s, err := strconv.Unquote(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid []byte literal: %s", arg)
+		}
+		return []byte(s), nil
+	case "string":
+		s, err := strconv.Unquote(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string literal: %s", arg)
+		}
+		return s, nil
+	case "bool":
+		switch arg {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid bool literal: %s", arg)
+	case "byte", "rune":
+		if len(arg) > 0 && arg[0] == '\'' {
+			s, err := strconv.Unquote(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s literal: %s", typ, arg)
+			}
+			r, size := utf8.DecodeRuneInString(s)
+			if size != len(s) || r == utf8.RuneError && size <= 1 {
+				return nil, fmt.Errorf("invalid %s literal: %s", typ, arg)
+			}
+			if typ == "byte" {
+				if r > 0xff {
+					return nil, fmt.Errorf("byte literal out of range: %s", arg)
+				}
+				return byte(r), nil
+			}
+			return r, nil
+		}
+		if typ == "byte" {
+			return parseUint(arg, "uint8")
+		}
+		return parseInt(arg, "int32")
+	case "int", "int8", "int16", "int32", "int64":
+		return parseInt(arg, typ)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return parseUint(arg, typ)
+	case "float32":
+		f, err := strconv.ParseFloat(arg, 32)
+		if err != nil {
+			return nil, err
+		}
+		return float32(f), nil
+	case "float64":
+		return strconv.ParseFloat(arg, 64)
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
+
+// parseInt returns an integer of value val and type typ.
+func parseInt(val, typ string) (interface{}, error) {
+	switch typ {
+	case "int":
+		i, err := strconv.ParseInt(val, 0, strconv.IntSize)
+		return int(i), err
+	case "int8":
+		i, err := strconv.ParseInt(val, 0, 8)
+		return int8(i), err
+	case "int16":
+		i, err := strconv.ParseInt(val, 0, 16)
+		return int16(i), err
+	case "int32":
+		i, err := strconv.ParseInt(val, 0, 32)
+		return int32(i), err
+	case "int64":
+		return strconv.ParseInt(val, 0, 64)
+	default:
+		panic("unreachable")
+	}
+}
+
+// parseUint returns an unsigned integer of value val and type typ.
+func parseUint(val, typ string) (interface{}, error) {
+	switch typ {
+	case "uint":
+		i, err := strconv.ParseUint(val, 0, strconv.IntSize)
+		return uint(i), err
+	case "uint8":
+		i, err := strconv.ParseUint(val, 0, 8)
+		return uint8(i), err
+	case "uint16":
+		i, err := strconv.ParseUint(val, 0, 16)
+		return uint16(i), err
+	case "uint32":
+		i, err := strconv.ParseUint(val, 0, 32)
+		return uint32(i), err
+	case "uint64":
+		return strconv.ParseUint(val, 0, 64)
+	default:
+		panic("unreachable")
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fuzz
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalMarshal(t *testing.T) {
+	var tests = []struct {
+		in string
+		ok bool
+	}{
+		{
+			in: "int(1234)",
+			ok: false, // missing version
+		},
+		{
+			in: `go test fuzz v1
+string("a"bcad")`,
+			ok: false, // malformed
+		},
+		{
+			in: `go test fuzz v1
+int()`,
+			ok: false, // empty value
+		},
+		{
+			in: `go test fuzz v1
+uint(-32)`,
+			ok: false, // invalid negative uint
+		},
+		{
+			in: `go test fuzz v1
+int8(1234456)`,
+			ok: false, // int8 too large
+		},
+		{
+			in: `go test fuzz v1
+int(20*5)`,
+			ok: false, // expression in int value
+		},
+		{
+			in: `go test fuzz v1
+int(--5)`,
+			ok: false, // expression in int value
+		},
+		{
+			in: `go test fuzz v1
+bool(0)`,
+			ok: false, // malformed bool
+		},
+		{
+			in: `go test fuzz v1
+byte('aa)`,
+			ok: false, // malformed byte
+		},
+		{
+			in: `go test fuzz v1
+byte('☃')`,
+			ok: false, // byte out of range
+		},
+		{
+			in: `go test fuzz v1
+complex64(1)`,
+			ok: false, // unsupported type
+		},
+		{
+			in: `go test fuzz v1
+string("hello\\xbd\\xb2=\\xbc ⌘")
+[]byte("a\x00b")
+int(-23)
+int8(-3)
+int16(2)
+int32(-123)
+int64(-10)
+uint(24)
+uint16(3)
+uint32(20000)
+uint64(1)
+float32(1.5)
+float64(-1e+06)
+float64(NaN)
+float64(+Inf)
+bool(true)
+rune('A')
+rune('\x00')
+int32(-1)
+byte('\x00')
+byte('ÿ')
+byte('c')`,
+			ok: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			vals, err := unmarshalCorpusFile([]byte(test.in))
+			if test.ok && err != nil {
+				t.Fatalf("unmarshal unexpected error: %v", err)
+			} else if !test.ok && err == nil {
+				t.Fatalf("unmarshal unexpected success")
+			}
+			if !test.ok {
+				return // skip the rest of the test
+			}
+			newB := marshalCorpusFile(vals...)
+			want := test.in + "\n"
+			if string(newB) != want {
+				t.Errorf("values changed after unmarshal then marshal\nbefore: %q\nafter:  %q", want, newB)
+			}
+		})
+	}
+}
+
+func TestMarshalUnmarshalValues(t *testing.T) {
+	vals := []interface{}{
+		[]byte{0, 1, 0xff, '"', '\\'},
+		"\x00⌘\xff",
+		true,
+		int(math.MinInt32),
+		int8(math.MinInt8),
+		int16(math.MaxInt16),
+		int32(math.MaxInt32),
+		int64(math.MinInt64),
+		uint(math.MaxUint32),
+		uint8(math.MaxUint8),
+		uint16(math.MaxUint16),
+		uint32(math.MaxUint32),
+		uint64(math.MaxUint64),
+		float32(math.SmallestNonzeroFloat32),
+		math.MaxFloat64,
+		math.Inf(-1),
+		rune(0x10FFFF),
+		rune(-5),
+	}
+	got, err := unmarshalCorpusFile(marshalCorpusFile(vals...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, vals) {
+		t.Errorf("round trip:\nhave %#v\nwant %#v", got, vals)
+	}
+
+	nan, err := unmarshalCorpusFile(marshalCorpusFile(math.NaN()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, ok := nan[0].(float64); !ok || !math.IsNaN(f) {
+		t.Errorf("round trip of NaN: have %#v", nan[0])
+	}
+}
+
+func TestCheckCorpus(t *testing.T) {
+	types := []reflect.Type{reflect.TypeOf([]byte(nil)), reflect.TypeOf(0)}
+	for i, tt := range []struct {
+		vals []interface{}
+		ok   bool
+	}{
+		{[]interface{}{[]byte("x"), 1}, true},
+		{[]interface{}{[]byte("x")}, false},
+		{[]interface{}{"x", 1}, false},
+		{[]interface{}{[]byte("x"), int64(1)}, false},
+	} {
+		err := CheckCorpus(tt.vals, types)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("#%d: CheckCorpus(%v) = %v, want ok=%v", i, tt.vals, err, tt.ok)
+		}
+	}
+}
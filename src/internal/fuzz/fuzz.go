@@ -0,0 +1,426 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fuzz provides common fuzzing functionality for tests built with
+// "go test" and for programs that use fuzzing functionality in the testing
+// package.
+package fuzz
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CoordinateFuzzingOpts is a set of arguments for CoordinateFuzzing.
+// The zero value is valid for each field unless specified otherwise.
+type CoordinateFuzzingOpts struct {
+	// Log is a writer for logging progress messages and warnings.
+	// If nil, ioutil.Discard will be used instead.
+	Log io.Writer
+
+	// Timeout is the amount of wall clock time to spend fuzzing after the
+	// corpus has loaded. If zero, there will be no time limit.
+	Timeout time.Duration
+
+	// Limit is the number of random values to generate and test. If zero,
+	// there will be no limit on the number of generated values.
+	Limit int64
+
+	// MinimizeTimeout is the amount of wall clock time to spend minimizing
+	// after discovering a crasher. If zero, there will be no time limit.
+	MinimizeTimeout time.Duration
+
+	// Seed is a list of seed values added by the fuzz target with
+	// testing.F.Add and in testdata.
+	Seed []CorpusEntry
+
+	// Types is the list of types which make up a corpus entry.
+	// Types must be set and must match values in Seed.
+	Types []reflect.Type
+
+	// CorpusDir is a directory where files containing values that crash the
+	// code being tested may be written. CorpusDir must be set.
+	CorpusDir string
+
+	// CacheDir is a directory containing additional "interesting" values.
+	// The fuzzer may derive new values from these, and may write new values
+	// here. If empty, interesting values are kept only in memory.
+	CacheDir string
+}
+
+// CorpusEntry represents an individual input for fuzzing.
+//
+// We must use an equivalent type in the testing and testing/internal/testdeps
+// packages, but testing can't import this package directly, and we don't want
+// to export this type from testing. Instead, we use the same struct type and
+// use a type alias (not a defined type) for convenience.
+type CorpusEntry = struct {
+	Parent string
+
+	// Path is the path of the corpus file, if the entry was loaded
+	// from or written to disk. For seed values provided by f.Add,
+	// Path is the name of the entry, e.g. seed#0.
+	Path string
+
+	// Data is the encoded form of Values, if known.
+	Data []byte
+
+	// Values is the unmarshaled values from a corpus file.
+	Values []interface{}
+
+	Generation int
+
+	// IsSeed indicates whether this entry is part of the seed corpus.
+	IsSeed bool
+}
+
+// maxBytes is the maximum total size of the []byte and string values
+// in a generated input.
+const maxBytes = 1 << 20
+
+// CoordinateFuzzing fuzzes fn, which runs the code being tested on the
+// values in a corpus entry and returns a non-nil error if it fails.
+//
+// CoordinateFuzzing first runs fn on each entry in the seed corpus and on
+// each value stored in opts.CacheDir, recording the code coverage each
+// reaches. It then repeatedly mutates entries from this corpus and calls
+// fn with the results. Inputs that reach new coverage are added to the
+// corpus and written to opts.CacheDir.
+//
+// If fn fails on a generated input, CoordinateFuzzing minimizes the input,
+// writes it to a new file in opts.CorpusDir, and returns an error whose
+// CrashPath method reports the name of that file. If fn fails on an entry
+// already in the corpus, that error is returned without minimization.
+//
+// CoordinateFuzzing returns nil when opts.Timeout or opts.Limit is
+// reached, or ctx.Err() if ctx is cancelled first.
+func CoordinateFuzzing(ctx context.Context, opts CoordinateFuzzingOpts, fn func(CorpusEntry) error) (err error) {
+	if opts.Log == nil {
+		opts.Log = ioutil.Discard
+	}
+	if opts.Timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	c, err := newCoordinator(opts, fn)
+	if err != nil {
+		return err
+	}
+
+	// Gather baseline coverage by running every entry in the corpus.
+	fmt.Fprintf(opts.Log, "fuzz: elapsed: 0s, gathering baseline coverage: 0/%d completed\n", len(c.corpus))
+	for _, e := range c.corpus {
+		if err := c.run(e); err != nil {
+			if e.IsSeed {
+				return fmt.Errorf("fuzz: seed corpus entry %s failed:\n%v", e.Path, err)
+			}
+			// The value was found by an earlier fuzzing run but is not
+			// yet in testdata, so save it there as a crasher.
+			e.Data = marshalCorpusFile(e.Values...)
+			if werr := writeToCorpus(&e, opts.CorpusDir); werr != nil {
+				return werr
+			}
+			return &crashError{path: e.Path, err: err}
+		}
+		updateCoverage(c.seenCoverage, c.snapshot)
+	}
+	if len(c.snapshot) == 0 {
+		fmt.Fprintf(opts.Log, "fuzz: warning: coverage instrumentation not found; fuzzing without coverage guidance\n")
+	}
+	c.start = time.Now()
+	c.count = 0
+	fmt.Fprintf(opts.Log, "fuzz: elapsed: 0s, gathering baseline coverage: %d/%d completed, now fuzzing\n", len(c.corpus), len(c.corpus))
+
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+	defer func() { c.logStats() }()
+	for {
+		select {
+		case <-ctx.Done():
+			if opts.Timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+				return nil
+			}
+			return ctx.Err()
+		case <-ticker.C:
+			c.logStats()
+		default:
+		}
+		if opts.Limit > 0 && c.count >= opts.Limit {
+			return nil
+		}
+
+		parent := c.corpus[c.next]
+		c.next = (c.next + 1) % len(c.corpus)
+		vals := append([]interface{}(nil), parent.Values...)
+		c.mutator.mutate(vals, maxBytes)
+		e := CorpusEntry{
+			Parent:     parent.Path,
+			Values:     vals,
+			Generation: parent.Generation + 1,
+		}
+
+		if err := c.run(e); err != nil {
+			e, err = c.minimize(ctx, e, err)
+			e.Data = marshalCorpusFile(e.Values...)
+			if werr := writeToCorpus(&e, opts.CorpusDir); werr != nil {
+				return werr
+			}
+			return &crashError{path: e.Path, err: err}
+		}
+
+		if updateCoverage(c.seenCoverage, c.snapshot) {
+			e.Data = marshalCorpusFile(e.Values...)
+			if opts.CacheDir != "" {
+				if err := writeToCorpus(&e, opts.CacheDir); err != nil {
+					return err
+				}
+			}
+			c.corpus = append(c.corpus, e)
+			c.interesting++
+		}
+	}
+}
+
+// coordinator holds the state of a single call to CoordinateFuzzing.
+type coordinator struct {
+	opts    CoordinateFuzzingOpts
+	fn      func(CorpusEntry) error
+	mutator *mutator
+
+	// start is the time fuzzing began, after the baseline run.
+	start time.Time
+
+	// count is the number of values tested since start.
+	count int64
+
+	// corpus is the set of inputs mutated to produce new values, and
+	// next is the index of the entry to mutate next.
+	corpus []CorpusEntry
+	next   int
+
+	// interesting is the number of new values added to the corpus since
+	// start.
+	interesting int
+
+	// snapshot holds the coverage counters of the most recent run, and
+	// seenCoverage the bucketed counters of all runs so far.
+	snapshot     []byte
+	seenCoverage []byte
+}
+
+func newCoordinator(opts CoordinateFuzzingOpts, fn func(CorpusEntry) error) (*coordinator, error) {
+	for _, e := range opts.Seed {
+		if err := CheckCorpus(e.Values, opts.Types); err != nil {
+			return nil, err
+		}
+	}
+	c := &coordinator{
+		opts:    opts,
+		fn:      fn,
+		mutator: newMutator(),
+		corpus:  append([]CorpusEntry(nil), opts.Seed...),
+		start:   time.Now(),
+	}
+	if opts.CacheDir != "" {
+		// Entries in the cache that no longer match the fuzz function's
+		// types are left over from an earlier version of the test and
+		// are ignored.
+		cached, _ := ReadCorpus(opts.CacheDir, opts.Types)
+		c.corpus = append(c.corpus, cached...)
+	}
+	if len(c.corpus) == 0 {
+		c.corpus = append(c.corpus, CorpusEntry{Values: zeroValues(opts.Types)})
+	}
+	size := len(coverage())
+	c.snapshot = make([]byte, size)
+	c.seenCoverage = make([]byte, size)
+	return c, nil
+}
+
+// run calls the fuzz function on e, recording the coverage it reaches in
+// c.snapshot.
+func (c *coordinator) run(e CorpusEntry) error {
+	resetCoverage()
+	err := c.fn(e)
+	copy(c.snapshot, coverage())
+	c.count++
+	return err
+}
+
+// minimize tries to find a smaller input than e for which the fuzz
+// function still fails, by shrinking each []byte and string value in turn.
+// It returns the smallest failing input found and the error it caused.
+func (c *coordinator) minimize(ctx context.Context, e CorpusEntry, err error) (CorpusEntry, error) {
+	var deadline time.Time
+	if c.opts.MinimizeTimeout > 0 {
+		deadline = time.Now().Add(c.opts.MinimizeTimeout)
+	}
+	shouldStop := func() bool {
+		return ctx.Err() != nil || !deadline.IsZero() && time.Now().After(deadline)
+	}
+
+	fmt.Fprintf(c.opts.Log, "fuzz: elapsed: %s, minimizing\n", c.elapsed())
+	vals := append([]interface{}(nil), e.Values...)
+	for i, v := range vals {
+		var b []byte
+		var set func([]byte)
+		switch v := v.(type) {
+		case []byte:
+			b = append([]byte(nil), v...)
+			set = func(b []byte) { vals[i] = b }
+		case string:
+			b = []byte(v)
+			set = func(b []byte) { vals[i] = string(b) }
+		default:
+			continue
+		}
+		try := func(candidate []byte) bool {
+			set(candidate)
+			if cerr := c.fn(CorpusEntry{Values: vals}); cerr != nil {
+				err = cerr
+				return true
+			}
+			return false
+		}
+		set(append([]byte(nil), minimizeBytes(b, try, shouldStop)...))
+	}
+	e.Values = vals
+	return e, err
+}
+
+func (c *coordinator) elapsed() time.Duration {
+	return time.Since(c.start).Round(time.Second)
+}
+
+func (c *coordinator) logStats() {
+	rate := float64(c.count) / time.Since(c.start).Seconds()
+	fmt.Fprintf(c.opts.Log, "fuzz: elapsed: %s, execs: %d (%.0f/sec), new interesting: %d (total: %d)\n", c.elapsed(), c.count, rate, c.interesting, len(c.corpus))
+}
+
+// zeroValues returns the zero value of each of the given types.
+func zeroValues(types []reflect.Type) []interface{} {
+	vals := make([]interface{}, len(types))
+	for i, t := range types {
+		vals[i] = reflect.Zero(t).Interface()
+	}
+	return vals
+}
+
+// crashError wraps a crasher written to the seed corpus. It saves the name
+// of the file where the input causing the crasher was saved. The testing
+// framework uses this to report a command to re-run that specific input.
+type crashError struct {
+	path string
+	err  error
+}
+
+func (e *crashError) Error() string {
+	return e.err.Error()
+}
+
+func (e *crashError) Unwrap() error {
+	return e.err
+}
+
+// CrashPath returns the name of the file holding the failing input.
+func (e *crashError) CrashPath() string {
+	return e.path
+}
+
+// malformedCorpusError is returned by ReadCorpus when one or more files
+// in the corpus directory could not be decoded.
+type malformedCorpusError struct {
+	errs []error
+}
+
+func (e *malformedCorpusError) Error() string {
+	var msgs []string
+	for _, s := range e.errs {
+		msgs = append(msgs, s.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ReadCorpus reads the corpus from the provided dir. The returned corpus
+// entries are guaranteed to match the given types. Any malformed files are
+// reported together in the returned error, along with the entries that
+// could be read.
+func ReadCorpus(dir string, types []reflect.Type) ([]CorpusEntry, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil // No corpus to read
+	} else if err != nil {
+		return nil, fmt.Errorf("reading seed corpus from testdata: %v", err)
+	}
+	var corpus []CorpusEntry
+	var errs []error
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		filename := filepath.Join(dir, file.Name())
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read corpus file: %v", err)
+		}
+		vals, err := unmarshalCorpusFile(data)
+		if err == nil {
+			err = CheckCorpus(vals, types)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %v", filename, err))
+			continue
+		}
+		corpus = append(corpus, CorpusEntry{Path: filename, Data: data, Values: vals})
+	}
+	if len(errs) > 0 {
+		return corpus, &malformedCorpusError{errs: errs}
+	}
+	return corpus, nil
+}
+
+// CheckCorpus verifies that the types in vals match the expected types
+// provided.
+func CheckCorpus(vals []interface{}, types []reflect.Type) error {
+	if len(vals) != len(types) {
+		return fmt.Errorf("wrong number of values in corpus entry: %d, want %d", len(vals), len(types))
+	}
+	for i := range types {
+		if reflect.TypeOf(vals[i]) != types[i] {
+			valsT := make([]reflect.Type, len(vals))
+			for j, v := range vals {
+				valsT[j] = reflect.TypeOf(v)
+			}
+			return fmt.Errorf("mismatched types in corpus entry: %v, want %v", valsT, types)
+		}
+	}
+	return nil
+}
+
+// writeToCorpus writes entry.Data to a new file in dir, named after the
+// hash of its contents, creating dir if it does not exist. If the file
+// already exists, writeToCorpus rewrites it with the same contents.
+// writeToCorpus sets entry.Path to the name of the file.
+func writeToCorpus(entry *CorpusEntry, dir string) error {
+	sum := fmt.Sprintf("%x", sha256.Sum256(entry.Data))[:16]
+	entry.Path = filepath.Join(dir, sum)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(entry.Path, entry.Data, 0666); err != nil {
+		os.Remove(entry.Path) // remove partially written file
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fuzz
+
+import "testing"
+
+func TestMinimizeBytes(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+		fails    func([]byte) bool
+	}{
+		{
+			in:   "aaaaaaaaaabbbbbbbbbbXcccccccccc",
+			want: "X",
+			fails: func(b []byte) bool {
+				for _, c := range b {
+					if c == 'X' {
+						return true
+					}
+				}
+				return false
+			},
+		},
+		{
+			in:   "0123456789",
+			want: "00",
+			fails: func(b []byte) bool {
+				return len(b) >= 2
+			},
+		},
+		{
+			in:   "\x01\x02\x03",
+			want: "0",
+			fails: func(b []byte) bool {
+				return len(b) > 0
+			},
+		},
+	} {
+		got := minimizeBytes([]byte(tt.in), tt.fails, func() bool { return false })
+		if string(got) != tt.want {
+			t.Errorf("minimizeBytes(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
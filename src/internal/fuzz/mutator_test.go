@@ -0,0 +1,35 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fuzz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMutator(t *testing.T) {
+	m := newMutator()
+	vals := []interface{}{[]byte("hello"), "world", 0, uint8(1), float64(2), true}
+	orig := append([]interface{}(nil), vals...)
+	for i := 0; i < 1000; i++ {
+		m.mutate(vals, 64)
+		if err := CheckCorpus(vals, []reflect.Type{
+			reflect.TypeOf([]byte(nil)),
+			reflect.TypeOf(""),
+			reflect.TypeOf(0),
+			reflect.TypeOf(uint8(0)),
+			reflect.TypeOf(float64(0)),
+			reflect.TypeOf(false),
+		}); err != nil {
+			t.Fatalf("after %d mutations: %v", i+1, err)
+		}
+		if b, s := vals[0].([]byte), vals[1].(string); len(b) > 64/len(vals) || len(s) > 64/len(vals) {
+			t.Fatalf("after %d mutations: values %q and %q longer than %d bytes", i+1, b, s, 64/len(vals))
+		}
+	}
+	if reflect.DeepEqual(vals, orig) {
+		t.Errorf("values unchanged after 1000 mutations")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package reflectlite_test
+
+import (
+	. "internal/reflectlite"
+	"io"
+	"os"
+	"testing"
+)
+
+type implementsTest struct {
+	x interface{}
+	t interface{}
+	b bool
+}
+
+var implementsTests = []implementsTest{
+	{new(*os.File), new(io.Reader), true},
+	{new(*os.File), new(io.ReadWriteCloser), true},
+	{new(os.File), new(io.Reader), false},
+	{new(*os.PathError), new(error), true},
+	{new(os.PathError), new(error), false},
+	{new(io.ReadWriter), new(io.Reader), true},
+	{new(io.Reader), new(io.ReadWriter), false},
+}
+
+func TestImplements(t *testing.T) {
+	for _, tt := range implementsTests {
+		xv := TypeOf(tt.x).Elem()
+		xt := TypeOf(tt.t).Elem()
+		if b := xv.Implements(xt); b != tt.b {
+			t.Errorf("(%s).Implements(%s) = %v, want %v", xv.String(), xt.String(), b, tt.b)
+		}
+	}
+}
+
+type IntPtr *int
+type IntPtr1 *int
+
+var assignableTests = []struct {
+	x interface{}
+	t interface{}
+	b bool
+}{
+	{new(chan int), new(<-chan int), true},
+	{new(<-chan int), new(chan int), false},
+	{new(*int), new(IntPtr), true},
+	{new(IntPtr), new(*int), true},
+	{new(IntPtr), new(IntPtr1), false},
+	{new(*os.PathError), new(error), true},
+	{new(error), new(*os.PathError), false},
+}
+
+func TestAssignableTo(t *testing.T) {
+	for _, tt := range assignableTests {
+		v := TypeOf(tt.x).Elem()
+		typ := TypeOf(tt.t).Elem()
+		if b := v.AssignableTo(typ); b != tt.b {
+			t.Errorf("(%s).AssignableTo(%s) = %v, want %v", v.String(), typ.String(), b, tt.b)
+		}
+	}
+}
+
+func TestComparable(t *testing.T) {
+	for _, tt := range []struct {
+		x interface{}
+		b bool
+	}{
+		{0, true},
+		{"", true},
+		{[]int(nil), false},
+		{map[int]int(nil), false},
+		{struct{ f []int }{}, false},
+		{&struct{ f []int }{}, true},
+	} {
+		if b := TypeOf(tt.x).Comparable(); b != tt.b {
+			t.Errorf("TypeOf(%T).Comparable() = %v, want %v", tt.x, b, tt.b)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	var err error
+	pe := &os.PathError{Op: "open", Path: "x", Err: os.ErrNotExist}
+	ValueOf(&err).Elem().Set(ValueOf(pe))
+	if err != pe {
+		t.Errorf("Set(error) = %v, want %v", err, pe)
+	}
+
+	var p *os.PathError
+	ValueOf(&p).Elem().Set(ValueOf(pe))
+	if p != pe {
+		t.Errorf("Set(*os.PathError) = %v, want %v", p, pe)
+	}
+
+	var r io.Reader = os.Stdin
+	ValueOf(&r).Elem().Set(ValueOf(os.Stdout))
+	if r != os.Stdout {
+		t.Errorf("Set(io.Reader) = %v, want %v", r, os.Stdout)
+	}
+}
+
+func TestNames(t *testing.T) {
+	typ := TypeOf(os.PathError{})
+	if got, want := typ.Name(), "PathError"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	if got, want := typ.PkgPath(), "io/fs"; got != want {
+		t.Errorf("PkgPath() = %q, want %q", got, want)
+	}
+	if got, want := TypeOf(&os.PathError{}).String(), "*fs.PathError"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if k := TypeOf(&os.PathError{}).Kind(); k != Ptr {
+		t.Errorf("Kind() = %v, want Ptr", k)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	. "io/fs"
+	"testing"
+)
+
+var isValidPathTests = []struct {
+	name string
+	ok   bool
+}{
+	{".", true},
+	{"x", true},
+	{"x/y", true},
+
+	{"", false},
+	{"..", false},
+	{"/", false},
+	{"x/", false},
+	{"/x", false},
+	{"x/y/", false},
+	{"/x/y", false},
+	{"./", false},
+	{"./x", false},
+	{"x/.", false},
+	{"x/./y", false},
+	{"../", false},
+	{"../x", false},
+	{"x/..", false},
+	{"x/../y", false},
+	{"x//y", false},
+	{`x\`, true},
+	{`x\y`, true},
+	{`\x`, true},
+}
+
+func TestValidPath(t *testing.T) {
+	for _, tt := range isValidPathTests {
+		ok := ValidPath(tt.name)
+		if ok != tt.ok {
+			t.Errorf("ValidPath(%q) = %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+}
+
+var fileModeStringTests = []struct {
+	mode FileMode
+	want string
+}{
+	{0, "----------"},
+	{0755, "-rwxr-xr-x"},
+	{ModeDir | 0755, "drwxr-xr-x"},
+	{ModeSymlink | 0777, "Lrwxrwxrwx"},
+	{ModeNamedPipe | 0600, "prw-------"},
+}
+
+func TestFileModeString(t *testing.T) {
+	for _, tt := range fileModeStringTests {
+		if got := tt.mode.String(); got != tt.want {
+			t.Errorf("FileMode(%#o).String() = %q, want %q", uint32(tt.mode), got, tt.want)
+		}
+	}
+}
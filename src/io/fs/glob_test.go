@@ -0,0 +1,50 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	. "io/fs"
+	"path"
+	"reflect"
+	"testing"
+)
+
+var globTests = []struct {
+	pattern string
+	matches []string
+}{
+	{"a", []string{"a"}},
+	{"*", []string{"a", "b", "g", "i"}},
+	{"b/*", []string{"b/c", "b/d"}},
+	{"b/d/[ef]", []string{"b/d/e", "b/d/f"}},
+	{"*/h.txt", []string{"g/h.txt"}},
+	{"*/*/e", []string{"b/d/e"}},
+	{"missing", nil},
+	{"x/*", nil},
+}
+
+func TestGlob(t *testing.T) {
+	for _, fsys := range []FS{testFS, openOnly{testFS}} {
+		for _, tt := range globTests {
+			matches, err := Glob(fsys, tt.pattern)
+			if err != nil {
+				t.Errorf("Glob(%#q): %v", tt.pattern, err)
+				continue
+			}
+			if !reflect.DeepEqual(matches, tt.matches) {
+				t.Errorf("Glob(%#q) = %q, want %q", tt.pattern, matches, tt.matches)
+			}
+		}
+	}
+}
+
+func TestGlobError(t *testing.T) {
+	bad := []string{`[]`, `*/[]`}
+	for _, pattern := range bad {
+		if _, err := Glob(openOnly{testFS}, pattern); err != path.ErrBadPattern {
+			t.Errorf("Glob(%#q) = %v, want ErrBadPattern", pattern, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	. "io/fs"
+	"testing"
+)
+
+func TestReadDir(t *testing.T) {
+	check := func(desc string, dirs []DirEntry, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: ReadDir: %v", desc, err)
+		}
+		if len(dirs) != 2 || dirs[0].Name() != "c" || dirs[0].IsDir() || dirs[1].Name() != "d" || !dirs[1].IsDir() {
+			var names []string
+			for _, d := range dirs {
+				names = append(names, d.Name())
+			}
+			t.Errorf("%s: ReadDir = %q, want [c d]", desc, names)
+		}
+	}
+
+	// Test that ReadDir uses the method when present.
+	dirs, err := ReadDir(testFS, "b")
+	check("MapFS", dirs, err)
+
+	// Test that ReadDir uses Open when the method is not present.
+	dirs, err = ReadDir(openOnly{testFS}, "b")
+	check("openOnly", dirs, err)
+
+	// Test that ReadDir on a plain file fails.
+	if _, err := ReadDir(openOnly{testFS}, "a"); err == nil {
+		t.Error("ReadDir of plain file succeeded, want error")
+	}
+}
+
+func TestFileInfoToDirEntry(t *testing.T) {
+	for _, name := range []string{"a", "b", "i"} {
+		info, err := Stat(testFS, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := FileInfoToDirEntry(info)
+		if d.Name() != info.Name() || d.IsDir() != info.IsDir() || d.Type() != info.Mode().Type() {
+			t.Errorf("FileInfoToDirEntry(%s) = %q, %v, %v; want %q, %v, %v", name,
+				d.Name(), d.IsDir(), d.Type(), info.Name(), info.IsDir(), info.Mode().Type())
+		}
+		if i, err := d.Info(); err != nil || i != info {
+			t.Errorf("FileInfoToDirEntry(%s).Info() = %v, %v; want original FileInfo", name, i, err)
+		}
+	}
+	if FileInfoToDirEntry(nil) != nil {
+		t.Error("FileInfoToDirEntry(nil) != nil")
+	}
+}
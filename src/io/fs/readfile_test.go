@@ -0,0 +1,46 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	. "io/fs"
+	"testing"
+)
+
+func TestReadFile(t *testing.T) {
+	// Test that ReadFile uses the method when present.
+	data, err := ReadFile(testFS, "g/h.txt")
+	if err != nil {
+		t.Fatalf("ReadFile(MapFS): %v", err)
+	}
+	if string(data) != "hello, world\n" {
+		t.Fatalf("ReadFile(MapFS) = %q, want %q", data, "hello, world\n")
+	}
+
+	// Test that ReadFile uses Open when the method is not present.
+	data, err = ReadFile(openOnly{testFS}, "g/h.txt")
+	if err != nil {
+		t.Fatalf("ReadFile(openOnly): %v", err)
+	}
+	if string(data) != "hello, world\n" {
+		t.Fatalf("ReadFile(openOnly) = %q, want %q", data, "hello, world\n")
+	}
+
+	if _, err := ReadFile(openOnly{testFS}, "missing"); err == nil {
+		t.Error("ReadFile(missing) succeeded, want error")
+	}
+}
+
+func TestStat(t *testing.T) {
+	for _, fsys := range []FS{testFS, openOnly{testFS}} {
+		info, err := Stat(fsys, "g/h.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Name() != "h.txt" || info.Size() != int64(len("hello, world\n")) || info.Mode() != 0644 {
+			t.Errorf("Stat(g/h.txt) = %q, %d, %v", info.Name(), info.Size(), info.Mode())
+		}
+	}
+}
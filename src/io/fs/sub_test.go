@@ -0,0 +1,72 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	"errors"
+	. "io/fs"
+	"reflect"
+	"testing"
+)
+
+func TestSub(t *testing.T) {
+	check := func(desc string, sub FS, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Sub: %v", err)
+		}
+
+		data, err := ReadFile(sub, "c")
+		if err != nil {
+			t.Fatalf("%s: ReadFile: %v", desc, err)
+		}
+		if string(data) != "c" {
+			t.Fatalf("%s: ReadFile = %q, want %q", desc, data, "c")
+		}
+
+		dirs, err := ReadDir(sub, "d")
+		if err != nil {
+			t.Fatalf("%s: ReadDir: %v", desc, err)
+		}
+		if len(dirs) != 2 || dirs[0].Name() != "e" || dirs[1].Name() != "f" {
+			t.Fatalf("%s: ReadDir = %v, want [e f]", desc, dirs)
+		}
+
+		matches, err := Glob(sub, "d/*")
+		if err != nil {
+			t.Fatalf("%s: Glob: %v", desc, err)
+		}
+		if want := []string{"d/e", "d/f"}; !reflect.DeepEqual(matches, want) {
+			t.Fatalf("%s: Glob = %q, want %q", desc, matches, want)
+		}
+
+		_, err = sub.Open("nonexist")
+		if err == nil {
+			t.Fatalf("%s: Open(nonexist): succeeded", desc)
+		}
+		var pe *PathError
+		if !errors.As(err, &pe) {
+			t.Fatalf("%s: Open(nonexist): error is %T, want *PathError", desc, err)
+		}
+		if pe.Path != "nonexist" {
+			t.Fatalf("%s: Open(nonexist): err.Path = %q, want %q", desc, pe.Path, "nonexist")
+		}
+	}
+
+	// Test that Sub uses the generic implementation.
+	sub, err := Sub(openOnly{testFS}, "b")
+	check("openOnly", sub, err)
+
+	// Test that Sub of "." returns the file system unchanged.
+	if sub, err := Sub(testFS, "."); err != nil || !reflect.DeepEqual(sub, FS(testFS)) {
+		t.Errorf("Sub(.) = %v, %v; want original FS", sub, err)
+	}
+
+	for _, dir := range []string{"/b", "b/", "../b"} {
+		if _, err := Sub(testFS, dir); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Sub(%q) = %v, want ErrInvalid", dir, err)
+		}
+	}
+}
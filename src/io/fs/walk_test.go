@@ -0,0 +1,101 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	"errors"
+	. "io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// openOnly hides all but the Open method of an FS,
+// so that the package-level helpers use their generic implementations.
+type openOnly struct{ FS }
+
+var testFS = fstest.MapFS{
+	"a":       {Data: []byte("a"), Mode: 0644},
+	"b/c":     {Data: []byte("c"), Mode: 0644},
+	"b/d/e":   {Data: []byte("e"), Mode: 0644},
+	"b/d/f":   {Data: []byte("f"), Mode: 0644},
+	"g/h.txt": {Data: []byte("hello, world\n"), Mode: 0644},
+	"i":       {Mode: ModeDir | 0755},
+}
+
+func TestWalkDir(t *testing.T) {
+	var visited []string
+	err := WalkDir(openOnly{testFS}, ".", func(path string, d DirEntry, err error) error {
+		if err != nil {
+			t.Errorf("walk %s: %v", path, err)
+			return err
+		}
+		if d.IsDir() {
+			path += "/"
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"./", "a", "b/", "b/c", "b/d/", "b/d/e", "b/d/f", "g/", "g/h.txt", "i/"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("visited %q\nwant %q", visited, want)
+	}
+}
+
+func TestWalkDirSkip(t *testing.T) {
+	var visited []string
+	err := WalkDir(testFS, ".", func(path string, d DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		switch path {
+		case "b/d":
+			return SkipDir
+		case "b/c":
+			// Skips the rest of b.
+			return SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".", "a", "b", "b/c", "g", "g/h.txt", "i"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("visited %q\nwant %q", visited, want)
+	}
+}
+
+func TestWalkDirError(t *testing.T) {
+	errStop := errors.New("stop")
+	n := 0
+	err := WalkDir(testFS, ".", func(path string, d DirEntry, err error) error {
+		n++
+		if path == "b/c" {
+			return errStop
+		}
+		return err
+	})
+	if err != errStop {
+		t.Errorf("WalkDir = %v, want %v", err, errStop)
+	}
+	if n != 4 {
+		t.Errorf("WalkDir visited %d paths, want 4", n)
+	}
+
+	err = WalkDir(testFS, "missing", func(path string, d DirEntry, err error) error {
+		if d != nil {
+			t.Errorf("walk %s: non-nil DirEntry for missing root", path)
+		}
+		return err
+	})
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("WalkDir(missing) = %v, want ErrNotExist", err)
+	}
+}
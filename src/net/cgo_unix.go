@@ -109,7 +109,7 @@ func cgoLookupServicePort(hints *C.struct_addrinfo, network, service string) (po
 		default:
 			err = addrinfoErrno(gerrno)
 		}
-		return 0, &DNSError{Err: err.Error(), Name: network + "/" + service}
+		return 0, &DNSError{UnwrapErr: err, Err: err.Error(), Name: network + "/" + service}
 	}
 	defer C.freeaddrinfo(res)
 
@@ -170,7 +170,7 @@ func cgoLookupIPCNAME(network, name string) (addrs []IPAddr, cname string, err e
 		default:
 			err = addrinfoErrno(gerrno)
 		}
-		return nil, "", &DNSError{Err: err.Error(), Name: name}
+		return nil, "", &DNSError{UnwrapErr: err, Err: err.Error(), Name: name}
 	}
 	defer C.freeaddrinfo(res)
 
@@ -299,7 +299,7 @@ func cgoLookupAddrPTR(addr string, sa *C.struct_sockaddr, salen C.socklen_t) (na
 		default:
 			err = addrinfoErrno(gerrno)
 		}
-		return nil, &DNSError{Err: err.Error(), Name: addr}
+		return nil, &DNSError{UnwrapErr: err, Err: err.Error(), Name: addr}
 	}
 	for i := 0; i < len(b); i++ {
 		if b[i] == 0 {
@@ -244,9 +244,10 @@ func (r *Resolver) tryOneName(ctx context.Context, cfg *dnsConfig, name string,
 			p, h, err := r.exchange(ctx, server, q, cfg.timeout)
 			if err != nil {
 				dnsErr := &DNSError{
-					Err:    err.Error(),
-					Name:   name,
-					Server: server,
+					UnwrapErr: err,
+					Err:       err.Error(),
+					Name:      name,
+					Server:    server,
 				}
 				if nerr, ok := err.(Error); ok && nerr.Timeout() {
 					dnsErr.IsTimeout = true
@@ -1127,8 +1127,18 @@ func TestStrictErrorsLookupIP(t *testing.T) {
 			IsTemporary: true,
 		}
 	}
+	makeOpError := func() error {
+		return &DNSError{
+			UnwrapErr:   &OpError{Op: "write", Err: fmt.Errorf("socket on fire")},
+			Err:         "write: socket on fire",
+			Name:        name,
+			Server:      server,
+			IsTemporary: true,
+		}
+	}
 	makeTimeout := func() error {
 		return &DNSError{
+			UnwrapErr: poll.ErrTimeout,
 			Err:       poll.ErrTimeout.Error(),
 			Name:      name,
 			Server:    server,
@@ -1209,7 +1219,7 @@ func TestStrictErrorsLookupIP(t *testing.T) {
 				}
 				return resolveOK
 			},
-			wantStrictErr: makeTempError("write: socket on fire"),
+			wantStrictErr: makeOpError(),
 			wantIPs:       []string{ip6},
 		},
 		{
@@ -1384,6 +1394,7 @@ func TestStrictErrorsLookupTXT(t *testing.T) {
 		var wantRRs int
 		if strict {
 			wantErr = &DNSError{
+				UnwrapErr: poll.ErrTimeout,
 				Err:       poll.ErrTimeout.Error(),
 				Name:      name,
 				Server:    server,
@@ -8,6 +8,7 @@ package net
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"internal/poll"
 	"io"
@@ -799,3 +800,22 @@ func parseLookupPortError(nestedErr error) error {
 	}
 	return fmt.Errorf("unexpected type on 1st nested level: %T", nestedErr)
 }
+
+func TestErrorUnwrap(t *testing.T) {
+	operr := &OpError{Op: "dial", Net: "tcp", Err: io.EOF}
+	if !errors.Is(operr, io.EOF) {
+		t.Errorf("errors.Is(%v, io.EOF) = false, want true", operr)
+	}
+	wrapped := &OpError{Op: "read", Net: "unix", Err: &os.SyscallError{Syscall: "open", Err: os.ErrNotExist}}
+	if !errors.Is(wrapped, os.ErrNotExist) {
+		t.Errorf("errors.Is(%v, os.ErrNotExist) = false, want true", wrapped)
+	}
+	dnserr := &DNSError{UnwrapErr: operr, Err: operr.Error(), Name: "golang.org"}
+	var got *OpError
+	if !errors.As(dnserr, &got) || got != operr {
+		t.Errorf("errors.As(%v, &operr) did not find the *OpError", dnserr)
+	}
+	if errors.Unwrap(&DNSError{Err: "no such host", Name: "golang.org"}) != nil {
+		t.Errorf("errors.Unwrap of DNSError without UnwrapErr is not nil")
+	}
+}
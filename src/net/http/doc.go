@@ -103,5 +103,14 @@ functions. Manually configuring HTTP/2 via the golang.org/x/net/http2
 package takes precedence over the net/http package's built-in HTTP/2
 support.
 
+The protocols used by a Server or Transport can also be chosen
+explicitly with their Protocols fields. This is the way to enable
+unencrypted HTTP/2 ("h2c"), which is never enabled by default:
+
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	srv := &http.Server{Protocols: &protocols}
+
 */
 package http
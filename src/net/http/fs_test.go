@@ -26,6 +26,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
@@ -396,6 +397,43 @@ func TestFileServerImplicitLeadingSlash(t *testing.T) {
 	}
 }
 
+func TestFileServerFS(t *testing.T) {
+	defer afterTest(t)
+	fsys := fstest.MapFS{
+		"foo.txt":     {Data: []byte("Hello world")},
+		"sub/bar.txt": {Data: []byte("bar")},
+	}
+	ts := httptest.NewServer(FileServer(FS(fsys)))
+	defer ts.Close()
+	get := func(suffix string) (int, string) {
+		res, err := Get(ts.URL + suffix)
+		if err != nil {
+			t.Fatalf("Get %s: %v", suffix, err)
+		}
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("ReadAll %s: %v", suffix, err)
+		}
+		res.Body.Close()
+		return res.StatusCode, string(b)
+	}
+	if _, s := get("/"); !strings.Contains(s, ">foo.txt<") || !strings.Contains(s, ">sub/<") {
+		t.Errorf("expected a directory listing with foo.txt and sub/, got %q", s)
+	}
+	if _, s := get("/foo.txt"); s != "Hello world" {
+		t.Errorf("expected %q, got %q", "Hello world", s)
+	}
+	if _, s := get("/sub/bar.txt"); s != "bar" {
+		t.Errorf("expected %q, got %q", "bar", s)
+	}
+	if code, _ := get("/missing.txt"); code != StatusNotFound {
+		t.Errorf("GET /missing.txt = %d; want %d", code, StatusNotFound)
+	}
+	if code, _ := get("/foo.txt/bar"); code != StatusNotFound {
+		t.Errorf("GET /foo.txt/bar = %d; want %d", code, StatusNotFound)
+	}
+}
+
 func TestDirJoin(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("skipping test on windows")
@@ -751,7 +751,7 @@ func (p *http2clientConnPool) getClientConn(req *Request, addr string, dialOnMis
 		// It gets its own connection.
 		http2traceGetConn(req, addr)
 		const singleUse = true
-		cc, err := p.t.dialClientConn(addr, singleUse)
+		cc, err := p.t.dialClientConn(req.Context(), addr, singleUse)
 		if err != nil {
 			return nil, err
 		}
@@ -772,27 +772,40 @@ func (p *http2clientConnPool) getClientConn(req *Request, addr string, dialOnMis
 		return nil, http2ErrNoCachedConn
 	}
 	http2traceGetConn(req, addr)
-	call := p.getStartDialLocked(addr)
+	call := p.getStartDialLocked(req.Context(), addr)
 	p.mu.Unlock()
 	<-call.done
+	if http2shouldRetryDial(call, req) {
+		// The dial was started for another request, whose context
+		// ended. That is no reason to fail this one.
+		return p.getClientConn(req, addr, dialOnMiss)
+	}
 	return call.res, call.err
 }
 
+// shouldRetryDial reports whether req should dial again after call
+// failed because the context of the request that started it ended.
+func http2shouldRetryDial(call *http2dialCall, req *Request) bool {
+	return call.err != nil && call.ctx != req.Context() &&
+		call.ctx.Err() != nil && req.Context().Err() == nil
+}
+
 // dialCall is an in-flight Transport dial call to a host.
 type http2dialCall struct {
 	p    *http2clientConnPool
+	ctx  context.Context  // context of the request that started the dial
 	done chan struct{}    // closed when done
 	res  *http2ClientConn // valid after done is closed
 	err  error            // valid after done is closed
 }
 
 // requires p.mu is held.
-func (p *http2clientConnPool) getStartDialLocked(addr string) *http2dialCall {
+func (p *http2clientConnPool) getStartDialLocked(ctx context.Context, addr string) *http2dialCall {
 	if call, ok := p.dialing[addr]; ok {
 		// A dial is already in-flight. Don't start another.
 		return call
 	}
-	call := &http2dialCall{p: p, done: make(chan struct{})}
+	call := &http2dialCall{p: p, ctx: ctx, done: make(chan struct{})}
 	if p.dialing == nil {
 		p.dialing = make(map[string]*http2dialCall)
 	}
@@ -804,7 +817,7 @@ func (p *http2clientConnPool) getStartDialLocked(addr string) *http2dialCall {
 // run in its own goroutine.
 func (c *http2dialCall) dial(addr string) {
 	const singleUse = false // shared conn
-	c.res, c.err = c.p.t.dialClientConn(addr, singleUse)
+	c.res, c.err = c.p.t.dialClientConn(c.ctx, addr, singleUse)
 	close(c.done)
 
 	c.p.mu.Lock()
@@ -3401,6 +3414,8 @@ func (e *http2httpError) Temporary() bool { return true }
 
 var http2errTimeout error = &http2httpError{msg: "http2: timeout awaiting response headers", timeout: true}
 
+var http2errStreamDeadline error = &http2httpError{msg: "http2: stream deadline exceeded", timeout: true}
+
 type http2connectionStater interface {
 	ConnectionState() tls.ConnectionState
 }
@@ -3831,7 +3846,15 @@ func http2ConfigureServer(s *Server, conf *http2Server) error {
 		if http2testHookOnConn != nil {
 			http2testHookOnConn()
 		}
+		var ctx context.Context
+		type baseContexter interface {
+			BaseContext() context.Context
+		}
+		if bc, ok := h.(baseContexter); ok {
+			ctx = bc.BaseContext()
+		}
 		conf.ServeConn(c, &http2ServeConnOpts{
+			Context:    ctx,
 			Handler:    h,
 			BaseConfig: hs,
 		})
@@ -3842,6 +3865,10 @@ func http2ConfigureServer(s *Server, conf *http2Server) error {
 
 // ServeConnOpts are options for the Server.ServeConn method.
 type http2ServeConnOpts struct {
+	// Context is the base context to use.
+	// If nil, context.Background is used.
+	Context context.Context
+
 	// BaseConfig optionally sets the base configuration
 	// for values. If nil, defaults are used.
 	BaseConfig *Server
@@ -3852,6 +3879,13 @@ type http2ServeConnOpts struct {
 	Handler Handler
 }
 
+func (o *http2ServeConnOpts) context() context.Context {
+	if o != nil && o.Context != nil {
+		return o.Context
+	}
+	return context.Background()
+}
+
 func (o *http2ServeConnOpts) baseConfig() *Server {
 	if o != nil && o.BaseConfig != nil {
 		return o.BaseConfig
@@ -3997,7 +4031,7 @@ func (s *http2Server) ServeConn(c net.Conn, opts *http2ServeConnOpts) {
 }
 
 func http2serverConnBaseContext(c net.Conn, opts *http2ServeConnOpts) (ctx context.Context, cancel func()) {
-	ctx, cancel = context.WithCancel(context.Background())
+	ctx, cancel = context.WithCancel(opts.context())
 	ctx = context.WithValue(ctx, LocalAddrContextKey, c.LocalAddr())
 	if hs := opts.baseConfig(); hs != nil {
 		ctx = context.WithValue(ctx, ServerContextKey, hs)
@@ -4115,6 +4149,7 @@ type http2stream struct {
 	resetQueued      bool        // RST_STREAM queued for write; set by sc.resetStream
 	gotTrailerHeader bool        // HEADER frame for trailers was seen
 	wroteHeaders     bool        // whether we wrote headers (not status 100)
+	readDeadline     *time.Timer // nil if unused
 	writeDeadline    *time.Timer // nil if unused
 
 	trailer    Header // accumulated trailers
@@ -4408,6 +4443,8 @@ func (sc *http2serverConn) serve() {
 			switch v := msg.(type) {
 			case func(int):
 				v(loopNum) // for testing
+			case func(*http2serverConn):
+				v(sc)
 			case *http2serverMessage:
 				switch v {
 				case http2settingsTimerMsg:
@@ -5022,6 +5059,9 @@ func (sc *http2serverConn) closeStream(st *http2stream, err error) {
 		panic(fmt.Sprintf("invariant; can't close stream in state %v", st.state))
 	}
 	st.state = http2stateClosed
+	if st.readDeadline != nil {
+		st.readDeadline.Stop()
+	}
 	if st.writeDeadline != nil {
 		st.writeDeadline.Stop()
 	}
@@ -5272,6 +5312,14 @@ func (st *http2stream) copyTrailersToHandlerRequest() {
 	}
 }
 
+// onReadTimeout is run on its own goroutine (from time.AfterFunc)
+// when the stream's read deadline has passed.
+func (st *http2stream) onReadTimeout() {
+	if st.body != nil {
+		st.body.CloseWithError(http2errStreamDeadline)
+	}
+}
+
 // onWriteTimeout is run on its own goroutine (from time.AfterFunc)
 // when the stream's WriteTimeout has fired.
 func (st *http2stream) onWriteTimeout() {
@@ -6047,22 +6095,91 @@ func (rws *http2responseWriterState) promoteUndeclaredTrailers() {
 }
 
 func (w *http2responseWriter) Flush() {
+	// Ignore the error. The frame writer already knows.
+	w.FlushError()
+}
+
+func (w *http2responseWriter) FlushError() error {
 	rws := w.rws
 	if rws == nil {
 		panic("Header called after Handler finished")
 	}
+	var err error
 	if rws.bw.Buffered() > 0 {
-		if err := rws.bw.Flush(); err != nil {
-			// Ignore the error. The frame writer already knows.
-			return
-		}
+		err = rws.bw.Flush()
 	} else {
 		// The bufio.Writer won't call chunkWriter.Write
 		// (writeChunk with zero bytes, so we have to do it
 		// ourselves to force the HTTP response header and/or
 		// final DATA frame (with END_STREAM) to be sent.
-		rws.writeChunk(nil)
+		_, err = rws.writeChunk(nil)
 	}
+	return err
+}
+
+func (w *http2responseWriter) SetReadDeadline(deadline time.Time) error {
+	st := w.rws.stream
+	if !deadline.IsZero() && deadline.Before(time.Now()) {
+		// If we're setting a deadline in the past, fail the
+		// body immediately so reads after SetReadDeadline
+		// returns will fail.
+		st.onReadTimeout()
+		return nil
+	}
+	w.rws.conn.sendServeMsg(func(sc *http2serverConn) {
+		if st.state == http2stateClosed {
+			return
+		}
+		if st.readDeadline != nil {
+			if !st.readDeadline.Stop() {
+				// Deadline already exceeded.
+				return
+			}
+		}
+		if deadline.IsZero() {
+			st.readDeadline = nil
+		} else if st.readDeadline == nil {
+			st.readDeadline = time.AfterFunc(time.Until(deadline), st.onReadTimeout)
+		} else {
+			st.readDeadline.Reset(time.Until(deadline))
+		}
+	})
+	return nil
+}
+
+func (w *http2responseWriter) SetWriteDeadline(deadline time.Time) error {
+	st := w.rws.stream
+	if !deadline.IsZero() && deadline.Before(time.Now()) {
+		// If we're setting a deadline in the past, reset the stream
+		// immediately so writes after SetWriteDeadline returns will fail.
+		st.onWriteTimeout()
+		return nil
+	}
+	w.rws.conn.sendServeMsg(func(sc *http2serverConn) {
+		if st.state == http2stateClosed {
+			return
+		}
+		if st.writeDeadline != nil {
+			if !st.writeDeadline.Stop() {
+				// Deadline already exceeded.
+				return
+			}
+		}
+		if deadline.IsZero() {
+			st.writeDeadline = nil
+		} else if st.writeDeadline == nil {
+			st.writeDeadline = time.AfterFunc(time.Until(deadline), st.onWriteTimeout)
+		} else {
+			st.writeDeadline.Reset(time.Until(deadline))
+		}
+	})
+	return nil
+}
+
+// EnableFullDuplex is a no-op: HTTP/2 handlers may always read the
+// request body while writing the response.
+func (w *http2responseWriter) EnableFullDuplex() error {
+	return nil
 }
 
 func (w *http2responseWriter) CloseNotify() <-chan bool {
@@ -6488,6 +6605,11 @@ type http2Transport struct {
 	// it will be used to set http.Response.TLS.
 	DialTLS func(network, addr string, cfg *tls.Config) (net.Conn, error)
 
+	// DialTLSContext is like DialTLS but also receives the context of
+	// the request that caused the dial. If both are set,
+	// DialTLSContext takes priority.
+	DialTLSContext func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error)
+
 	// TLSClientConfig specifies the TLS configuration to use with
 	// tls.Client. If nil, the default configuration is used.
 	TLSClientConfig *tls.Config
@@ -6953,12 +7075,12 @@ func http2canRetryError(err error) bool {
 	return false
 }
 
-func (t *http2Transport) dialClientConn(addr string, singleUse bool) (*http2ClientConn, error) {
+func (t *http2Transport) dialClientConn(ctx context.Context, addr string, singleUse bool) (*http2ClientConn, error) {
 	host, _, err := net.SplitHostPort(addr)
 	if err != nil {
 		return nil, err
 	}
-	tconn, err := t.dialTLS()("tcp", addr, t.newTLSConfig(host))
+	tconn, err := t.dialTLS(ctx)("tcp", addr, t.newTLSConfig(host))
 	if err != nil {
 		return nil, err
 	}
@@ -6979,7 +7101,12 @@ func (t *http2Transport) newTLSConfig(host string) *tls.Config {
 	return cfg
 }
 
-func (t *http2Transport) dialTLS() func(string, string, *tls.Config) (net.Conn, error) {
+func (t *http2Transport) dialTLS(ctx context.Context) func(string, string, *tls.Config) (net.Conn, error) {
+	if t.DialTLSContext != nil {
+		return func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return t.DialTLSContext(ctx, network, addr, cfg)
+		}
+	}
 	if t.DialTLS != nil {
 		return t.DialTLS
 	}
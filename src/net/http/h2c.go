@@ -0,0 +1,207 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Unencrypted HTTP/2 ("h2c") support for the Server, as described in
+// RFC 7540, sections 3.2 and 3.4. The HTTP/2 server in h2_bundle.go
+// only speaks HTTP/2 from the first byte of a connection, so the
+// code here hands it a net.Conn that replays whatever the HTTP/1
+// server has already consumed.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"internal/x/net/http/httpguts"
+	"internal/x/net/http2/hpack"
+)
+
+// serveH2CPriorKnowledge serves HTTP/2 on c, whose HTTP/1 reader has
+// just parsed the "PRI * HTTP/2.0" request that starts the HTTP/2
+// connection preface.
+func (c *conn) serveH2CPriorKnowledge(ctx context.Context) {
+	// The rest of the preface follows the pseudo-request.
+	const rest = "SM\r\n\r\n"
+	buf := make([]byte, len(rest))
+	if _, err := io.ReadFull(c.bufr, buf); err != nil || string(buf) != rest {
+		return
+	}
+	c.serveH2C(ctx, strings.NewReader(http2ClientPreface))
+}
+
+// h2cUpgradeSettings reports whether req asks to upgrade the connection
+// to unencrypted HTTP/2, and if so returns the SETTINGS frame payload
+// carried in its HTTP2-Settings header.
+func h2cUpgradeSettings(req *Request) (settings []byte, ok bool) {
+	if req.ProtoMajor != 1 || req.ProtoMinor != 1 || req.Method == "CONNECT" {
+		return nil, false
+	}
+	// The HTTP/1 request becomes stream 1 of the new connection, and
+	// we only forward its header. Serve requests with a body using
+	// HTTP/1.1 instead, which RFC 7540 permits.
+	if req.ContentLength != 0 {
+		return nil, false
+	}
+	if !httpguts.HeaderValuesContainsToken(req.Header["Upgrade"], "h2c") ||
+		!httpguts.HeaderValuesContainsToken(req.Header["Connection"], "Upgrade") ||
+		!httpguts.HeaderValuesContainsToken(req.Header["Connection"], "HTTP2-Settings") {
+		return nil, false
+	}
+	vv := req.Header["Http2-Settings"]
+	if len(vv) != 1 {
+		return nil, false
+	}
+	settings, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(vv[0], "="))
+	if err != nil || len(settings)%6 != 0 {
+		return nil, false
+	}
+	return settings, true
+}
+
+// serveH2CUpgrade switches c to HTTP/2 in response to the upgrade
+// request req, which becomes stream 1 of the new connection.
+func (c *conn) serveH2CUpgrade(ctx context.Context, req *Request, settings []byte) {
+	c.bufw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: h2c\r\n\r\n")
+	if err := c.bufw.Flush(); err != nil {
+		return
+	}
+
+	// The client now sends its connection preface, which starts with
+	// a SETTINGS frame. Merge the settings from the HTTP2-Settings
+	// header into that frame, rather than sending the server a frame
+	// of its own, so that the client gets exactly the one
+	// acknowledgement it expects.
+	buf := make([]byte, len(http2ClientPreface)+http2frameHeaderLen)
+	if _, err := io.ReadFull(c.bufr, buf); err != nil || string(buf[:len(http2ClientPreface)]) != http2ClientPreface {
+		return
+	}
+	hdr := buf[len(http2ClientPreface):]
+	n := int(hdr[0])<<16 | int(hdr[1])<<8 | int(hdr[2])
+	if http2FrameType(hdr[3]) != http2FrameSettings || http2Flags(hdr[4]).Has(http2FlagSettingsAck) ||
+		n%6 != 0 || n > http2initialMaxFrameSize {
+		return
+	}
+	clientSettings := make([]byte, n)
+	if _, err := io.ReadFull(c.bufr, clientSettings); err != nil {
+		return
+	}
+
+	// Follow the SETTINGS frame with the request header on stream 1.
+	var frames bytes.Buffer
+	fr := http2NewFramer(&frames, nil)
+	if err := fr.WriteRawFrame(http2FrameSettings, 0, 0, h2cMergeSettings(settings, clientSettings)); err != nil {
+		return
+	}
+	block := h2cHeaderBlock(req)
+	for first := true; first || len(block) > 0; first = false {
+		frag := block
+		if len(frag) > http2initialMaxFrameSize {
+			frag = frag[:http2initialMaxFrameSize]
+		}
+		block = block[len(frag):]
+		var err error
+		if first {
+			err = fr.WriteHeaders(http2HeadersFrameParam{
+				StreamID:      1,
+				BlockFragment: frag,
+				EndStream:     true,
+				EndHeaders:    len(block) == 0,
+			})
+		} else {
+			err = fr.WriteContinuation(1, len(block) == 0, frag)
+		}
+		if err != nil {
+			return
+		}
+	}
+	c.serveH2C(ctx, io.MultiReader(strings.NewReader(http2ClientPreface), &frames))
+}
+
+// h2cMergeSettings returns a SETTINGS frame payload holding the
+// settings from an HTTP2-Settings header that the client does not
+// repeat in its own SETTINGS frame, followed by the client's settings.
+// Each setting is 6 bytes: a 2-byte identifier and a 4-byte value.
+func h2cMergeSettings(upgrade, client []byte) []byte {
+	merged := make([]byte, 0, len(upgrade)+len(client))
+	for i := 0; i < len(upgrade); i += 6 {
+		repeated := false
+		for j := 0; j < len(client); j += 6 {
+			if upgrade[i] == client[j] && upgrade[i+1] == client[j+1] {
+				repeated = true
+				break
+			}
+		}
+		if !repeated {
+			merged = append(merged, upgrade[i:i+6]...)
+		}
+	}
+	return append(merged, client...)
+}
+
+// h2cHeaderBlock returns the HPACK-encoded HTTP/2 form of req's header.
+func h2cHeaderBlock(req *Request) []byte {
+	var buf bytes.Buffer
+	enc := hpack.NewEncoder(&buf)
+	writeField := func(name, value string) {
+		enc.WriteField(hpack.HeaderField{Name: name, Value: value})
+	}
+	writeField(":method", req.Method)
+	writeField(":scheme", "http")
+	writeField(":authority", req.Host)
+	writeField(":path", req.URL.RequestURI())
+	for k, vv := range req.Header {
+		lk := strings.ToLower(k)
+		switch lk {
+		case "connection", "host", "http2-settings", "keep-alive",
+			"proxy-connection", "transfer-encoding", "upgrade":
+			// Connection-specific; not allowed in HTTP/2.
+			continue
+		case "te":
+			// HTTP/2 only allows "TE: trailers".
+			if httpguts.HeaderValuesContainsToken(vv, "trailers") {
+				writeField("te", "trailers")
+			}
+			continue
+		}
+		for _, v := range vv {
+			writeField(lk, v)
+		}
+	}
+	return buf.Bytes()
+}
+
+// serveH2C hands c over to the HTTP/2 server, which derives its
+// request contexts from ctx. The server reads prefix, then anything
+// c's HTTP/1 reader has buffered, then the rest of the connection.
+func (c *conn) serveH2C(ctx context.Context, prefix io.Reader) {
+	buffered, _ := c.bufr.Peek(c.bufr.Buffered())
+	buffered = append([]byte(nil), buffered...)
+
+	// The HTTP/2 server manages its own timeouts.
+	c.rwc.SetReadDeadline(time.Time{})
+
+	h2c := &h2cConn{
+		Conn: c.rwc,
+		r:    io.MultiReader(prefix, bytes.NewReader(buffered), c.rwc),
+	}
+	c.server.h2c.ServeConn(h2c, &http2ServeConnOpts{
+		Context:    ctx,
+		Handler:    serverHandler{c.server},
+		BaseConfig: c.server,
+	})
+}
+
+// h2cConn is a net.Conn whose reads come from r.
+type h2cConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *h2cConn) Read(p []byte) (int, error) { return c.r.Read(p) }
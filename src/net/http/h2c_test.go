@@ -0,0 +1,374 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	. "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newH2CServer(t *testing.T, http1 bool, h Handler) *httptest.Server {
+	ts := httptest.NewUnstartedServer(h)
+	var p Protocols
+	p.SetHTTP1(http1)
+	p.SetUnencryptedHTTP2(true)
+	ts.Config.Protocols = &p
+	ts.Start()
+	return ts
+}
+
+func newH2CTransport() *Transport {
+	var p Protocols
+	p.SetUnencryptedHTTP2(true)
+	return &Transport{Protocols: &p}
+}
+
+var h2cProtoHandler = HandlerFunc(func(w ResponseWriter, r *Request) {
+	fmt.Fprintf(w, "%s %s %s", r.Proto, r.Method, r.URL.Path)
+})
+
+func TestProtocolsString(t *testing.T) {
+	var p Protocols
+	if got, want := p.String(), "{}"; got != want {
+		t.Errorf("zero Protocols.String() = %q, want %q", got, want)
+	}
+	p.SetHTTP1(true)
+	p.SetUnencryptedHTTP2(true)
+	if got, want := p.String(), "{HTTP1,UnencryptedHTTP2}"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	p.SetHTTP1(false)
+	p.SetHTTP2(true)
+	if p.HTTP1() || !p.HTTP2() || !p.UnencryptedHTTP2() {
+		t.Errorf("Protocols = %v, want {HTTP2,UnencryptedHTTP2}", p)
+	}
+}
+
+func TestH2CPriorKnowledge(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	ts := newH2CServer(t, true, h2cProtoHandler)
+	defer ts.Close()
+
+	tr := newH2CTransport()
+	defer tr.CloseIdleConnections()
+	c := &Client{Transport: tr}
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(ts.URL + "/foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.ProtoMajor != 2 {
+			t.Errorf("response Proto = %q, want HTTP/2.0", res.Proto)
+		}
+		if got, want := string(body), "HTTP/2.0 GET /foo"; got != want {
+			t.Errorf("body = %q, want %q", got, want)
+		}
+	}
+
+	// HTTP/1 clients are still served.
+	res, err := Get(ts.URL + "/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if got, want := string(body), "HTTP/1.1 GET /bar"; got != want {
+		t.Errorf("HTTP/1 body = %q, want %q", got, want)
+	}
+}
+
+func TestH2CTransportDialUsesRequestContext(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	tr := newH2CTransport()
+	defer tr.CloseIdleConnections()
+	dialErr := make(chan error, 1)
+	tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		<-ctx.Done()
+		dialErr <- ctx.Err()
+		return nil, ctx.Err()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := NewRequest("GET", "http://example.com/", nil)
+	req = req.WithContext(ctx)
+	go cancel()
+	if _, err := (&Client{Transport: tr}).Do(req); err == nil {
+		t.Fatal("request with canceled context succeeded")
+	}
+	select {
+	case err := <-dialErr:
+		if err != context.Canceled {
+			t.Errorf("dial context error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("dial did not see the request's context")
+	}
+}
+
+func TestTransportProtocolsWithoutHTTP1(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	h2ts := httptest.NewUnstartedServer(h2cProtoHandler)
+	ExportHttp2ConfigureServer(h2ts.Config, nil)
+	offered := make(chan []string, 1)
+	h2ts.Config.TLSConfig.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		offered <- hello.SupportedProtos
+		return nil, nil
+	}
+	h2ts.TLS = h2ts.Config.TLSConfig
+	h2ts.StartTLS()
+	defer h2ts.Close()
+	h1ts := httptest.NewTLSServer(h2cProtoHandler)
+	defer h1ts.Close()
+
+	var p Protocols
+	p.SetHTTP2(true)
+	tr := &Transport{
+		Protocols:       &p,
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	defer tr.CloseIdleConnections()
+	c := &Client{Transport: tr}
+
+	res, err := c.Get(h2ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.ProtoMajor != 2 {
+		t.Errorf("response Proto = %q, want HTTP/2.0", res.Proto)
+	}
+	if got := <-offered; len(got) != 1 || got[0] != "h2" {
+		t.Errorf("client offered protocols %q, want [h2]", got)
+	}
+
+	if res, err := c.Get(h1ts.URL); err == nil {
+		res.Body.Close()
+		t.Errorf("request to HTTP/1-only server succeeded over %v", res.Proto)
+	}
+	if res, err := c.Get("http://" + h2ts.Listener.Addr().String()); err == nil {
+		res.Body.Close()
+		t.Errorf("http:// request succeeded over %v", res.Proto)
+	}
+}
+
+func TestH2COnlyServerRejectsHTTP1(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	ts := newH2CServer(t, false, h2cProtoHandler)
+	defer ts.Close()
+
+	res, err := Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusHTTPVersionNotSupported {
+		t.Errorf("HTTP/1 status = %v, want %v", res.StatusCode, StatusHTTPVersionNotSupported)
+	}
+
+	tr := newH2CTransport()
+	defer tr.CloseIdleConnections()
+	res, err = (&Client{Transport: tr}).Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusOK || res.ProtoMajor != 2 {
+		t.Errorf("h2c response = %v %v, want 200 over HTTP/2", res.Proto, res.Status)
+	}
+}
+
+func TestServerWithoutH2CIgnoresUpgrade(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	ts := httptest.NewServer(h2cProtoHandler)
+	defer ts.Close()
+
+	req, _ := NewRequest("GET", ts.URL+"/x", nil)
+	req.Header.Set("Connection", "Upgrade, HTTP2-Settings")
+	req.Header.Set("Upgrade", "h2c")
+	req.Header.Set("HTTP2-Settings", "")
+	res, err := DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if got, want := string(body), "HTTP/1.1 GET /x"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+// h2cFrame is a raw HTTP/2 frame read by readH2CFrame.
+type h2cFrame struct {
+	typ, flags byte
+	stream     uint32
+	payload    []byte
+}
+
+func readH2CFrame(r io.Reader) (h2cFrame, error) {
+	var hdr [9]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return h2cFrame{}, err
+	}
+	f := h2cFrame{
+		typ:     hdr[3],
+		flags:   hdr[4],
+		stream:  binary.BigEndian.Uint32(hdr[5:]) & (1<<31 - 1),
+		payload: make([]byte, int(hdr[0])<<16|int(hdr[1])<<8|int(hdr[2])),
+	}
+	_, err := io.ReadFull(r, f.payload)
+	return f, err
+}
+
+func TestH2CUpgrade(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	ts := newH2CServer(t, true, h2cProtoHandler)
+	defer ts.Close()
+
+	c, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(10 * time.Second))
+
+	// The HTTP2-Settings header holds SETTINGS_MAX_CONCURRENT_STREAMS=100.
+	io.WriteString(c, "GET /up HTTP/1.1\r\n"+
+		"Host: example.com\r\n"+
+		"Connection: Upgrade, HTTP2-Settings\r\n"+
+		"Upgrade: h2c\r\n"+
+		"HTTP2-Settings: AAMAAABk\r\n\r\n")
+	br := bufio.NewReader(c)
+	res, err := ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != StatusSwitchingProtocols || res.Header.Get("Upgrade") != "h2c" {
+		t.Fatalf("upgrade response = %v %v", res.Status, res.Header)
+	}
+
+	// Send the connection preface and an empty SETTINGS frame.
+	io.WriteString(c, "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n\x00\x00\x00\x04\x00\x00\x00\x00\x00")
+
+	const (
+		frameData     = 0x0
+		frameSettings = 0x4
+		framePing     = 0x6
+		frameGoAway   = 0x7
+		flagEndStream = 0x1
+		flagAck       = 0x1
+	)
+	var body strings.Builder
+	acks := 0
+	sentPing := false
+	for {
+		f, err := readH2CFrame(br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case f.typ == frameSettings && f.flags&flagAck != 0:
+			acks++
+		case f.typ == frameGoAway:
+			t.Fatalf("got GOAWAY frame: %q", f.payload)
+		case f.typ == frameData && f.stream == 1:
+			body.Write(f.payload)
+		}
+		if f.typ == frameData && f.stream == 1 && f.flags&flagEndStream != 0 {
+			// The server answers frames in order, so once it
+			// acknowledges this PING it has answered both SETTINGS.
+			io.WriteString(c, "\x00\x00\x08\x06\x00\x00\x00\x00\x00ping1234")
+			sentPing = true
+		}
+		if sentPing && f.typ == framePing && f.flags&flagAck != 0 {
+			break
+		}
+	}
+	if got, want := body.String(), "HTTP/2.0 GET /up"; got != want {
+		t.Errorf("stream 1 body = %q, want %q", got, want)
+	}
+	// The server must acknowledge the SETTINGS frame we sent, and
+	// nothing else: the HTTP2-Settings header is not a frame.
+	if acks != 1 {
+		t.Errorf("got %d SETTINGS acknowledgements, want 1", acks)
+	}
+}
+
+func TestH2CUpgradeWithBodyUsesHTTP1(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	ts := newH2CServer(t, true, HandlerFunc(func(w ResponseWriter, r *Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s", r.Proto, b)
+	}))
+	defer ts.Close()
+
+	req, _ := NewRequest("POST", ts.URL, strings.NewReader("hello"))
+	req.Header.Set("Connection", "Upgrade, HTTP2-Settings")
+	req.Header.Set("Upgrade", "h2c")
+	req.Header.Set("HTTP2-Settings", "AAMAAABk")
+	res, err := DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if got, want := string(body), "HTTP/1.1 hello"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestH2CServerContexts(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	type connKey struct{}
+	ch := make(chan interface{}, 1)
+	ts := httptest.NewUnstartedServer(HandlerFunc(func(w ResponseWriter, r *Request) {
+		ch <- r.Context().Value(connKey{})
+	}))
+	var p Protocols
+	p.SetHTTP1(true)
+	p.SetUnencryptedHTTP2(true)
+	ts.Config.Protocols = &p
+	ts.Config.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		return context.WithValue(ctx, connKey{}, "conn")
+	}
+	ts.Start()
+	defer ts.Close()
+
+	tr := newH2CTransport()
+	defer tr.CloseIdleConnections()
+	res, err := (&Client{Transport: tr}).Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.ProtoMajor != 2 {
+		t.Errorf("response Proto = %q, want HTTP/2.0", res.Proto)
+	}
+	if got, want := <-ch, "conn"; got != want {
+		t.Errorf("conn context key = %#v; want %q", got, want)
+	}
+}
@@ -78,7 +78,11 @@ func (h Header) write(w io.Writer, trace *httptrace.ClientTrace) error {
 	return h.writeSubset(w, nil, trace)
 }
 
-func (h Header) clone() Header {
+// Clone returns a copy of h or nil if h is nil.
+func (h Header) Clone() Header {
+	if h == nil {
+		return nil
+	}
 	h2 := make(Header, len(h))
 	for k, vv := range h {
 		vv2 := make([]string, len(vv))
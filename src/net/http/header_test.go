@@ -211,3 +211,19 @@ func TestHeaderWriteSubsetAllocs(t *testing.T) {
 		t.Errorf("allocs = %g; want 0", n)
 	}
 }
+
+func TestHeaderClone(t *testing.T) {
+	if h := Header(nil).Clone(); h != nil {
+		t.Errorf("nil Header.Clone() = %v, want nil", h)
+	}
+	h := Header{"A": {"1", "2"}, "B": {}}
+	h2 := h.Clone()
+	h2["A"][0] = "changed"
+	h2.Add("C", "3")
+	if h["A"][0] != "1" || len(h) != 2 {
+		t.Errorf("modifying clone changed original: %v", h)
+	}
+	if vv, ok := h2["B"]; !ok || len(vv) != 0 {
+		t.Errorf("clone lost empty value list: %v", h2)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRoutingFirstSegment(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want []string
+	}{
+		{"/a/b/c", []string{"a", "b", "c"}},
+		{"/a/b/", []string{"a", "b", "/"}},
+		{"/", []string{"/"}},
+		{"/a/%62/c", []string{"a", "b", "c"}},
+		{"/a%2Fb%2fc", []string{"a/b/c"}},
+	} {
+		var got []string
+		rest := test.in
+		for len(rest) > 0 {
+			var seg string
+			seg, rest = firstSegment(rest)
+			got = append(got, seg)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func getTestTree() *routingNode {
+	root := &routingNode{}
+	for _, p := range []string{
+		"/a",
+		"/a/b",
+		"/a/{x}",
+		"/g/h/i",
+		"/g/{x}/j",
+		"/a/b/{x...}",
+		"/a/b/{y}",
+		"/a/b/{$}",
+		"GET /",
+		"POST /",
+		"GET /g/{x}/j",
+		"/h/",
+		"/i/{$}",
+		"HEAD /k",
+		"DELETE /k",
+		"HEAD example.com/",
+		"example.com/a",
+	} {
+		root.addPattern(parseTestPattern(p), nil)
+	}
+	return root
+}
+
+func TestRoutingMatch(t *testing.T) {
+	type testCase struct {
+		method, host, path string
+		wantPat            string // "" for nil (no match)
+		wantMatches        []string
+	}
+
+	test := func(tree *routingNode, tests []testCase) {
+		t.Helper()
+		for _, tc := range tests {
+			gotNode, gotMatches := tree.match(tc.host, tc.method, tc.path)
+			got := ""
+			if gotNode != nil {
+				got = gotNode.pattern.String()
+			}
+			if got != tc.wantPat {
+				t.Errorf("%s, %s, %s: got %q, want %q", tc.host, tc.method, tc.path, got, tc.wantPat)
+			}
+			if !reflect.DeepEqual(gotMatches, tc.wantMatches) {
+				t.Errorf("%s, %s, %s: got matches %v, want %v", tc.host, tc.method, tc.path, gotMatches, tc.wantMatches)
+			}
+		}
+	}
+
+	test(getTestTree(), []testCase{
+		{"Get", "", "/a", "/a", nil},
+		{"Get", "", "/b", "", nil},
+		{"Get", "", "/a/b", "/a/b", nil},
+		{"Get", "", "/a/c", "/a/{x}", []string{"c"}},
+		{"Get", "", "/a/b/", "/a/b/{$}", nil},
+		{"Get", "", "/a/b/c", "/a/b/{y}", []string{"c"}},
+		{"Get", "", "/a/b/c/d", "/a/b/{x...}", []string{"c/d"}},
+		{"Get", "", "/g/h/i", "/g/h/i", nil},
+		{"Get", "", "/g/h/j", "/g/{x}/j", []string{"h"}},
+		{"Get", "", "/h/", "/h/", nil},
+		{"Get", "", "/h/x/y", "/h/", nil},
+		{"Get", "", "/i/", "/i/{$}", nil},
+		{"Get", "", "/i/x", "", nil},
+		{"GET", "", "/", "GET /", nil},
+		{"GET", "", "/x", "GET /", nil},
+		{"GET", "", "/g/h/j", "GET /g/{x}/j", []string{"h"}},
+		{"HEAD", "", "/k", "HEAD /k", nil},
+		{"HEAD", "", "/x", "GET /", nil},
+		{"DELETE", "", "/k", "DELETE /k", nil},
+		{"PUT", "", "/k", "", nil},
+		{"HEAD", "example.com", "/", "HEAD example.com/", nil},
+		{"GET", "example.com", "/a", "example.com/a", nil},
+		{"GET", "example.com", "/x", "GET /", nil},
+		{"Get", "other.com", "/a", "/a", nil},
+		{"GET", "other.com", "/a", "GET /", nil},
+	})
+
+	// A pattern ending in {$} should only match URLs with a trailing slash.
+	pat1 := "/a/b/{$}"
+	test(buildTree(pat1), []testCase{
+		{"GET", "", "/a/b", "", nil},
+		{"GET", "", "/a/b/", pat1, nil},
+		{"GET", "", "/a/b/c", "", nil},
+		{"GET", "", "/a/b/c/d", "", nil},
+	})
+
+	// Escaped path segments are unescaped before matching.
+	pat2 := "/{x}/b"
+	test(buildTree(pat2, "/a%2Fb/{y...}"), []testCase{
+		{"GET", "", "/a%2Fb/c", "/a%2Fb/{y...}", []string{"c"}},
+		{"GET", "", "/a%2fb/c%2Fd", "/a%2Fb/{y...}", []string{"c/d"}},
+		{"GET", "", "/x%2Fy/b", pat2, []string{"x/y"}},
+	})
+}
+
+func buildTree(pats ...string) *routingNode {
+	root := &routingNode{}
+	for _, p := range pats {
+		root.addPattern(parseTestPattern(p), nil)
+	}
+	return root
+}
+
+// parseTestPattern is like mustParsePattern, but it panics
+// on error so that it can be used to build trees outside of tests.
+func parseTestPattern(s string) *pattern {
+	p, err := parsePattern(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func TestMatchingMethods(t *testing.T) {
+	hostTree := buildTree("GET a.com/", "PUT b.com/", "POST /foo/{x}")
+	for _, test := range []struct {
+		name       string
+		tree       *routingNode
+		host, path string
+		want       string
+	}{
+		{
+			"post",
+			buildTree("POST /"), "", "/foo",
+			"POST",
+		},
+		{
+			"get",
+			buildTree("GET /"), "", "/foo",
+			"GET,HEAD",
+		},
+		{
+			"host",
+			hostTree, "", "/foo",
+			"",
+		},
+		{
+			"host",
+			hostTree, "", "/foo/bar",
+			"POST",
+		},
+		{
+			"host2",
+			hostTree, "a.com", "/foo/bar",
+			"GET,HEAD,POST",
+		},
+		{
+			"host3",
+			hostTree, "b.com", "/bar",
+			"PUT",
+		},
+		{
+			// This case shouldn't come up because we only call matchingMethods
+			// when there was no match, but we include it for completeness.
+			"empty",
+			buildTree("/"), "", "/",
+			"",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ms := map[string]bool{}
+			test.tree.matchingMethods(test.host, test.path, ms)
+			var keys []string
+			for k := range ms {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			got := strings.Join(keys, ",")
+			if got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -396,6 +396,189 @@ func TestServeMuxHandleFuncWithNilHandler(t *testing.T) {
 	mux.HandleFunc("/", nil)
 }
 
+func TestServeMuxHandlePatterns(t *testing.T) {
+	setParallel(t)
+	mux := NewServeMux()
+	mux.Handle("GET /items/{id}", stringHandler("get item"))
+	mux.Handle("DELETE /items/{id}", stringHandler("delete item"))
+	mux.Handle("/items/{id}/edit", stringHandler("edit item"))
+	mux.Handle("/files/{path...}", stringHandler("file"))
+	mux.Handle("GET /{$}", stringHandler("index"))
+	mux.Handle("example.com/items/{id}", stringHandler("example.com item"))
+
+	for _, tt := range []struct {
+		method string
+		host   string
+		path   string
+		code   int
+		want   string // Result header, or Allow header for 405s
+	}{
+		{"GET", "a.com", "/items/1", 200, "get item"},
+		{"HEAD", "a.com", "/items/1", 200, "get item"},
+		{"DELETE", "a.com", "/items/1", 200, "delete item"},
+		{"POST", "a.com", "/items/1", 405, "DELETE, GET, HEAD"},
+		{"PUT", "a.com", "/items/1/edit", 200, "edit item"},
+		{"GET", "a.com", "/items/1/x", 404, ""},
+		{"GET", "a.com", "/files/a/b/c", 200, "file"},
+		{"GET", "a.com", "/files", 301, ""},
+		{"GET", "a.com", "/", 200, "index"},
+		{"POST", "a.com", "/", 405, "GET, HEAD"},
+		{"GET", "a.com", "/other", 404, ""},
+		{"POST", "example.com", "/items/1", 200, "example.com item"},
+	} {
+		r := &Request{
+			Method: tt.method,
+			Host:   tt.host,
+			URL:    &url.URL{Path: tt.path},
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, r)
+		if rr.Code != tt.code {
+			t.Errorf("%s %s%s: code = %d, want %d", tt.method, tt.host, tt.path, rr.Code, tt.code)
+			continue
+		}
+		var got string
+		switch tt.code {
+		case 200:
+			got = rr.HeaderMap.Get("Result")
+		case 405:
+			got = rr.HeaderMap.Get("Allow")
+		}
+		if got != tt.want {
+			t.Errorf("%s %s%s: got %q, want %q", tt.method, tt.host, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestServeMuxPathValue(t *testing.T) {
+	setParallel(t)
+	for _, test := range []struct {
+		pattern string
+		url     string
+		want    map[string]string
+	}{
+		{
+			"/{a}/is/{b}/{c...}",
+			"/now/is/the/time/for/all",
+			map[string]string{
+				"a": "now",
+				"b": "the",
+				"c": "time/for/all",
+				"d": "",
+			},
+		},
+		{
+			"/names/{name}/{other...}",
+			"/names/%2fjohn/address",
+			map[string]string{
+				"name":  "/john",
+				"other": "address",
+			},
+		},
+		{
+			"/names/{name}/{other...}",
+			"/names/john%2Fdoe/there/is%2F/more",
+			map[string]string{
+				"name":  "john/doe",
+				"other": "there/is//more",
+			},
+		},
+	} {
+		mux := NewServeMux()
+		mux.HandleFunc(test.pattern, func(w ResponseWriter, r *Request) {
+			for name, want := range test.want {
+				got := r.PathValue(name)
+				if got != want {
+					t.Errorf("%q, %q: got %q, want %q", test.pattern, name, got, want)
+				}
+			}
+		})
+		req, err := NewRequest("GET", "http://example.com"+test.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func TestSetPathValue(t *testing.T) {
+	setParallel(t)
+	mux := NewServeMux()
+	mux.HandleFunc("/a/{b}/c/{d...}", func(_ ResponseWriter, r *Request) {
+		kvs := map[string]string{
+			"b": "X",
+			"d": "Y",
+			"a": "Z",
+		}
+		for k, v := range kvs {
+			r.SetPathValue(k, v)
+		}
+		for k, w := range kvs {
+			if g := r.PathValue(k); g != w {
+				t.Errorf("got %q, want %q", g, w)
+			}
+		}
+	})
+	req, err := NewRequest("GET", "http://example.com/a/b/c/d/e", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestSetPathValueOnClone(t *testing.T) {
+	setParallel(t)
+	mux := NewServeMux()
+	mux.HandleFunc("/items/{id}", func(_ ResponseWriter, r *Request) {
+		r.SetPathValue("other", "x")
+		r2 := r.Clone(r.Context())
+		r2.SetPathValue("id", "changed")
+		r2.SetPathValue("other", "y")
+		if g, w := r.PathValue("id"), "1"; g != w {
+			t.Errorf("original id = %q, want %q", g, w)
+		}
+		if g, w := r.PathValue("other"), "x"; g != w {
+			t.Errorf("original other = %q, want %q", g, w)
+		}
+		if g, w := r2.PathValue("id"), "changed"; g != w {
+			t.Errorf("clone id = %q, want %q", g, w)
+		}
+		if g, w := r2.PathValue("other"), "y"; g != w {
+			t.Errorf("clone other = %q, want %q", g, w)
+		}
+	})
+	req, err := NewRequest("GET", "http://example.com/items/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestServeMuxHandleConflict(t *testing.T) {
+	setParallel(t)
+	for _, test := range []struct {
+		pat1, pat2 string
+	}{
+		{"/a", "/a"},
+		{"GET /", "/index.html"},
+		{"/a/{x}", "/{y}/b"},
+		{"/", "/{rest...}"},
+	} {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Errorf("%q then %q: expected panic", test.pat1, test.pat2)
+				} else if !strings.Contains(fmt.Sprint(err), "conflicts with pattern") {
+					t.Errorf("%q then %q: got panic %v, want conflict", test.pat1, test.pat2, err)
+				}
+			}()
+			mux := NewServeMux()
+			mux.Handle(test.pat1, stringHandler(""))
+			mux.Handle(test.pat2, stringHandler(""))
+		}()
+	}
+}
+
 var serveMuxTests2 = []struct {
 	method  string
 	host    string
@@ -6031,3 +6214,92 @@ func BenchmarkResponseStatusLine(b *testing.B) {
 		}
 	})
 }
+
+func TestServerContexts_h1(t *testing.T) { testServerContexts(t, h1Mode) }
+func TestServerContexts_h2(t *testing.T) { testServerContexts(t, h2Mode) }
+
+func testServerContexts(t *testing.T, h2 bool) {
+	setParallel(t)
+	defer afterTest(t)
+	type baseKey struct{}
+	type connKey struct{}
+	ch := make(chan context.Context, 1)
+	cst := newClientServerTest(t, h2, HandlerFunc(func(rw ResponseWriter, r *Request) {
+		ch <- r.Context()
+	}), func(ts *httptest.Server) {
+		ts.Config.BaseContext = func(ln net.Listener) context.Context {
+			if strings.Contains(reflect.TypeOf(ln).String(), "onceClose") {
+				t.Errorf("unexpected onceClose listener type %T", ln)
+			}
+			return context.WithValue(context.Background(), baseKey{}, "base")
+		}
+		ts.Config.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+			if got, want := ctx.Value(baseKey{}), "base"; got != want {
+				t.Errorf("in ConnContext, base context key = %#v; want %q", got, want)
+			}
+			return context.WithValue(ctx, connKey{}, "conn")
+		}
+	})
+	defer cst.close()
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	ctx := <-ch
+	if got, want := ctx.Value(baseKey{}), "base"; got != want {
+		t.Errorf("base context key = %#v; want %q", got, want)
+	}
+	if got, want := ctx.Value(connKey{}), "conn"; got != want {
+		t.Errorf("conn context key = %#v; want %q", got, want)
+	}
+	if _, ok := ctx.Value(ServerContextKey).(*Server); !ok {
+		t.Error("request context is missing ServerContextKey")
+	}
+}
+
+// ConnContext must start from the base context for each connection,
+// not from the context returned for an earlier one.
+func TestConnContextNotModifyingAllContexts(t *testing.T) {
+	setParallel(t)
+	defer afterTest(t)
+	type connKey struct{}
+	ts := httptest.NewUnstartedServer(HandlerFunc(func(rw ResponseWriter, r *Request) {
+		rw.Header().Set("Connection", "close")
+	}))
+	ts.Config.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		if got := ctx.Value(connKey{}); got != nil {
+			t.Errorf("in ConnContext, unexpected context key = %#v", got)
+		}
+		return context.WithValue(ctx, connKey{}, "conn")
+	}
+	ts.Start()
+	defer ts.Close()
+
+	var res *Response
+	var err error
+
+	res, err = ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	res, err = ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+}
+
+func TestServerBaseContextNil(t *testing.T) {
+	ln := newLocalListener(t)
+	defer ln.Close()
+	srv := &Server{BaseContext: func(net.Listener) context.Context { return nil }}
+	defer func() {
+		if e := recover(); e == nil {
+			t.Error("Serve with nil BaseContext result did not panic")
+		}
+	}()
+	srv.Serve(ln)
+}
@@ -8,9 +8,139 @@ package http
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
 	"testing"
 )
 
+func TestFindHandler(t *testing.T) {
+	mux := NewServeMux()
+	for _, ph := range []struct {
+		pat string
+		h   Handler
+	}{
+		{"/", &handler{1}},
+		{"/foo/", &handler{2}},
+		{"/foo", &handler{3}},
+		{"/bar/", &handler{4}},
+		{"//foo", &handler{5}},
+	} {
+		mux.Handle(ph.pat, ph.h)
+	}
+
+	for _, test := range []struct {
+		method      string
+		path        string
+		wantHandler string
+	}{
+		{"GET", "/", "&http.handler{i:1}"},
+		{"GET", "//", `&http.redirectHandler{url:"/", code:301}`},
+		{"GET", "/foo/../bar/./..//baz", `&http.redirectHandler{url:"/baz", code:301}`},
+		{"GET", "/foo", "&http.handler{i:3}"},
+		{"GET", "/foo/x", "&http.handler{i:2}"},
+		{"GET", "/bar/x", "&http.handler{i:4}"},
+		{"GET", "/bar", `&http.redirectHandler{url:"/bar/", code:301}`},
+		{"CONNECT", "/", "&http.handler{i:1}"},
+		{"CONNECT", "//", "&http.handler{i:1}"},
+		{"CONNECT", "//foo", "&http.handler{i:5}"},
+		{"CONNECT", "/foo/../bar/./..//baz", "&http.handler{i:2}"},
+		{"CONNECT", "/foo", "&http.handler{i:3}"},
+		{"CONNECT", "/foo/x", "&http.handler{i:2}"},
+		{"CONNECT", "/bar/x", "&http.handler{i:4}"},
+		{"CONNECT", "/bar", `&http.redirectHandler{url:"/bar/", code:301}`},
+	} {
+		var r Request
+		r.Method = test.method
+		r.Host = "example.com"
+		r.URL = &url.URL{Path: test.path}
+		gotH, _, _, _ := mux.findHandler(&r)
+		got := fmt.Sprintf("%#v", gotH)
+		if got != test.wantHandler {
+			t.Errorf("%s %q: got %q, want %q", test.method, test.path, got, test.wantHandler)
+		}
+	}
+}
+
+func TestEmptyRequest(t *testing.T) {
+	for _, test := range []struct {
+		method string
+		host   string
+		path   string
+	}{
+		{"", "", ""},
+		{"GET", "", ""},
+		{"", "example.com", ""},
+		{"", "", "/"},
+	} {
+		mux := NewServeMux()
+		mux.Handle("/", &handler{1})
+		var r Request
+		r.Method = test.method
+		r.Host = test.host
+		r.URL = &url.URL{Path: test.path}
+		h, _, _, _ := mux.findHandler(&r)
+		if h == nil {
+			t.Errorf("%q %q %q: got nil handler", test.method, test.host, test.path)
+		}
+	}
+}
+
+func TestRegisterErr(t *testing.T) {
+	mux := NewServeMux()
+	h := &handler{}
+	mux.Handle("/a", h)
+
+	for _, test := range []struct {
+		pattern    string
+		handler    Handler
+		wantRegexp string
+	}{
+		{"", h, "invalid pattern"},
+		{"/", nil, "nil handler"},
+		{"/", HandlerFunc(nil), "nil handler"},
+		{"/{x", h, `parsing "/\{x": at offset 1: bad wildcard segment`},
+		{"/a", h, `conflicts with pattern.* \(registered at .*/server_test.go:\d+`},
+	} {
+		t.Run(fmt.Sprintf("%s:%#v", test.pattern, test.handler), func(t *testing.T) {
+			err := mux.register(test.pattern, test.handler)
+			if err == nil {
+				t.Fatal("got nil error")
+			}
+			re := regexp.MustCompile(test.wantRegexp)
+			if g := err.Error(); !re.MatchString(g) {
+				t.Errorf("\ngot %q\nwant string matching %q", g, test.wantRegexp)
+			}
+		})
+	}
+}
+
+func TestExactMatch(t *testing.T) {
+	for _, test := range []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"", "/a", false},
+		{"/", "/a", false},
+		{"/a", "/a", true},
+		{"/a/{x...}", "/a/b", false},
+		{"/a/{x}", "/a/b", true},
+		{"/a/b/", "/a/b/", true},
+		{"/a/b/{$}", "/a/b/", true},
+		{"/a/", "/a/b/", false},
+	} {
+		var n *routingNode
+		if test.pattern != "" {
+			pat := mustParsePattern(t, test.pattern)
+			n = &routingNode{pattern: pat}
+		}
+		got := exactMatch(n, test.path)
+		if got != test.want {
+			t.Errorf("%q, %s: got %t, want %t", test.pattern, test.path, got, test.want)
+		}
+	}
+}
+
 func BenchmarkServerMatch(b *testing.B) {
 	fn := func(w ResponseWriter, r *Request) {
 		fmt.Fprintf(w, "OK")
@@ -37,9 +167,13 @@ func BenchmarkServerMatch(b *testing.B) {
 		"/products/", "/products/3/image.jpg"}
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
-		if h, p := mux.match(paths[i%len(paths)]); h != nil && p == "" {
+		if n, _ := mux.tree.match("", "GET", paths[i%len(paths)]); n != nil && n.pattern == nil {
 			b.Error("impossible")
 		}
 	}
 	b.StopTimer()
 }
+
+type handler struct{ i int }
+
+func (handler) ServeHTTP(ResponseWriter, *Request) {}
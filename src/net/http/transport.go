@@ -253,10 +253,31 @@ type Transport struct {
 	// Zero means to use a default limit.
 	MaxResponseHeaderBytes int64
 
+	// Protocols is the set of protocols supported by the transport.
+	//
+	// If Protocols includes UnencryptedHTTP2 and does not include
+	// HTTP1, the transport uses unencrypted HTTP/2 with prior
+	// knowledge for requests for http:// URLs. Such requests are
+	// not sent through a proxy.
+	//
+	// If Protocols includes HTTP2, HTTP/2 is negotiated on TLS
+	// connections even if the transport has a custom TLS
+	// configuration or dialer.
+	//
+	// If Protocols does not include HTTP1, only "h2" is offered
+	// during TLS negotiation, and connections on which the server
+	// does not select it fail. Requests for http:// URLs fail unless
+	// Protocols includes UnencryptedHTTP2.
+	//
+	// If Protocols is nil, the default is HTTP/1 and HTTP/2, with
+	// HTTP/2 enabled only as described in the package documentation.
+	Protocols *Protocols
+
 	// nextProtoOnce guards initialization of TLSNextProto and
 	// h2transport (via onceSetNextProtoDefaults)
 	nextProtoOnce sync.Once
-	h2transport   h2Transport // non-nil if http2 wired up
+	h2transport   h2Transport     // non-nil if http2 wired up
+	h2c           *http2Transport // non-nil if unencrypted HTTP/2 is used
 }
 
 // h2Transport is the interface we expect to be able to call from
@@ -272,6 +293,19 @@ type h2Transport interface {
 // onceSetNextProtoDefaults initializes TLSNextProto.
 // It must be called via t.nextProtoOnce.Do.
 func (t *Transport) onceSetNextProtoDefaults() {
+	if p := t.Protocols; p != nil && p.UnencryptedHTTP2() && !p.HTTP1() {
+		t.h2c = &http2Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return t.dial(ctx, network, addr)
+			},
+			t1: t,
+		}
+		t.configureHTTP2MaxHeaderListSize(t.h2c)
+	}
+	if p := t.Protocols; p != nil && !p.HTTP2() {
+		return
+	}
 	if strings.Contains(os.Getenv("GODEBUG"), "http2client=0") {
 		return
 	}
@@ -296,7 +330,7 @@ func (t *Transport) onceSetNextProtoDefaults() {
 		// Transport.
 		return
 	}
-	if t.TLSClientConfig != nil || t.Dial != nil || t.DialTLS != nil {
+	if t.Protocols == nil && (t.TLSClientConfig != nil || t.Dial != nil || t.DialTLS != nil) {
 		// Be conservative and don't automatically enable
 		// http2 if they've specified a custom TLS config or
 		// custom dialers. Let them opt-in themselves via
@@ -310,11 +344,18 @@ func (t *Transport) onceSetNextProtoDefaults() {
 		return
 	}
 	t.h2transport = t2
+	t.configureHTTP2MaxHeaderListSize(t2)
+}
 
-	// Auto-configure the http2.Transport's MaxHeaderListSize from
-	// the http.Transport's MaxResponseHeaderBytes. They don't
-	// exactly mean the same thing, but they're close.
-	//
+// http1Disabled reports whether t.Protocols excludes HTTP/1.
+func (t *Transport) http1Disabled() bool {
+	return t.Protocols != nil && !t.Protocols.HTTP1()
+}
+
+// configureHTTP2MaxHeaderListSize auto-configures the http2.Transport's
+// MaxHeaderListSize from the http.Transport's MaxResponseHeaderBytes.
+// They don't exactly mean the same thing, but they're close.
+func (t *Transport) configureHTTP2MaxHeaderListSize(t2 *http2Transport) {
 	// TODO: also add this to x/net/http2.Configure Transport, behind
 	// a +build go1.7 build tag:
 	if limit1 := t.MaxResponseHeaderBytes; limit1 != 0 && t2.MaxHeaderListSize == 0 {
@@ -443,6 +484,13 @@ func (t *Transport) roundTrip(req *Request) (*Response, error) {
 		req.closeBody()
 		return nil, errors.New("http: no Host in request URL")
 	}
+	if t.h2c != nil && scheme == "http" {
+		return t.h2c.RoundTrip(req)
+	}
+	if t.http1Disabled() && (scheme == "http" || !t.Protocols.HTTP2()) {
+		req.closeBody()
+		return nil, fmt.Errorf("net/http: Transport.Protocols has no protocol enabled for %s URLs", scheme)
+	}
 
 	for {
 		select {
@@ -605,6 +653,9 @@ func (t *Transport) CloseIdleConnections() {
 	if t2 := t.h2transport; t2 != nil {
 		t2.CloseIdleConnections()
 	}
+	if t.h2c != nil {
+		t.h2c.CloseIdleConnections()
+	}
 }
 
 // CancelRequest cancels an in-flight request by closing its connection.
@@ -1173,6 +1224,8 @@ func (pconn *persistConn) addTLS(name string, trace *httptrace.ClientTrace) erro
 	}
 	if pconn.cacheKey.onlyH1 {
 		cfg.NextProtos = nil
+	} else if pconn.t.http1Disabled() {
+		cfg.NextProtos = []string{"h2"}
 	}
 	plainConn := pconn.conn
 	tlsConn := tls.Client(plainConn, cfg)
@@ -1348,6 +1401,10 @@ func (t *Transport) dialConn(ctx context.Context, cm connectMethod) (*persistCon
 			return &persistConn{alt: next(cm.targetAddr, pconn.conn.(*tls.Conn))}, nil
 		}
 	}
+	if t.http1Disabled() {
+		pconn.conn.Close()
+		return nil, errors.New("net/http: server did not negotiate HTTP/2 and Transport.Protocols does not include HTTP1")
+	}
 
 	if t.MaxConnsPerHost > 0 {
 		pconn.conn = &connCloseListener{Conn: pconn.conn, t: t, cmKey: pconn.cacheKey}
@@ -12,7 +12,10 @@
 
 package net
 
-import "internal/bytealg"
+import (
+	"internal/bytealg"
+	"net/netip"
+)
 
 // IP address lengths (bytes).
 const (
@@ -531,6 +534,41 @@ func (n *IPNet) String() string {
 	return nn.String() + "/" + uitoa(uint(l))
 }
 
+// Prefix returns n as a netip.Prefix. IPv4 networks, including those
+// whose address is stored in 16-byte form, yield IPv4 prefixes.
+// The host bits of n.IP are kept as is; use netip.Prefix.Masked
+// to clear them.
+// If n is nil or its mask is not in the canonical form, Prefix
+// returns the zero Prefix and false.
+func (n *IPNet) Prefix() (p netip.Prefix, ok bool) {
+	if n == nil {
+		return netip.Prefix{}, false
+	}
+	nn, m := networkNumberAndMask(n)
+	if nn == nil || m == nil {
+		return netip.Prefix{}, false
+	}
+	l := simpleMaskLength(m)
+	if l == -1 {
+		return netip.Prefix{}, false
+	}
+	ip, _ := netip.AddrFromSlice(nn)
+	return netip.PrefixFrom(ip, l), true
+}
+
+// IPNetFromPrefix returns p as an IPNet whose IP is masked to the
+// network number. It returns nil if p is not valid.
+func IPNetFromPrefix(p netip.Prefix) *IPNet {
+	if !p.IsValid() {
+		return nil
+	}
+	p = p.Masked()
+	return &IPNet{
+		IP:   p.Addr().AsSlice(),
+		Mask: CIDRMask(p.Bits(), p.Addr().BitLen()),
+	}
+}
+
 // Parse IPv4 address (d.d.d.d).
 func parseIPv4(s string) IP {
 	var p [IPv4len]byte
@@ -9,6 +9,7 @@ package net
 import (
 	"bytes"
 	"math/rand"
+	"net/netip"
 	"reflect"
 	"runtime"
 	"testing"
@@ -413,6 +414,41 @@ func TestIPNetString(t *testing.T) {
 	}
 }
 
+var ipNetPrefixTests = []struct {
+	in  *IPNet
+	out string // "" means no Prefix
+}{
+	{&IPNet{IP: IPv4(192, 168, 1, 1), Mask: IPv4Mask(255, 255, 255, 0)}, "192.168.1.1/24"},
+	{&IPNet{IP: IP{10, 0, 0, 0}, Mask: IPMask{255, 0, 0, 0}}, "10.0.0.0/8"},
+	{&IPNet{IP: ParseIP("2001:db8::1"), Mask: CIDRMask(48, 128)}, "2001:db8::1/48"},
+	{&IPNet{IP: ParseIP("2001:db8::"), Mask: IPMask(ParseIP("8000:f123:0:cafe::"))}, ""},
+	{&IPNet{IP: IP{192, 168, 0}, Mask: IPv4Mask(255, 255, 255, 0)}, ""},
+	{nil, ""},
+}
+
+func TestIPNetPrefix(t *testing.T) {
+	for _, tt := range ipNetPrefixTests {
+		p, ok := tt.in.Prefix()
+		if tt.out == "" {
+			if ok {
+				t.Errorf("IPNet.Prefix(%v) = %v, true; want false", tt.in, p)
+			}
+			continue
+		}
+		if !ok || p.String() != tt.out {
+			t.Errorf("IPNet.Prefix(%v) = %v, %v; want %s, true", tt.in, p, ok, tt.out)
+			continue
+		}
+		n := IPNetFromPrefix(p)
+		if want := p.Masked().String(); n.String() != want {
+			t.Errorf("IPNetFromPrefix(%v) = %v; want %s", p, n, want)
+		}
+	}
+	if n := IPNetFromPrefix(netip.Prefix{}); n != nil {
+		t.Errorf("IPNetFromPrefix of zero Prefix = %v; want nil", n)
+	}
+}
+
 var cidrMaskTests = []struct {
 	ones int
 	bits int
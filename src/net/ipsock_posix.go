@@ -9,6 +9,7 @@ package net
 import (
 	"context"
 	"internal/poll"
+	"net/netip"
 	"runtime"
 	"syscall"
 )
@@ -180,3 +181,40 @@ func ipToSockaddr(family int, ip IP, port int, zone string) (syscall.Sockaddr, e
 	}
 	return nil, &AddrError{Err: "invalid address family", Addr: ip.String()}
 }
+
+func addrPortToSockaddr(family int, ap netip.AddrPort) (syscall.Sockaddr, error) {
+	ip := ap.Addr()
+	port := int(ap.Port())
+	switch family {
+	case syscall.AF_INET:
+		ip = ip.Unmap()
+		if !ip.Is4() {
+			return nil, &AddrError{Err: "non-IPv4 address", Addr: ip.String()}
+		}
+		return &syscall.SockaddrInet4{Port: port, Addr: ip.As4()}, nil
+	case syscall.AF_INET6:
+		// See the comment in ipToSockaddr about wildcard
+		// addresses; the same rules apply here.
+		if ip == netip.IPv4Unspecified() {
+			ip = netip.IPv6Unspecified()
+		}
+		// We accept any IPv6 address including IPv4-mapped
+		// IPv6 address. As16 maps an IPv4 address into that form.
+		return &syscall.SockaddrInet6{Port: port, Addr: ip.As16(), ZoneId: uint32(zoneCache.index(ip.Zone()))}, nil
+	}
+	return nil, &AddrError{Err: "invalid address family", Addr: ip.String()}
+}
+
+// sockaddrToAddrPort converts sa to a netip.AddrPort without
+// allocating a net.IP. It returns the zero AddrPort if sa is
+// not an internet socket address.
+func sockaddrToAddrPort(sa syscall.Sockaddr) netip.AddrPort {
+	switch sa := sa.(type) {
+	case *syscall.SockaddrInet4:
+		return netip.AddrPortFrom(netip.AddrFrom4(sa.Addr), uint16(sa.Port))
+	case *syscall.SockaddrInet6:
+		ip := netip.AddrFrom16(sa.Addr).WithZone(zoneCache.name(int(sa.ZoneId)))
+		return netip.AddrPortFrom(ip, uint16(sa.Port))
+	}
+	return netip.AddrPort{}
+}
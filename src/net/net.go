@@ -471,6 +471,8 @@ func (e *OpError) Error() string {
 	return s
 }
 
+func (e *OpError) Unwrap() error { return e.Err }
+
 var (
 	// aLongTimeAgo is a non-zero time, far in the past, used for
 	// immediate cancelation of dials.
@@ -574,6 +576,7 @@ var (
 
 // DNSError represents a DNS lookup error.
 type DNSError struct {
+	UnwrapErr   error  // error returned by the Unwrap method, may be nil
 	Err         string // description of the error
 	Name        string // name looked for
 	Server      string // server used
@@ -581,6 +584,9 @@ type DNSError struct {
 	IsTemporary bool   // if true, error is temporary; not all errors set this
 }
 
+// Unwrap returns e.UnwrapErr.
+func (e *DNSError) Unwrap() error { return e.UnwrapErr }
+
 func (e *DNSError) Error() string {
 	if e == nil {
 		return "<nil>"
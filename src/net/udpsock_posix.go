@@ -8,6 +8,7 @@ package net
 
 import (
 	"context"
+	"net/netip"
 	"syscall"
 )
 
@@ -54,6 +55,11 @@ func (c *UDPConn) readFrom(b []byte) (int, *UDPAddr, error) {
 	return n, addr, err
 }
 
+func (c *UDPConn) readFromAddrPort(b []byte) (int, netip.AddrPort, error) {
+	n, sa, err := c.fd.readFrom(b)
+	return n, sockaddrToAddrPort(sa), err
+}
+
 func (c *UDPConn) readMsg(b, oob []byte) (n, oobn, flags int, addr *UDPAddr, err error) {
 	var sa syscall.Sockaddr
 	n, oobn, flags, sa, err = c.fd.readMsg(b, oob)
@@ -66,6 +72,13 @@ func (c *UDPConn) readMsg(b, oob []byte) (n, oobn, flags int, addr *UDPAddr, err
 	return
 }
 
+func (c *UDPConn) readMsgAddrPort(b, oob []byte) (n, oobn, flags int, addr netip.AddrPort, err error) {
+	var sa syscall.Sockaddr
+	n, oobn, flags, sa, err = c.fd.readMsg(b, oob)
+	addr = sockaddrToAddrPort(sa)
+	return
+}
+
 func (c *UDPConn) writeTo(b []byte, addr *UDPAddr) (int, error) {
 	if c.fd.isConnected {
 		return 0, ErrWriteToConnected
@@ -80,6 +93,20 @@ func (c *UDPConn) writeTo(b []byte, addr *UDPAddr) (int, error) {
 	return c.fd.writeTo(b, sa)
 }
 
+func (c *UDPConn) writeToAddrPort(b []byte, addr netip.AddrPort) (int, error) {
+	if c.fd.isConnected {
+		return 0, ErrWriteToConnected
+	}
+	if !addr.IsValid() {
+		return 0, errMissingAddress
+	}
+	sa, err := addrPortToSockaddr(c.fd.family, addr)
+	if err != nil {
+		return 0, err
+	}
+	return c.fd.writeTo(b, sa)
+}
+
 func (c *UDPConn) writeMsg(b, oob []byte, addr *UDPAddr) (n, oobn int, err error) {
 	if c.fd.isConnected && addr != nil {
 		return 0, 0, ErrWriteToConnected
@@ -94,6 +121,23 @@ func (c *UDPConn) writeMsg(b, oob []byte, addr *UDPAddr) (n, oobn int, err error
 	return c.fd.writeMsg(b, oob, sa)
 }
 
+func (c *UDPConn) writeMsgAddrPort(b, oob []byte, addr netip.AddrPort) (n, oobn int, err error) {
+	if c.fd.isConnected && addr.IsValid() {
+		return 0, 0, ErrWriteToConnected
+	}
+	if !c.fd.isConnected && !addr.IsValid() {
+		return 0, 0, errMissingAddress
+	}
+	var sa syscall.Sockaddr
+	if addr.IsValid() {
+		sa, err = addrPortToSockaddr(c.fd.family, addr)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return c.fd.writeMsg(b, oob, sa)
+}
+
 func (sd *sysDialer) dialUDP(ctx context.Context, laddr, raddr *UDPAddr) (*UDPConn, error) {
 	fd, err := internetSocket(ctx, sd.network, laddr, raddr, syscall.SOCK_DGRAM, 0, "dial", sd.Dialer.Control)
 	if err != nil {
@@ -8,6 +8,7 @@ package net
 
 import (
 	"internal/testenv"
+	"net/netip"
 	"reflect"
 	"runtime"
 	"testing"
@@ -454,3 +455,68 @@ func TestUDPReadSizeError(t *testing.T) {
 		}
 	}
 }
+
+func TestUDPAddrPortConversion(t *testing.T) {
+	for _, tt := range []struct {
+		addr *UDPAddr
+		want netip.AddrPort
+	}{
+		{&UDPAddr{IP: IPv4(192, 0, 2, 1).To4(), Port: 53}, netip.MustParseAddrPort("192.0.2.1:53")},
+		{&UDPAddr{IP: ParseIP("2001:db8::1"), Port: 443}, netip.MustParseAddrPort("[2001:db8::1]:443")},
+		{&UDPAddr{IP: ParseIP("fe80::1"), Port: 1, Zone: "eth0"}, netip.MustParseAddrPort("[fe80::1%eth0]:1")},
+		{nil, netip.AddrPort{}},
+	} {
+		got := tt.addr.AddrPort()
+		if got != tt.want {
+			t.Errorf("%v.AddrPort() = %v; want %v", tt.addr, got, tt.want)
+		}
+		if tt.addr == nil {
+			continue
+		}
+		back := UDPAddrFromAddrPort(got)
+		if !back.IP.Equal(tt.addr.IP) || back.Port != tt.addr.Port || back.Zone != tt.addr.Zone {
+			t.Errorf("UDPAddrFromAddrPort(%v) = %v; want %v", got, back, tt.addr)
+		}
+	}
+	if a := UDPAddrFromAddrPort(netip.AddrPort{}); a.IP != nil {
+		t.Errorf("UDPAddrFromAddrPort of zero AddrPort has IP %v; want nil", a.IP)
+	}
+}
+
+func TestUDPAddrPortReadWrite(t *testing.T) {
+	switch runtime.GOOS {
+	case "nacl", "plan9":
+		t.Skipf("not supported on %s", runtime.GOOS)
+	}
+
+	c, err := newLocalPacketListener("udp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	uc := c.(*UDPConn)
+	laddr := uc.LocalAddr().(*UDPAddr).AddrPort()
+
+	b := []byte("HELLO-R-U-THERE")
+	if n, err := uc.WriteToUDPAddrPort(b, laddr); err != nil {
+		t.Fatal(err)
+	} else if n != len(b) {
+		t.Fatalf("WriteToUDPAddrPort wrote %d bytes; want %d", n, len(b))
+	}
+	uc.SetReadDeadline(time.Now().Add(30 * time.Second))
+	buf := make([]byte, 64)
+	n, addr, err := uc.ReadFromUDPAddrPort(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != string(b) {
+		t.Errorf("got %q; want %q", buf[:n], b)
+	}
+	if addr.Addr().Unmap() != laddr.Addr().Unmap() || addr.Port() != laddr.Port() {
+		t.Errorf("got source address %v; want %v", addr, laddr)
+	}
+
+	if _, err := uc.WriteToUDPAddrPort(b, netip.AddrPort{}); err == nil {
+		t.Error("WriteToUDPAddrPort with zero AddrPort succeeded; want error")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	encodingPkg "encoding"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -1616,6 +1617,16 @@ func TestURLErrorImplementsNetError(t *testing.T) {
 	}
 }
 
+func TestURLErrorUnwrap(t *testing.T) {
+	err := &Error{"Get", "http://google.com/", io.EOF}
+	if got := errors.Unwrap(err); got != io.EOF {
+		t.Errorf("errors.Unwrap(%v) = %v, want %v", err, got, io.EOF)
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("errors.Is(%v, io.EOF) = false, want true", err)
+	}
+}
+
 func TestURLHostname(t *testing.T) {
 	tests := []struct {
 		host string // URL.Host field
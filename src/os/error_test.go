@@ -5,6 +5,7 @@
 package os_test
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -110,6 +111,12 @@ func TestIsExist(t *testing.T) {
 		if isnot := os.IsNotExist(tt.err); isnot != tt.isnot {
 			t.Errorf("os.IsNotExist(%T %v) = %v, want %v", tt.err, tt.err, isnot, tt.isnot)
 		}
+		if is := errors.Is(tt.err, os.ErrExist); is != tt.is {
+			t.Errorf("errors.Is(%T %v, os.ErrExist) = %v, want %v", tt.err, tt.err, is, tt.is)
+		}
+		if isnot := errors.Is(tt.err, os.ErrNotExist); isnot != tt.isnot {
+			t.Errorf("errors.Is(%T %v, os.ErrNotExist) = %v, want %v", tt.err, tt.err, isnot, tt.isnot)
+		}
 	}
 }
 
@@ -129,6 +136,27 @@ func TestIsPermission(t *testing.T) {
 		if got := os.IsPermission(tt.err); got != tt.want {
 			t.Errorf("os.IsPermission(%#v) = %v; want %v", tt.err, got, tt.want)
 		}
+		if got := errors.Is(tt.err, os.ErrPermission); got != tt.want {
+			t.Errorf("errors.Is(%#v, os.ErrPermission) = %v; want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestErrIsNotExistWrapped(t *testing.T) {
+	_, err := os.Open(filepath.Join(os.TempDir(), "_Go_ErrIsNotExistWrapped_does_not_exist"))
+	if err == nil {
+		t.Fatal("Open succeeded on a missing file")
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("errors.Is(%v, os.ErrNotExist) = false, want true", err)
+	}
+	wrapped := fmt.Errorf("loading config: %w", err)
+	if !errors.Is(wrapped, os.ErrNotExist) {
+		t.Errorf("errors.Is(%v, os.ErrNotExist) = false, want true", wrapped)
+	}
+	var perr *os.PathError
+	if !errors.As(wrapped, &perr) || perr != err {
+		t.Errorf("errors.As(%v, &perr) did not find the *PathError", wrapped)
 	}
 }
 
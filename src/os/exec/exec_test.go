@@ -11,6 +11,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"internal/poll"
 	"internal/testenv"
@@ -939,6 +940,17 @@ func TestHelperProcess(*testing.T) {
 	case "sleep":
 		time.Sleep(3 * time.Second)
 		os.Exit(0)
+	case "spawnsleeper":
+		// Leave behind a "sleep" process that holds our
+		// standard output open, and exit.
+		sleeper := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "sleep")
+		sleeper.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+		sleeper.Stdout = os.Stdout
+		if err := sleeper.Start(); err != nil {
+			fmt.Fprintf(os.Stderr, "Start: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command %q\n", cmd)
 		os.Exit(2)
@@ -1150,3 +1162,137 @@ func TestDedupEnvEcho(t *testing.T) {
 		t.Errorf("output = %q; want %q", got, want)
 	}
 }
+
+func TestContextCanceledError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := helperCommandContext(t, ctx, "sleep")
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := c.Wait(); err != context.Canceled {
+		t.Errorf("Wait() = %v (%T); want %v", err, err, context.Canceled)
+	}
+	if c.ProcessState == nil || c.ProcessState.Success() {
+		t.Errorf("ProcessState = %v; want unsuccessful exit", c.ProcessState)
+	}
+}
+
+func TestContextDeadlineError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := helperCommandContext(t, ctx, "sleep").Run(); err != context.DeadlineExceeded {
+		t.Errorf("Run() = %v (%T); want %v", err, err, context.DeadlineExceeded)
+	}
+}
+
+func TestContextExitStatusBeforeCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := helperCommandContext(t, ctx, "exit", "42")
+	err := c.Run()
+	cancel()
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Errorf("Run() = %v (%T); want *exec.ExitError", err, err)
+	}
+}
+
+func TestContextCancelFailingExit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := helperCommandContext(t, ctx, "exit", "42")
+	// Stand in for a Cancel that sends a signal the command catches
+	// before exiting with a failing status of its own.
+	c.Cancel = func() error { return nil }
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// Canceling before Wait makes watchCtx call Cancel before Wait can
+	// collect the exit status.
+	cancel()
+	if err := c.Wait(); err != context.Canceled {
+		t.Errorf("Wait() = %v (%T); want %v", err, err, context.Canceled)
+	}
+	if c.ProcessState == nil || c.ProcessState.ExitCode() != 42 {
+		t.Errorf("ProcessState = %v; want exit status 42", c.ProcessState)
+	}
+}
+
+func TestContextCancelProcessDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := helperCommandContext(t, ctx, "exit", "42")
+	c.Cancel = func() error { return os.ErrProcessDone }
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	err := c.Wait()
+	if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != 42 {
+		t.Errorf("Wait() = %v (%T); want *exec.ExitError with status 42", err, err)
+	}
+}
+
+func TestCancelError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := helperCommandContext(t, ctx, "sleep")
+	boom := errors.New("boom")
+	c.Cancel = func() error {
+		return boom
+	}
+	c.WaitDelay = 100 * time.Millisecond
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	err := c.Wait()
+	if want := "exec: canceling Cmd: boom"; err == nil || err.Error() != want {
+		t.Errorf("Wait() = %v; want %q", err, want)
+	}
+	if !errors.Is(err, boom) {
+		t.Errorf("errors.Is(%v, boom) = false; want true", err)
+	}
+}
+
+func TestCancelWithoutContext(t *testing.T) {
+	c := helperCommand(t, "echo")
+	c.Cancel = func() error { return nil }
+	if err := c.Start(); err == nil {
+		c.Wait()
+		t.Fatal("Start succeeded with a non-nil Cancel and no Context")
+	}
+}
+
+func TestWaitDelayKillsAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := helperCommandContext(t, ctx, "sleep")
+	// A Cancel that does not stop the process; WaitDelay must kill it.
+	c.Cancel = func() error { return nil }
+	c.WaitDelay = 100 * time.Millisecond
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	cancel()
+	if err := c.Wait(); err != context.Canceled {
+		t.Errorf("Wait() = %v (%T); want %v", err, err, context.Canceled)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Wait took %v; want process killed after WaitDelay", d)
+	}
+}
+
+func TestWaitDelayClosesPipes(t *testing.T) {
+	if runtime.GOOS == "plan9" {
+		t.Skipf("skipping test on %q", runtime.GOOS)
+	}
+	c := helperCommand(t, "spawnsleeper")
+	var out bytes.Buffer
+	c.Stdout = &out
+	c.WaitDelay = 100 * time.Millisecond
+	start := time.Now()
+	if err := c.Run(); err != exec.ErrWaitDelay {
+		t.Errorf("Run() = %v (%T); want %v", err, err, exec.ErrWaitDelay)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Run took %v; want pipes closed after WaitDelay", d)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"internal/testenv"
 	"io"
+	"io/fs"
 	"io/ioutil"
 	. "os"
 	osexec "os/exec"
@@ -343,6 +344,33 @@ func testReaddir(dir string, contents []string, t *testing.T) {
 	}
 }
 
+func testReadDir(dir string, contents []string, t *testing.T) {
+	dirs, err := ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir %q failed: %v", dir, err)
+	}
+	if !sort.SliceIsSorted(dirs, func(i, j int) bool { return dirs[i].Name() < dirs[j].Name() }) {
+		t.Errorf("ReadDir %q: entries not sorted", dir)
+	}
+	for _, m := range contents {
+		found := false
+		for _, n := range dirs {
+			if equal(m, n.Name()) {
+				if found {
+					t.Error("present twice:", m)
+				}
+				found = true
+				if n.IsDir() {
+					t.Errorf("ReadDir %q: %s is a directory", dir, m)
+				}
+			}
+		}
+		if !found {
+			t.Error("could not find", m)
+		}
+	}
+}
+
 func TestReaddirnames(t *testing.T) {
 	testReaddirnames(".", dot, t)
 	testReaddirnames(sysdir.name, sysdir.files, t)
@@ -353,6 +381,95 @@ func TestReaddir(t *testing.T) {
 	testReaddir(sysdir.name, sysdir.files, t)
 }
 
+func TestReadDir(t *testing.T) {
+	testReadDir(".", dot, t)
+	testReadDir(sysdir.name, sysdir.files, t)
+
+	if _, err := ReadDir("/_nonexistent_"); !IsNotExist(err) {
+		t.Errorf("ReadDir of nonexistent directory = %v, want not-exist error", err)
+	}
+}
+
+func TestFileReadDirCount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestFileReadDirCount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveAll(dir)
+	for _, name := range []string{"a", "b", "c"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n := 0
+	for {
+		dirs, err := f.ReadDir(2)
+		n += len(dirs)
+		if err == io.EOF {
+			if len(dirs) != 0 {
+				t.Errorf("ReadDir(2) returned %d entries with io.EOF", len(dirs))
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dirs) == 0 || len(dirs) > 2 {
+			t.Fatalf("ReadDir(2) returned %d entries", len(dirs))
+		}
+	}
+	if n != 3 {
+		t.Errorf("ReadDir(2) returned %d entries in total, want 3", n)
+	}
+}
+
+func TestDirFS(t *testing.T) {
+	fsys := DirFS("./testdata/dirfs")
+	data, err := fs.ReadFile(fsys, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "a\n" {
+		t.Errorf("ReadFile(a) = %q, want %q", data, "a\n")
+	}
+	var visited []string
+	err = fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".", "a", "b", "dir", "dir/x"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("WalkDir visited %q, want %q", visited, want)
+	}
+	info, err := fs.Stat(fsys, "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDir() {
+		t.Errorf("Stat(dir).IsDir() = false, want true")
+	}
+	for _, name := range []string{"/a", "../a", "dir/", "dir/../a", ""} {
+		if _, err := fsys.Open(name); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Open(%q) = %v, want ErrInvalid", name, err)
+		}
+	}
+	if _, err := fsys.Open("missing"); !errors.Is(err, ErrNotExist) {
+		t.Errorf("Open(missing) = %v, want ErrNotExist", err)
+	}
+}
+
 func benchmarkReaddirname(path string, b *testing.B) {
 	var nentries int
 	for i := 0; i < b.N; i++ {
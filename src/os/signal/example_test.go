@@ -5,9 +5,11 @@
 package signal_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"time"
 )
 
 func ExampleNotify() {
@@ -36,3 +38,18 @@ func ExampleNotify_allSignals() {
 	s := <-c
 	fmt.Println("Got signal:", s)
 }
+
+// This example shows the usual way to shut a program down gracefully:
+// the first ^C cancels ctx, giving the work a chance to finish, and a
+// second ^C terminates the program immediately.
+func ExampleNotifyContext() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	select {
+	case <-time.After(time.Minute):
+		fmt.Println("finished")
+	case <-ctx.Done():
+		fmt.Println("interrupted, shutting down:", ctx.Err())
+	}
+}
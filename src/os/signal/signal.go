@@ -5,6 +5,7 @@
 package signal
 
 import (
+	"context"
 	"os"
 	"sync"
 )
@@ -240,3 +241,85 @@ func process(sig os.Signal) {
 		}
 	}
 }
+
+// NotifyContext returns a copy of the parent context that is marked done
+// (its Done channel is closed) when one of the listed signals arrives,
+// when the returned stop function is called, or when the parent context's
+// Done channel is closed, whichever happens first.
+//
+// Once the first signal has been delivered, the context stops relaying
+// the listed signals, so a second one takes its default action unless
+// other calls to Notify still want it. For a program that is shutting
+// down gracefully after os.Interrupt, this lets a second ^C terminate it.
+//
+// The stop function unregisters the signal behavior, which, like Reset,
+// may restore the default behavior for a given signal. For example, if a
+// Go program receives os.Interrupt and uses NotifyContext, calling stop
+// will cause a later os.Interrupt to terminate the program.
+//
+// Canceling this context releases resources associated with it, so code
+// should call stop as soon as the operations running in this Context
+// complete and signals no longer need to be diverted to the context.
+func NotifyContext(parent context.Context, signals ...os.Signal) (ctx context.Context, stop context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	c := &signalCtx{
+		Context: ctx,
+		cancel:  cancel,
+		signals: signals,
+	}
+	c.ch = make(chan os.Signal, 1)
+	Notify(c.ch, c.signals...)
+	if ctx.Err() == nil {
+		go func() {
+			select {
+			case <-c.ch:
+				// Stop relaying before canceling, so that by the
+				// time ctx is done a second signal gets its
+				// default behavior.
+				Stop(c.ch)
+				c.cancel()
+			case <-c.Done():
+			}
+		}()
+	}
+	return c, c.stop
+}
+
+type signalCtx struct {
+	context.Context
+
+	cancel  context.CancelFunc
+	signals []os.Signal
+	ch      chan os.Signal
+}
+
+func (c *signalCtx) stop() {
+	c.cancel()
+	Stop(c.ch)
+}
+
+type stringer interface {
+	String() string
+}
+
+func (c *signalCtx) String() string {
+	var buf []byte
+	// We know that the type of c.Context is a cancel context from
+	// package context, whose String method returns a string that ends
+	// with ".WithCancel".
+	name := c.Context.(stringer).String()
+	name = name[:len(name)-len(".WithCancel")]
+	buf = append(buf, "signal.NotifyContext("+name...)
+	if len(c.signals) != 0 {
+		buf = append(buf, ", ["...)
+		for i, s := range c.signals {
+			buf = append(buf, s.String()...)
+			if i != len(c.signals)-1 {
+				buf = append(buf, ' ')
+			}
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, ')')
+	return string(buf)
+}
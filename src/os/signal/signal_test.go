@@ -8,6 +8,7 @@ package signal
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"internal/testenv"
@@ -453,3 +454,182 @@ func atomicStopTestProgram() {
 
 	os.Exit(0)
 }
+
+func TestNotifyContext(t *testing.T) {
+	ctx, stop := NotifyContext(context.Background(), syscall.SIGHUP)
+	defer stop()
+
+	if want, got := "signal.NotifyContext(context.Background, [hangup])", fmt.Sprint(ctx); want != got {
+		t.Errorf("ctx.String() = %q, want %q", got, want)
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	select {
+	case <-ctx.Done():
+		if got := ctx.Err(); got != context.Canceled {
+			t.Errorf("ctx.Err() = %v, want %v", got, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for SIGHUP")
+	}
+
+	// After the first signal, the context no longer wants it.
+	handlers.Lock()
+	_, registered := handlers.m[ctx.(*signalCtx).ch]
+	handlers.Unlock()
+	if registered {
+		t.Error("NotifyContext still relaying signals after the first one")
+	}
+}
+
+func TestNotifyContextStop(t *testing.T) {
+	Ignore(syscall.SIGHUP)
+	if !Ignored(syscall.SIGHUP) {
+		t.Errorf("expected SIGHUP to be ignored when explicitly ignoring it.")
+	}
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	defer cancelParent()
+	c, stop := NotifyContext(parent, syscall.SIGHUP)
+	defer stop()
+
+	// If we're being notified, then the signal should not be ignored.
+	if Ignored(syscall.SIGHUP) {
+		t.Errorf("expected SIGHUP to not be ignored.")
+	}
+
+	if want, got := "signal.NotifyContext(context.Background.WithCancel, [hangup])", fmt.Sprint(c); want != got {
+		t.Errorf("c.String() = %q, wanted %q", got, want)
+	}
+
+	stop()
+	select {
+	case <-c.Done():
+		if got := c.Err(); got != context.Canceled {
+			t.Errorf("c.Err() = %q, want %q", got, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("timed out waiting for context to be done after calling stop")
+	}
+	Reset(syscall.SIGHUP)
+}
+
+func TestNotifyContextCancelParent(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	defer cancelParent()
+	c, stop := NotifyContext(parent, syscall.SIGINT)
+	defer stop()
+
+	if want, got := "signal.NotifyContext(context.Background.WithCancel, [interrupt])", fmt.Sprint(c); want != got {
+		t.Errorf("c.String() = %q, want %q", got, want)
+	}
+
+	cancelParent()
+	select {
+	case <-c.Done():
+		if got := c.Err(); got != context.Canceled {
+			t.Errorf("c.Err() = %q, want %q", got, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("timed out waiting for parent context to be canceled")
+	}
+}
+
+func TestNotifyContextPrematureCancelParent(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	defer cancelParent()
+
+	cancelParent() // Prematurely cancel context before calling NotifyContext.
+	c, stop := NotifyContext(parent, syscall.SIGINT)
+	defer stop()
+
+	if want, got := "signal.NotifyContext(context.Background.WithCancel, [interrupt])", fmt.Sprint(c); want != got {
+		t.Errorf("c.String() = %q, want %q", got, want)
+	}
+
+	select {
+	case <-c.Done():
+		if got := c.Err(); got != context.Canceled {
+			t.Errorf("c.Err() = %q, want %q", got, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("timed out waiting for parent context to be canceled")
+	}
+}
+
+func TestNotifyContextSimultaneousStop(t *testing.T) {
+	c, stop := NotifyContext(context.Background(), syscall.SIGINT)
+	defer stop()
+
+	if want, got := "signal.NotifyContext(context.Background, [interrupt])", fmt.Sprint(c); want != got {
+		t.Errorf("c.String() = %q, want %q", got, want)
+	}
+
+	var wg sync.WaitGroup
+	n := 10
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			stop()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	select {
+	case <-c.Done():
+		if got := c.Err(); got != context.Canceled {
+			t.Errorf("c.Err() = %q, want %q", got, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("expected context to be canceled")
+	}
+}
+
+// Test that a second signal after the one that canceled a NotifyContext
+// takes its default action and terminates the program.
+func TestNotifyContextSecondSignal(t *testing.T) {
+	if os.Getenv("GO_TEST_NOTIFY_CONTEXT") != "" {
+		notifyContextTestProgram()
+		t.Fatal("notifyContextTestProgram returned")
+	}
+
+	testenv.MustHaveExec(t)
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestNotifyContextSecondSignal")
+	cmd.Env = append(os.Environ(), "GO_TEST_NOTIFY_CONTEXT=1")
+	out, err := cmd.CombinedOutput()
+	if !bytes.Contains(out, []byte("canceled by first signal")) {
+		t.Errorf("first signal did not cancel the context; output: %s", out)
+	}
+	ee, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("error (%v) has type %T; expected exec.ExitError; output: %s", err, err, out)
+	}
+	if ws, ok := ee.Sys().(syscall.WaitStatus); !ok {
+		t.Errorf("error.Sys (%v) has type %T; expected syscall.WaitStatus", ee.Sys(), ee.Sys())
+	} else if !ws.Signaled() || ws.Signal() != syscall.SIGINT {
+		t.Errorf("got exit status %v; expected SIGINT; output: %s", ee, out)
+	}
+}
+
+// notifyContextTestProgram is run in a subprocess by
+// TestNotifyContextSecondSignal. It should die from the second SIGINT.
+func notifyContextTestProgram() {
+	ctx, stop := NotifyContext(context.Background(), syscall.SIGINT)
+	defer stop()
+
+	pid := syscall.Getpid()
+	syscall.Kill(pid, syscall.SIGINT)
+	select {
+	case <-ctx.Done():
+		fmt.Println("canceled by first signal")
+	case <-time.After(2 * time.Second):
+		fmt.Println("lost first signal")
+		os.Exit(0)
+	}
+
+	syscall.Kill(pid, syscall.SIGINT)
+	time.Sleep(2 * time.Second)
+	fmt.Println("survived second signal")
+	os.Exit(0)
+}
@@ -37,6 +37,11 @@
 // functions, are the atomic equivalents of "return *addr" and
 // "*addr = val".
 //
+// The types Bool, Int32, Int64, Uint32, Uint64 and Uintptr provide the
+// same operations as methods on a value of that type, which is easier
+// to use correctly than passing the address of a plain variable to the
+// functions above.
+//
 package atomic
 
 import (
@@ -49,9 +54,10 @@ import (
 //
 // On ARM, x86-32, and 32-bit MIPS,
 // it is the caller's responsibility to arrange for 64-bit
-// alignment of 64-bit words accessed atomically. The first word in a
-// variable or in an allocated struct, array, or slice can be relied upon to be
-// 64-bit aligned.
+// alignment of 64-bit words accessed atomically via the primitive
+// atomic functions (types Int64 and Uint64 are automatically aligned).
+// The first word in a variable or in an allocated struct, array, or slice
+// can be relied upon to be 64-bit aligned.
 
 // SwapInt32 atomically stores new into *addr and returns the previous *addr value.
 func SwapInt32(addr *int32, new int32) (old int32)
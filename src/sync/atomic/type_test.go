@@ -0,0 +1,253 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package atomic_test
+
+import (
+	"reflect"
+	. "sync/atomic"
+	"testing"
+	"unsafe"
+)
+
+func TestBoolMethods(t *testing.T) {
+	var x struct {
+		before uint32
+		b      Bool
+		after  uint32
+	}
+	x.before = magic32
+	x.after = magic32
+	if x.b.Load() {
+		t.Fatal("zero Bool is true")
+	}
+	x.b.Store(true)
+	if !x.b.Load() {
+		t.Fatal("Load after Store(true) = false")
+	}
+	if old := x.b.Swap(false); !old || x.b.Load() {
+		t.Fatalf("Swap(false) = %v, Load = %v; want true, false", old, x.b.Load())
+	}
+	if x.b.CompareAndSwap(true, true) || x.b.Load() {
+		t.Fatal("CompareAndSwap(true, true) succeeded on false")
+	}
+	if !x.b.CompareAndSwap(false, true) || !x.b.Load() {
+		t.Fatal("CompareAndSwap(false, true) failed on false")
+	}
+	if x.before != magic32 || x.after != magic32 {
+		t.Fatalf("wrong magic: %#x _ %#x != %#x _ %#x", x.before, x.after, magic32, magic32)
+	}
+}
+
+func TestInt32Methods(t *testing.T) {
+	var x struct {
+		before int32
+		i      Int32
+		after  int32
+	}
+	x.before = magic32
+	x.after = magic32
+	var j int32
+	for delta := int32(1); delta+delta > delta; delta += delta {
+		k := x.i.Add(delta)
+		j += delta
+		if x.i.Load() != j || k != j {
+			t.Fatalf("delta=%d i=%d j=%d k=%d", delta, x.i.Load(), j, k)
+		}
+	}
+	x.i.Store(-1)
+	if old := x.i.Swap(magic32); old != -1 || x.i.Load() != magic32 {
+		t.Fatalf("Swap: old=%d new=%d", old, x.i.Load())
+	}
+	if x.i.CompareAndSwap(0, 1) || x.i.Load() != magic32 {
+		t.Fatal("CompareAndSwap succeeded with wrong old value")
+	}
+	if !x.i.CompareAndSwap(magic32, 1) || x.i.Load() != 1 {
+		t.Fatal("CompareAndSwap failed with correct old value")
+	}
+	if x.before != magic32 || x.after != magic32 {
+		t.Fatalf("wrong magic: %#x _ %#x != %#x _ %#x", x.before, x.after, magic32, magic32)
+	}
+}
+
+func TestUint32Methods(t *testing.T) {
+	var x struct {
+		before uint32
+		i      Uint32
+		after  uint32
+	}
+	x.before = magic32
+	x.after = magic32
+	var j uint32
+	for delta := uint32(1); delta+delta > delta; delta += delta {
+		k := x.i.Add(delta)
+		j += delta
+		if x.i.Load() != j || k != j {
+			t.Fatalf("delta=%d i=%d j=%d k=%d", delta, x.i.Load(), j, k)
+		}
+	}
+	if old := x.i.Swap(magic32); old != j || x.i.Load() != magic32 {
+		t.Fatalf("Swap: old=%d new=%d", old, x.i.Load())
+	}
+	if x.i.CompareAndSwap(0, 1) || x.i.Load() != magic32 {
+		t.Fatal("CompareAndSwap succeeded with wrong old value")
+	}
+	if !x.i.CompareAndSwap(magic32, 1) || x.i.Load() != 1 {
+		t.Fatal("CompareAndSwap failed with correct old value")
+	}
+	x.i.Store(2)
+	if x.i.Load() != 2 {
+		t.Fatalf("Load after Store(2) = %d", x.i.Load())
+	}
+	if x.before != magic32 || x.after != magic32 {
+		t.Fatalf("wrong magic: %#x _ %#x != %#x _ %#x", x.before, x.after, magic32, magic32)
+	}
+}
+
+func TestInt64Methods(t *testing.T) {
+	if test64err != nil {
+		t.Skipf("Skipping 64-bit tests: %v", test64err)
+	}
+	var x struct {
+		before int32
+		i      Int64
+		after  int32
+	}
+	x.before = magic32
+	x.after = magic32
+	var j int64
+	for delta := int64(1); delta+delta > delta; delta += delta {
+		k := x.i.Add(delta)
+		j += delta
+		if x.i.Load() != j || k != j {
+			t.Fatalf("delta=%d i=%d j=%d k=%d", delta, x.i.Load(), j, k)
+		}
+	}
+	x.i.Store(-1)
+	if old := x.i.Swap(magic64); old != -1 || x.i.Load() != magic64 {
+		t.Fatalf("Swap: old=%d new=%d", old, x.i.Load())
+	}
+	if x.i.CompareAndSwap(0, 1) || x.i.Load() != magic64 {
+		t.Fatal("CompareAndSwap succeeded with wrong old value")
+	}
+	if !x.i.CompareAndSwap(magic64, 1) || x.i.Load() != 1 {
+		t.Fatal("CompareAndSwap failed with correct old value")
+	}
+	if x.before != magic32 || x.after != magic32 {
+		t.Fatalf("wrong magic: %#x _ %#x != %#x _ %#x", x.before, x.after, magic32, magic32)
+	}
+}
+
+func TestUint64Methods(t *testing.T) {
+	if test64err != nil {
+		t.Skipf("Skipping 64-bit tests: %v", test64err)
+	}
+	var x struct {
+		before uint32
+		i      Uint64
+		after  uint32
+	}
+	x.before = magic32
+	x.after = magic32
+	var j uint64
+	for delta := uint64(1); delta+delta > delta; delta += delta {
+		k := x.i.Add(delta)
+		j += delta
+		if x.i.Load() != j || k != j {
+			t.Fatalf("delta=%d i=%d j=%d k=%d", delta, x.i.Load(), j, k)
+		}
+	}
+	if old := x.i.Swap(magic64); old != j || x.i.Load() != magic64 {
+		t.Fatalf("Swap: old=%d new=%d", old, x.i.Load())
+	}
+	if x.i.CompareAndSwap(0, 1) || x.i.Load() != magic64 {
+		t.Fatal("CompareAndSwap succeeded with wrong old value")
+	}
+	if !x.i.CompareAndSwap(magic64, 1) || x.i.Load() != 1 {
+		t.Fatal("CompareAndSwap failed with correct old value")
+	}
+	x.i.Store(2)
+	if x.i.Load() != 2 {
+		t.Fatalf("Load after Store(2) = %d", x.i.Load())
+	}
+	if x.before != magic32 || x.after != magic32 {
+		t.Fatalf("wrong magic: %#x _ %#x != %#x _ %#x", x.before, x.after, magic32, magic32)
+	}
+}
+
+func TestUintptrMethods(t *testing.T) {
+	var x struct {
+		before uintptr
+		i      Uintptr
+		after  uintptr
+	}
+	var m uint64 = magic64
+	magicptr := uintptr(m)
+	x.before = magicptr
+	x.after = magicptr
+	var j uintptr
+	for delta := uintptr(1); delta+delta > delta; delta += delta {
+		k := x.i.Add(delta)
+		j += delta
+		if x.i.Load() != j || k != j {
+			t.Fatalf("delta=%d i=%d j=%d k=%d", delta, x.i.Load(), j, k)
+		}
+	}
+	if old := x.i.Swap(magicptr); old != j || x.i.Load() != magicptr {
+		t.Fatalf("Swap: old=%d new=%d", old, x.i.Load())
+	}
+	if x.i.CompareAndSwap(0, 1) || x.i.Load() != magicptr {
+		t.Fatal("CompareAndSwap succeeded with wrong old value")
+	}
+	if !x.i.CompareAndSwap(magicptr, 1) || x.i.Load() != 1 {
+		t.Fatal("CompareAndSwap failed with correct old value")
+	}
+	x.i.Store(2)
+	if x.i.Load() != 2 {
+		t.Fatalf("Load after Store(2) = %d", x.i.Load())
+	}
+	if x.before != magicptr || x.after != magicptr {
+		t.Fatalf("wrong magic: %#x _ %#x != %#x _ %#x", x.before, x.after, magicptr, magicptr)
+	}
+}
+
+// An int64 field that follows a 32-bit field is only 4-byte aligned
+// on 32-bit systems, but Int64 and Uint64 fields must always be
+// 8-byte aligned.
+type align64Test struct {
+	a int32
+	i Int64
+	b int32
+	u Uint64
+}
+
+var align64Global align64Test
+
+//go:noinline
+func checkAlign64(t *testing.T, where string, x *align64Test) {
+	if p := uintptr(unsafe.Pointer(&x.i)); p%8 != 0 {
+		t.Errorf("%s: Int64 field at %#x is not 8-byte aligned", where, p)
+	}
+	if p := uintptr(unsafe.Pointer(&x.u)); p%8 != 0 {
+		t.Errorf("%s: Uint64 field at %#x is not 8-byte aligned", where, p)
+	}
+	x.i.Add(1)
+	x.u.Add(1)
+}
+
+func TestAlign64(t *testing.T) {
+	for _, typ := range []reflect.Type{reflect.TypeOf(Int64{}), reflect.TypeOf(Uint64{})} {
+		if a := typ.Align(); a != 8 {
+			t.Errorf("%v.Align() = %d, want 8", typ, a)
+		}
+	}
+	checkAlign64(t, "global", &align64Global)
+	var local align64Test
+	checkAlign64(t, "local", &local)
+	for i := 0; i < 16; i++ {
+		checkAlign64(t, "heap", new(align64Test))
+		x := make([]align64Test, i+1)
+		checkAlign64(t, "slice", &x[i])
+	}
+}
@@ -57,3 +57,27 @@ func ExampleOnce() {
 	// Output:
 	// Only once
 }
+
+// This example uses OnceValues to compute a value and error only once,
+// however many goroutines ask for them.
+func ExampleOnceValues() {
+	once := sync.OnceValues(func() (interface{}, error) {
+		fmt.Println("Computing value")
+		return 42, nil
+	})
+	done := make(chan bool)
+	for i := 0; i < 10; i++ {
+		go func() {
+			v, err := once()
+			if v != 42 || err != nil {
+				panic("OnceValues returned the wrong result")
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+	// Output:
+	// Computing value
+}
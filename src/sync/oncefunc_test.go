@@ -0,0 +1,163 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// We assume that the Once.Do tests have already covered parallelism.
+
+func TestOnceFunc(t *testing.T) {
+	calls := 0
+	f := sync.OnceFunc(func() { calls++ })
+	allocs := testing.AllocsPerRun(10, f)
+	if calls != 1 {
+		t.Errorf("want calls==1, got %d", calls)
+	}
+	if allocs != 0 {
+		t.Errorf("want 0 allocations per call, got %v", allocs)
+	}
+}
+
+func TestOnceValue(t *testing.T) {
+	calls := 0
+	f := sync.OnceValue(func() interface{} {
+		calls++
+		return calls
+	})
+	for i := 0; i < 10; i++ {
+		if got := f(); got != 1 {
+			t.Errorf("want 1, got %v", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("want calls==1, got %d", calls)
+	}
+}
+
+func TestOnceValues(t *testing.T) {
+	calls := 0
+	errBoom := errors.New("boom")
+	f := sync.OnceValues(func() (interface{}, error) {
+		calls++
+		return calls, errBoom
+	})
+	for i := 0; i < 10; i++ {
+		v, err := f()
+		if v != 1 || err != errBoom {
+			t.Errorf("want 1, %v; got %v, %v", errBoom, v, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("want calls==1, got %d", calls)
+	}
+}
+
+func testOncePanicX(t *testing.T, calls *int, f func()) {
+	testOncePanicWith(t, calls, f, func(label string, p interface{}) {
+		if p != "x" {
+			t.Fatalf("%s: want panic %v, got %v", label, "x", p)
+		}
+	})
+}
+
+func testOncePanicWith(t *testing.T, calls *int, f func(), check func(label string, p interface{})) {
+	// Check that the each call to f panics with the same value, but the
+	// underlying function is only called once.
+	for _, label := range []string{"first time", "second time"} {
+		var p interface{}
+		panicked := true
+		func() {
+			defer func() {
+				p = recover()
+			}()
+			f()
+			panicked = false
+		}()
+		if !panicked {
+			t.Fatalf("%s: f did not panic", label)
+		}
+		check(label, p)
+	}
+	if *calls != 1 {
+		t.Errorf("want calls==1, got %d", *calls)
+	}
+}
+
+func TestOnceFuncPanic(t *testing.T) {
+	calls := 0
+	f := sync.OnceFunc(func() {
+		calls++
+		panic("x")
+	})
+	testOncePanicX(t, &calls, f)
+}
+
+func TestOnceValuePanic(t *testing.T) {
+	calls := 0
+	f := sync.OnceValue(func() interface{} {
+		calls++
+		panic("x")
+	})
+	testOncePanicX(t, &calls, func() { f() })
+}
+
+func TestOnceValuesPanic(t *testing.T) {
+	calls := 0
+	f := sync.OnceValues(func() (interface{}, error) {
+		calls++
+		panic("x")
+	})
+	testOncePanicX(t, &calls, func() { f() })
+}
+
+func TestOnceFuncPanicTraceback(t *testing.T) {
+	// Test that on the first invocation of a OnceFunc, the stack trace goes all
+	// the way to the origin of the panic.
+	f := sync.OnceFunc(onceFuncPanic)
+
+	defer func() {
+		if p := recover(); p != "x" {
+			t.Fatalf("want panic %v, got %v", "x", p)
+		}
+		stack := make([]byte, 4096)
+		stack = stack[:runtime.Stack(stack, false)]
+		if !strings.Contains(string(stack), "onceFuncPanic") {
+			t.Fatalf("want onceFuncPanic in stack trace, got:\n%s", stack)
+		}
+	}()
+	f()
+}
+
+func onceFuncPanic() {
+	panic("x")
+}
+
+func TestOnceFuncGC(t *testing.T) {
+	// The function passed to OnceFunc must not be kept alive after it has
+	// been called. Use an object large enough to avoid the tiny allocator,
+	// which may delay finalizers.
+	gc := make(chan bool)
+	f := func() func() {
+		x := new([32]byte)
+		runtime.SetFinalizer(x, func(*[32]byte) { close(gc) })
+		return sync.OnceFunc(func() { x[0]++ })
+	}()
+	f()
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		select {
+		case <-gc:
+			return
+		default:
+		}
+	}
+	t.Fatal("function passed to OnceFunc not garbage collected")
+}
@@ -0,0 +1,44 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fstest
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestMapFSSynthesizedDirs(t *testing.T) {
+	m := MapFS{
+		"hello":   {Data: []byte("hello, world\n")},
+		"a/b/c/d": {Data: []byte("d")},
+		"a/e":     {Mode: fs.ModeDir | 0700},
+	}
+
+	info, err := fs.Stat(m, "a/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDir() || info.Name() != "b" {
+		t.Errorf("Stat(a/b) = %q, IsDir=%v; want synthesized directory b", info.Name(), info.IsDir())
+	}
+
+	dirs, err := fs.ReadDir(m, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 2 || dirs[0].Name() != "b" || dirs[1].Name() != "e" {
+		t.Fatalf("ReadDir(a) = %v, want [b e]", dirs)
+	}
+	if info, _ := dirs[1].Info(); info.Mode() != fs.ModeDir|0700 {
+		t.Errorf("ReadDir(a)[1].Mode() = %v, want %v", info.Mode(), fs.ModeDir|0700)
+	}
+
+	if _, err := m.Open("a/x"); err == nil {
+		t.Error("Open(a/x) succeeded, want error")
+	}
+	if _, err := m.Open("/hello"); err == nil {
+		t.Error("Open(/hello) succeeded, want error")
+	}
+}
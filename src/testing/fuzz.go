@@ -0,0 +1,530 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testing
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	matchFuzz        = flag.String("test.fuzz", "", "run the fuzz target matching `regexp`")
+	fuzzDuration     durationOrCountFlag
+	minimizeDuration = flag.Duration("test.fuzzminimizetime", 60*time.Second, "time to spend minimizing a value after finding a failing input")
+	fuzzCacheDir     = flag.String("test.fuzzcachedir", "", "directory where interesting fuzzing inputs are stored")
+)
+
+func init() {
+	flag.Var(&fuzzDuration, "test.fuzztime", "time to spend fuzzing; default is to run indefinitely")
+}
+
+// corpusDir is the directory, relative to the package being tested, where
+// the seed corpus of each fuzz target is stored, one subdirectory per target.
+const corpusDir = "testdata/fuzz"
+
+// durationOrCountFlag is a flag that holds either a time duration, such
+// as "1m30s", or a count of iterations, such as "1000x".
+type durationOrCountFlag struct {
+	d time.Duration
+	n int
+}
+
+func (f *durationOrCountFlag) String() string {
+	if f.n > 0 {
+		return fmt.Sprintf("%dx", f.n)
+	}
+	return f.d.String()
+}
+
+func (f *durationOrCountFlag) Set(s string) error {
+	if strings.HasSuffix(s, "x") {
+		n, err := strconv.ParseInt(s[:len(s)-1], 10, 0)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid count")
+		}
+		*f = durationOrCountFlag{n: int(n)}
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid duration")
+	}
+	*f = durationOrCountFlag{d: d}
+	return nil
+}
+
+// InternalFuzzTarget is an internal type but exported because it is
+// cross-package; it is part of the implementation of the "go test" command.
+type InternalFuzzTarget struct {
+	Name string
+	Fn   func(f *F)
+}
+
+// F is a type passed to fuzz targets.
+//
+// A fuzz target may add seed corpus entries using F.Add or by storing files
+// in the testdata/fuzz/<FuzzTargetName> directory. The fuzz target must then
+// call F.Fuzz once to provide a fuzz function. See the testing package
+// documentation for an example, and see the F.Fuzz and F.Add method
+// documentation for details.
+type F struct {
+	common
+	fuzzContext *fuzzContext
+	context     *testContext
+	corpus      []corpusEntry // corpus is the in-memory corpus
+	fuzzCalled  bool          // F.Fuzz has been called
+}
+
+var _ TB = (*F)(nil)
+
+// corpusEntry is an alias to the same type as internal/fuzz.CorpusEntry.
+// We use a type alias because we don't want to export this type, and we can't
+// import internal/fuzz from testing.
+type corpusEntry = struct {
+	Parent     string
+	Path       string
+	Data       []byte
+	Values     []interface{}
+	Generation int
+	IsSeed     bool
+}
+
+// supportedTypes are the types that may be used as arguments to a fuzz
+// function and passed to F.Add.
+var supportedTypes = map[reflect.Type]bool{
+	reflect.TypeOf(([]byte)("")):  true,
+	reflect.TypeOf((string)("")):  true,
+	reflect.TypeOf((bool)(false)): true,
+	reflect.TypeOf((byte)(0)):     true,
+	reflect.TypeOf((rune)(0)):     true,
+	reflect.TypeOf((float32)(0)):  true,
+	reflect.TypeOf((float64)(0)):  true,
+	reflect.TypeOf((int)(0)):      true,
+	reflect.TypeOf((int8)(0)):     true,
+	reflect.TypeOf((int16)(0)):    true,
+	reflect.TypeOf((int32)(0)):    true,
+	reflect.TypeOf((int64)(0)):    true,
+	reflect.TypeOf((uint)(0)):     true,
+	reflect.TypeOf((uint8)(0)):    true,
+	reflect.TypeOf((uint16)(0)):   true,
+	reflect.TypeOf((uint32)(0)):   true,
+	reflect.TypeOf((uint64)(0)):   true,
+}
+
+// Add will add the arguments to the seed corpus for the fuzz target. This
+// will be a no-op if called after or within the Fuzz function. The args
+// must match those in the Fuzz function.
+func (f *F) Add(args ...interface{}) {
+	if f.fuzzCalled {
+		return
+	}
+	var values []interface{}
+	for i := range args {
+		if t := reflect.TypeOf(args[i]); !supportedTypes[t] {
+			panic(fmt.Sprintf("testing: unsupported type to Add %v", t))
+		}
+		values = append(values, args[i])
+	}
+	f.corpus = append(f.corpus, corpusEntry{Values: values, IsSeed: true, Path: fmt.Sprintf("seed#%d", len(f.corpus))})
+}
+
+// Fuzz runs the fuzz function, ff, for fuzz testing. If ff fails for a set
+// of arguments, those arguments will be added to the seed corpus.
+//
+// ff must be a function with no return value whose first argument is *T
+// and whose remaining arguments are the types to be fuzzed.
+// For example:
+//
+//     f.Fuzz(func(t *testing.T, b []byte, i int) { ... })
+//
+// The following types are allowed: []byte, string, bool, byte, rune,
+// float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16,
+// uint32, uint64. More types may be supported in the future.
+//
+// ff must not call any *F methods, e.g. (*F).Log, (*F).Error, (*F).Skip.
+// Use the corresponding *T method instead. ff must not call t.Parallel.
+//
+// This function should be fast and deterministic, and its behavior should
+// not depend on shared state. No mutable input arguments, or pointers to
+// them, should be retained between executions of the fuzz function, as the
+// memory backing them may be mutated during a subsequent invocation. ff
+// must not modify the underlying data of the arguments provided by the
+// fuzzing engine.
+//
+// When fuzzing, F.Fuzz does not return until a problem is found, time runs
+// out (set with -fuzztime), or the test process is interrupted by a signal.
+// F.Fuzz should be called exactly once, unless F.Skip or F.Fail is called
+// beforehand.
+func (f *F) Fuzz(ff interface{}) {
+	if f.fuzzCalled {
+		panic("testing: F.Fuzz called more than once")
+	}
+	f.fuzzCalled = true
+	f.Helper()
+
+	// ff should be in the form func(*testing.T, ...interface{})
+	fn := reflect.ValueOf(ff)
+	fnType := fn.Type()
+	if fnType.Kind() != reflect.Func {
+		panic("testing: F.Fuzz must receive a function")
+	}
+	if fnType.NumIn() < 2 || fnType.In(0) != reflect.TypeOf((*T)(nil)) {
+		panic("testing: fuzz target must receive at least two arguments, where the first argument is a *T")
+	}
+	if fnType.NumOut() != 0 {
+		panic("testing: fuzz target must not return a value")
+	}
+
+	// Save the types of the function to compare against the corpus.
+	var types []reflect.Type
+	for i := 1; i < fnType.NumIn(); i++ {
+		t := fnType.In(i)
+		if !supportedTypes[t] {
+			panic(fmt.Sprintf("testing: unsupported type for fuzzing %v", t))
+		}
+		types = append(types, t)
+	}
+
+	// Load the testdata seed corpus. Check types of entries in the testdata
+	// corpus and entries declared with F.Add.
+	dir := filepath.Join(corpusDir, f.name)
+	c, err := f.fuzzContext.deps.ReadCorpus(dir, types)
+	if err != nil {
+		f.Fatal(err)
+	}
+	for i := range c {
+		c[i].IsSeed = true
+	}
+	for _, e := range f.corpus {
+		if err := f.fuzzContext.deps.CheckCorpus(e.Values, types); err != nil {
+			f.Fatalf("%s: %v", e.Path, err)
+		}
+	}
+	f.corpus = append(f.corpus, c...)
+
+	call := func(t *T, e corpusEntry) {
+		args := []reflect.Value{reflect.ValueOf(t)}
+		for _, v := range e.Values {
+			args = append(args, reflect.ValueOf(v))
+		}
+		fn.Call(args)
+	}
+
+	switch f.fuzzContext.mode {
+	case fuzzCoordinator:
+		// run calls ff on a single input in a new goroutine with its own
+		// T, returning the output of the test as an error if it failed.
+		// A panic in ff is reported as a failure rather than ending the
+		// process, so that the input can be minimized and saved.
+		run := func(e corpusEntry) error {
+			t := &T{
+				common: common{
+					signal:  make(chan bool),
+					barrier: make(chan bool),
+					name:    f.name,
+					level:   1,
+				},
+				context: f.context,
+			}
+			t.w = indenter{&t.common}
+			go tRunner(t, func(t *T) {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("panic: %v\n%s", r, debug.Stack())
+					}
+				}()
+				call(t, e)
+			})
+			<-t.signal
+			if !t.Failed() {
+				return nil
+			}
+			if len(t.output) == 0 {
+				return errors.New("    fuzz function failed\n")
+			}
+			return errors.New(string(t.output))
+		}
+		var cacheDir string
+		if *fuzzCacheDir != "" {
+			cacheDir = filepath.Join(*fuzzCacheDir, f.name)
+		}
+		err := f.fuzzContext.deps.CoordinateFuzzing(fuzzDuration.d, int64(fuzzDuration.n), *minimizeDuration, f.corpus, types, dir, cacheDir, run)
+		if err != nil {
+			f.fuzzFailed(err)
+		}
+
+	default:
+		// Run each seed corpus entry as a subtest of f.
+		for _, e := range f.corpus {
+			name := filepath.Base(e.Path)
+			f.runSeed(name, func(t *T) { call(t, e) })
+		}
+	}
+}
+
+// fuzzFailed records the failure reported by the fuzzing engine, along
+// with instructions for reproducing it if the failing input was saved.
+func (f *F) fuzzFailed(err error) {
+	f.Fail()
+	type crashPather interface {
+		CrashPath() string
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "    ") {
+		msg = "    " + msg
+	}
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	f.output = append(f.output, msg...)
+	if crashErr, ok := err.(crashPather); ok {
+		crashName := filepath.Base(crashErr.CrashPath())
+		f.output = append(f.output, fmt.Sprintf("\n    Failing input written to %s\n    To re-run:\n    go test -run=%s/%s\n",
+			filepath.Join(corpusDir, f.name, crashName), f.name, crashName)...)
+	}
+}
+
+// runSeed runs fn as a subtest of f called name, in the same way that
+// T.Run runs a subtest of a test.
+func (f *F) runSeed(name string, fn func(t *T)) bool {
+	atomic.StoreInt32(&f.hasSub, 1)
+	testName, ok, _ := f.context.match.fullName(&f.common, name)
+	if !ok || shouldFailFast() {
+		return true
+	}
+	var pc [maxStackLen]uintptr
+	n := runtime.Callers(2, pc[:])
+	t := &T{
+		common: common{
+			barrier: make(chan bool),
+			signal:  make(chan bool),
+			name:    testName,
+			parent:  &f.common,
+			level:   f.level + 1,
+			creator: pc[:n],
+			chatty:  f.chatty,
+		},
+		context: f.context,
+	}
+	t.w = indenter{&t.common}
+
+	if t.chatty {
+		// Print directly to root's io.Writer so there is no delay.
+		root := t.parent
+		for ; root.parent != nil; root = root.parent {
+		}
+		root.mu.Lock()
+		fmt.Fprintf(root.w, "=== RUN   %s\n", t.name)
+		root.mu.Unlock()
+	}
+	go tRunner(t, fn)
+	if !<-t.signal {
+		// At this point, it is likely that FailNow was called on one of the
+		// parent tests by one of the subtests. Continue aborting up the chain.
+		runtime.Goexit()
+	}
+	return !t.failed
+}
+
+// fuzzMode is the mode a fuzz target runs in.
+type fuzzMode uint8
+
+const (
+	// seedCorpusOnly runs the fuzz function on each seed corpus entry,
+	// as an ordinary test.
+	seedCorpusOnly fuzzMode = iota
+
+	// fuzzCoordinator runs the fuzzing engine on the fuzz function.
+	fuzzCoordinator
+)
+
+// fuzzContext holds fields common to all fuzz targets.
+type fuzzContext struct {
+	deps testDeps
+	mode fuzzMode
+}
+
+// runFuzzTests runs the fuzz targets matching the pattern for -run. This
+// will only run the f.Fuzz function for each seed corpus entry, without
+// generating new inputs.
+func runFuzzTests(deps testDeps, fuzzTargets []InternalFuzzTarget) (ran, ok bool) {
+	ok = true
+	if len(fuzzTargets) == 0 {
+		return ran, ok
+	}
+	for _, procs := range cpuList {
+		runtime.GOMAXPROCS(procs)
+		for i := uint(0); i < *count; i++ {
+			if shouldFailFast() {
+				break
+			}
+			tctx := newTestContext(*parallel, newMatcher(deps.MatchString, *match, "-test.run"))
+			fctx := &fuzzContext{deps: deps, mode: seedCorpusOnly}
+			root := common{w: os.Stdout, chatty: *chatty}
+			for _, ft := range fuzzTargets {
+				if shouldFailFast() {
+					break
+				}
+				testName, matched, _ := tctx.match.fullName(nil, ft.Name)
+				if !matched {
+					continue
+				}
+				f := newF(testName, &root, tctx, fctx)
+				if f.chatty {
+					fmt.Fprintf(root.w, "=== RUN   %s\n", f.name)
+				}
+				go fRunner(f, ft.Fn)
+				<-f.signal
+			}
+			ok = ok && !root.Failed()
+			ran = ran || root.ran
+		}
+	}
+	return ran, ok
+}
+
+// runFuzzing runs the fuzz target matching the pattern for -fuzz. Only one
+// fuzz target must match. This will run the fuzzing engine to generate and
+// mutate new inputs against the f.Fuzz function.
+func runFuzzing(deps testDeps, fuzzTargets []InternalFuzzTarget) (ok bool) {
+	if len(fuzzTargets) == 0 || *matchFuzz == "" {
+		return true
+	}
+	m := newMatcher(deps.MatchString, *matchFuzz, "-test.fuzz")
+	var target *InternalFuzzTarget
+	var targetName string
+	var matched []string
+	for i := range fuzzTargets {
+		name, ok, _ := m.fullName(nil, fuzzTargets[i].Name)
+		if !ok {
+			continue
+		}
+		matched = append(matched, name)
+		target = &fuzzTargets[i]
+		targetName = name
+	}
+	if len(matched) == 0 {
+		fmt.Fprintln(os.Stderr, "testing: warning: no targets to fuzz")
+		return true
+	}
+	if len(matched) > 1 {
+		fmt.Fprintf(os.Stderr, "testing: will not fuzz, -fuzz matches more than one target: %v\n", matched)
+		return false
+	}
+
+	tctx := newTestContext(1, newMatcher(deps.MatchString, "", ""))
+	fctx := &fuzzContext{deps: deps, mode: fuzzCoordinator}
+	root := common{w: os.Stdout, chatty: *chatty}
+	f := newF(targetName, &root, tctx, fctx)
+	if f.chatty {
+		fmt.Fprintf(root.w, "=== FUZZ  %s\n", f.name)
+	}
+	go fRunner(f, target.Fn)
+	<-f.signal
+	return !f.failed
+}
+
+// newF returns a new F for the fuzz target name, as a child of parent.
+func newF(name string, parent *common, tctx *testContext, fctx *fuzzContext) *F {
+	f := &F{
+		common: common{
+			signal:  make(chan bool),
+			barrier: make(chan bool),
+			name:    name,
+			parent:  parent,
+			level:   parent.level + 1,
+			chatty:  parent.chatty,
+		},
+		context:     tctx,
+		fuzzContext: fctx,
+	}
+	f.w = indenter{&f.common}
+	return f
+}
+
+// fRunner wraps a call to a fuzz target and ensures that cleanup functions
+// are called and status flags are set. fRunner should be called in its own
+// goroutine. To wait for its completion, receive from f.signal.
+//
+// fRunner is analogous with tRunner, which wraps subtests started with T.Run.
+func fRunner(f *F, fn func(*F)) {
+	f.runner = callerName(0)
+
+	// When this goroutine is done, either because fn(f) returned normally
+	// or because a test failure triggered a call to runtime.Goexit, record
+	// the duration and send a signal saying that the test is done.
+	defer func() {
+		if f.Failed() {
+			atomic.AddUint32(&numFailed, 1)
+		}
+
+		f.duration += time.Since(f.start)
+		// If the fuzz target panicked, print any test output before dying.
+		err := recover()
+		if !f.finished && err == nil {
+			err = errNilPanicOrGoexit
+		}
+		doPanic := func(err interface{}) {
+			f.Fail()
+			if r := f.runCleanup(recoverAndReturnPanic); r != nil {
+				f.Logf("cleanup panicked with %v", r)
+			}
+			f.report()
+			panic(err)
+		}
+		if err != nil {
+			doPanic(err)
+		}
+
+		if len(f.sub) > 0 {
+			// Run parallel seed corpus entries.
+			// Decrease the running count for this fuzz target.
+			f.context.release()
+			// Release the parallel subtests.
+			close(f.barrier)
+			// Wait for the subtests to complete.
+			for _, sub := range f.sub {
+				<-sub.signal
+			}
+			cleanupStart := time.Now()
+			err := f.runCleanup(recoverAndReturnPanic)
+			f.duration += time.Since(cleanupStart)
+			if err != nil {
+				doPanic(err)
+			}
+			// Reacquire the count for sequential tests.
+			f.context.waitParallel()
+		}
+		f.report() // Report after all subtests have finished.
+
+		f.done = true
+		f.setRan()
+		f.signal <- true
+	}()
+	defer func() {
+		if len(f.sub) == 0 {
+			f.runCleanup(normalPanic)
+		}
+	}()
+
+	f.start = time.Now()
+	fn(f)
+
+	// Code beyond this point will not be executed when FailNow or SkipNow
+	// is invoked.
+	f.finished = true
+}
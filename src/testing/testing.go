@@ -132,6 +132,53 @@
 // example function, at least one other function, type, variable, or constant
 // declaration, and no test or benchmark functions.
 //
+// Fuzzing
+//
+// 'go test' and the testing package support fuzzing, a testing technique where
+// a function is called with randomly generated inputs to find bugs not
+// anticipated by unit tests.
+//
+// Functions of the form
+//     func FuzzXxx(*testing.F)
+// are considered fuzz targets.
+//
+// For example:
+//
+//     func FuzzHex(f *testing.F) {
+//         for _, seed := range [][]byte{{}, {0}, {9}, {0xa}, {0xf}, {1, 2, 3, 4}} {
+//             f.Add(seed)
+//         }
+//         f.Fuzz(func(t *testing.T, in []byte) {
+//             enc := hex.EncodeToString(in)
+//             out, err := hex.DecodeString(enc)
+//             if err != nil {
+//                 t.Fatalf("%v: decode: %v", in, err)
+//             }
+//             if !bytes.Equal(in, out) {
+//                 t.Fatalf("%v: not equal after round trip: %v", in, out)
+//             }
+//         })
+//     }
+//
+// Seed inputs may be registered by calling F.Add or by storing files in the
+// directory testdata/fuzz/<Name> (where <Name> is the name of the fuzz target)
+// within the package containing the fuzz target. Seed inputs are optional, but
+// the fuzzing engine may find bugs more efficiently when provided with a set
+// of small seed inputs with good code coverage.
+//
+// When fuzzing is disabled, the fuzz function is called with the seed inputs
+// registered with F.Add and seed inputs from testdata/fuzz/<Name>, each as
+// a subtest of the fuzz target. In this mode, the fuzz target acts much like
+// a regular test.
+//
+// When fuzzing is enabled with the -fuzz flag, the fuzzing engine generates
+// inputs by mutating the seed inputs, and calls the fuzz function with them.
+// The code being tested is built with coverage instrumentation, and inputs
+// that reach new code are kept and mutated further. These inputs are cached
+// in the build cache. When the fuzz function fails, the engine minimizes the
+// failing input and writes it to a file in testdata/fuzz/<Name>, where it
+// serves as a seed input that reproduces the failure as a normal test.
+//
 // Skipping
 //
 // Tests or benchmarks may be skipped at run time with a call to
@@ -239,6 +286,8 @@ import (
 	"internal/race"
 	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"runtime"
 	"runtime/debug"
 	"runtime/trace"
@@ -308,12 +357,15 @@ type common struct {
 	skipped bool                // Test of benchmark has been skipped.
 	done    bool                // Test is finished and all subtests have completed.
 	helpers map[string]struct{} // functions to be skipped when writing file/line info
+	cleanup func()              // optional function to be called at the end of the test
 
 	chatty     bool   // A copy of the chatty flag.
 	finished   bool   // Test function has completed.
 	hasSub     int32  // written atomically
 	raceErrors int    // number of races detected during test
 	runner     string // function name of tRunner running the test
+	isParallel bool   // Test is running in parallel with other tests.
+	isEnvSet   bool   // Test has changed the environment with Setenv.
 
 	parent   *common
 	level    int       // Nesting depth of test or benchmark.
@@ -324,6 +376,11 @@ type common struct {
 	barrier  chan bool // To signal parallel subtests they may start.
 	signal   chan bool // To signal a test is done.
 	sub      []*T      // Queue of subtests to be run in parallel.
+
+	tempDirOnce sync.Once
+	tempDir     string
+	tempDirErr  error
+	tempDirSeq  int32
 }
 
 // Short reports whether the -test.short flag is set.
@@ -511,6 +568,9 @@ type TB interface {
 	Skipf(format string, args ...interface{})
 	Skipped() bool
 	Helper()
+	Cleanup(func())
+	Setenv(key, value string)
+	TempDir() string
 
 	// A private method to prevent users implementing the
 	// interface and so future additions to it will not
@@ -533,8 +593,7 @@ var _ TB = (*B)(nil)
 // may be called simultaneously from multiple goroutines.
 type T struct {
 	common
-	isParallel bool
-	context    *testContext // For running tests and subtests.
+	context *testContext // For running tests and subtests.
 }
 
 func (c *common) private() {}
@@ -723,6 +782,111 @@ func (c *common) Helper() {
 	c.helpers[callerName(1)] = struct{}{}
 }
 
+// Cleanup registers a function to be called when the test (or benchmark)
+// and all its subtests complete. Cleanup functions will be called in last
+// added, first called order.
+func (c *common) Cleanup(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	oldCleanup := c.cleanup
+	c.cleanup = func() {
+		if oldCleanup != nil {
+			defer oldCleanup()
+		}
+		f()
+	}
+}
+
+// panicHandling is an argument to runCleanup.
+type panicHandling int
+
+const (
+	normalPanic panicHandling = iota
+	recoverAndReturnPanic
+)
+
+// runCleanup is called at the end of the test.
+// If ph is recoverAndReturnPanic, it will catch panics, and return the
+// recovered value if any.
+func (c *common) runCleanup(ph panicHandling) (panicVal interface{}) {
+	c.mu.Lock()
+	cleanup := c.cleanup
+	c.cleanup = nil
+	c.mu.Unlock()
+	if cleanup == nil {
+		return nil
+	}
+
+	if ph == recoverAndReturnPanic {
+		defer func() {
+			panicVal = recover()
+		}()
+	}
+
+	cleanup()
+	return nil
+}
+
+// TempDir returns a temporary directory for the test (or benchmark) to use.
+// The directory is automatically removed by Cleanup when the test and all
+// its subtests complete. Each call to TempDir returns a new, unique
+// directory. If the directory cannot be created, TempDir terminates the
+// test by calling Fatal.
+func (c *common) TempDir() string {
+	c.Helper()
+	c.tempDirOnce.Do(func() {
+		// Path separators in the test name would put the directory
+		// somewhere else, so mangle the name to accommodate subtests.
+		pattern := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(c.Name())
+		c.tempDir, c.tempDirErr = mkdirTemp(pattern)
+		if c.tempDirErr == nil {
+			c.Cleanup(func() {
+				if err := os.RemoveAll(c.tempDir); err != nil {
+					c.Errorf("TempDir RemoveAll cleanup: %v", err)
+				}
+			})
+		}
+	})
+	if c.tempDirErr != nil {
+		c.Fatalf("TempDir: %v", c.tempDirErr)
+	}
+	seq := atomic.AddInt32(&c.tempDirSeq, 1)
+	dir := fmt.Sprintf("%s%c%03d", c.tempDir, os.PathSeparator, seq)
+	if err := os.Mkdir(dir, 0777); err != nil {
+		c.Fatalf("TempDir: %v", err)
+	}
+	return dir
+}
+
+// Setenv calls os.Setenv(key, value) and uses Cleanup to restore the
+// environment variable to its original value after the test (or benchmark).
+//
+// Because the environment is shared by the whole process, Setenv cannot be
+// used in parallel tests or in tests with parallel ancestors.
+func (c *common) Setenv(key, value string) {
+	c.Helper()
+	for p := c; p != nil; p = p.parent {
+		if p.isParallel {
+			panic("testing: Setenv called in a parallel test; cannot set environment variables in parallel tests")
+		}
+	}
+	c.isEnvSet = true
+
+	prevValue, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		c.Fatalf("cannot set environment variable: %v", err)
+	}
+	if ok {
+		c.Cleanup(func() {
+			os.Setenv(key, prevValue)
+		})
+	} else {
+		c.Cleanup(func() {
+			os.Unsetenv(key)
+		})
+	}
+}
+
 // callerName gives the function name (qualified with a package path)
 // for the caller after skip frames (where 0 means the current function).
 func callerName(skip int) string {
@@ -740,11 +904,14 @@ func callerName(skip int) string {
 // Parallel signals that this test is to be run in parallel with (and only with)
 // other parallel tests. When a test is run multiple times due to use of
 // -test.count or -test.cpu, multiple instances of a single test never run in
-// parallel with each other.
+// parallel with each other. Parallel panics if the test has called Setenv.
 func (t *T) Parallel() {
 	if t.isParallel {
 		panic("testing: t.Parallel called multiple times")
 	}
+	if t.isEnvSet {
+		panic("testing: t.Parallel called after t.Setenv; cannot set environment variables in parallel tests")
+	}
 	t.isParallel = true
 
 	// We don't want to include the time we spend waiting for serial tests
@@ -824,11 +991,17 @@ func tRunner(t *T, fn func(t *T)) {
 				}
 			}
 		}
-		if err != nil {
+		doPanic := func(err interface{}) {
 			t.Fail()
+			if r := t.runCleanup(recoverAndReturnPanic); r != nil {
+				t.Logf("cleanup panicked with %v", r)
+			}
 			t.report()
 			panic(err)
 		}
+		if err != nil {
+			doPanic(err)
+		}
 
 		if len(t.sub) > 0 {
 			// Run parallel subtests.
@@ -840,6 +1013,13 @@ func tRunner(t *T, fn func(t *T)) {
 			for _, sub := range t.sub {
 				<-sub.signal
 			}
+			// Run the cleanup functions now that the subtests are done.
+			cleanupStart := time.Now()
+			err := t.runCleanup(recoverAndReturnPanic)
+			t.duration += time.Since(cleanupStart)
+			if err != nil {
+				doPanic(err)
+			}
 			if !t.isParallel {
 				// Reacquire the count for sequential tests. See comment in Run.
 				t.context.waitParallel()
@@ -859,6 +1039,13 @@ func tRunner(t *T, fn func(t *T)) {
 		}
 		t.signal <- signal
 	}()
+	defer func() {
+		// Without parallel subtests, there is nothing left to wait for,
+		// so run the cleanup functions before the deferred function above.
+		if len(t.sub) == 0 {
+			t.runCleanup(normalPanic)
+		}
+	}()
 
 	t.start = time.Now()
 	t.raceErrors = -race.Errors()
@@ -989,6 +1176,13 @@ func (f matchStringOnly) WriteProfileTo(string, io.Writer, int) error { return e
 func (f matchStringOnly) ImportPath() string                          { return "" }
 func (f matchStringOnly) StartTestLog(io.Writer)                      {}
 func (f matchStringOnly) StopTestLog() error                          { return errMain }
+func (f matchStringOnly) CoordinateFuzzing(time.Duration, int64, time.Duration, []corpusEntry, []reflect.Type, string, string, func(corpusEntry) error) error {
+	return errMain
+}
+func (f matchStringOnly) ReadCorpus(string, []reflect.Type) ([]corpusEntry, error) {
+	return nil, errMain
+}
+func (f matchStringOnly) CheckCorpus([]interface{}, []reflect.Type) error { return nil }
 
 // Main is an internal function, part of the implementation of the "go test" command.
 // It was exported because it is cross-package and predates "internal" packages.
@@ -997,15 +1191,16 @@ func (f matchStringOnly) StopTestLog() error                          { return e
 // new functionality is added to the testing package.
 // Systems simulating "go test" should be updated to use MainStart.
 func Main(matchString func(pat, str string) (bool, error), tests []InternalTest, benchmarks []InternalBenchmark, examples []InternalExample) {
-	os.Exit(MainStart(matchStringOnly(matchString), tests, benchmarks, examples).Run())
+	os.Exit(MainStart(matchStringOnly(matchString), tests, benchmarks, nil, examples).Run())
 }
 
 // M is a type passed to a TestMain function to run the actual tests.
 type M struct {
-	deps       testDeps
-	tests      []InternalTest
-	benchmarks []InternalBenchmark
-	examples   []InternalExample
+	deps        testDeps
+	tests       []InternalTest
+	benchmarks  []InternalBenchmark
+	fuzzTargets []InternalFuzzTarget
+	examples    []InternalExample
 
 	timer     *time.Timer
 	afterOnce sync.Once
@@ -1025,17 +1220,21 @@ type testDeps interface {
 	StartTestLog(io.Writer)
 	StopTestLog() error
 	WriteProfileTo(string, io.Writer, int) error
+	CoordinateFuzzing(time.Duration, int64, time.Duration, []corpusEntry, []reflect.Type, string, string, func(corpusEntry) error) error
+	ReadCorpus(string, []reflect.Type) ([]corpusEntry, error)
+	CheckCorpus([]interface{}, []reflect.Type) error
 }
 
 // MainStart is meant for use by tests generated by 'go test'.
 // It is not meant to be called directly and is not subject to the Go 1 compatibility document.
 // It may change signature from release to release.
-func MainStart(deps testDeps, tests []InternalTest, benchmarks []InternalBenchmark, examples []InternalExample) *M {
+func MainStart(deps testDeps, tests []InternalTest, benchmarks []InternalBenchmark, fuzzTargets []InternalFuzzTarget, examples []InternalExample) *M {
 	return &M{
-		deps:       deps,
-		tests:      tests,
-		benchmarks: benchmarks,
-		examples:   examples,
+		deps:        deps,
+		tests:       tests,
+		benchmarks:  benchmarks,
+		fuzzTargets: fuzzTargets,
+		examples:    examples,
 	}
 }
 
@@ -1059,7 +1258,7 @@ func (m *M) Run() int {
 	}
 
 	if len(*matchList) != 0 {
-		listTests(m.deps.MatchString, m.tests, m.benchmarks, m.examples)
+		listTests(m.deps.MatchString, m.tests, m.benchmarks, m.fuzzTargets, m.examples)
 		return 0
 	}
 
@@ -1070,12 +1269,19 @@ func (m *M) Run() int {
 	m.startAlarm()
 	haveExamples = len(m.examples) > 0
 	testRan, testOk := runTests(m.deps.MatchString, m.tests)
+	fuzzTargetsRan, fuzzTargetsOk := runFuzzTests(m.deps, m.fuzzTargets)
 	exampleRan, exampleOk := runExamples(m.deps.MatchString, m.examples)
 	m.stopAlarm()
-	if !testRan && !exampleRan && *matchBenchmarks == "" {
+	if !testRan && !fuzzTargetsRan && !exampleRan && *matchBenchmarks == "" && *matchFuzz == "" {
 		fmt.Fprintln(os.Stderr, "testing: warning: no tests to run")
 	}
-	if !testOk || !exampleOk || !runBenchmarks(m.deps.ImportPath(), m.deps.MatchString, m.benchmarks) || race.Errors() > 0 {
+	if !testOk || !fuzzTargetsOk || !exampleOk || !runBenchmarks(m.deps.ImportPath(), m.deps.MatchString, m.benchmarks) || race.Errors() > 0 {
+		fmt.Println("FAIL")
+		return 1
+	}
+	// Fuzzing runs without the -test.timeout alarm, after all other tests
+	// have passed.
+	if !runFuzzing(m.deps, m.fuzzTargets) {
 		fmt.Println("FAIL")
 		return 1
 	}
@@ -1084,24 +1290,24 @@ func (m *M) Run() int {
 	return 0
 }
 
-func (t *T) report() {
-	if t.parent == nil {
+func (c *common) report() {
+	if c.parent == nil {
 		return
 	}
-	dstr := fmtDuration(t.duration)
+	dstr := fmtDuration(c.duration)
 	format := "--- %s: %s (%s)\n"
-	if t.Failed() {
-		t.flushToParent(format, "FAIL", t.name, dstr)
-	} else if t.chatty {
-		if t.Skipped() {
-			t.flushToParent(format, "SKIP", t.name, dstr)
+	if c.Failed() {
+		c.flushToParent(format, "FAIL", c.name, dstr)
+	} else if c.chatty {
+		if c.Skipped() {
+			c.flushToParent(format, "SKIP", c.name, dstr)
 		} else {
-			t.flushToParent(format, "PASS", t.name, dstr)
+			c.flushToParent(format, "PASS", c.name, dstr)
 		}
 	}
 }
 
-func listTests(matchString func(pat, str string) (bool, error), tests []InternalTest, benchmarks []InternalBenchmark, examples []InternalExample) {
+func listTests(matchString func(pat, str string) (bool, error), tests []InternalTest, benchmarks []InternalBenchmark, fuzzTargets []InternalFuzzTarget, examples []InternalExample) {
 	if _, err := matchString(*matchList, "non-empty"); err != nil {
 		fmt.Fprintf(os.Stderr, "testing: invalid regexp in -test.list (%q): %s\n", *matchList, err)
 		os.Exit(1)
@@ -1117,6 +1323,11 @@ func listTests(matchString func(pat, str string) (bool, error), tests []Internal
 			fmt.Println(bench.Name)
 		}
 	}
+	for _, fuzzTarget := range fuzzTargets {
+		if ok, _ := matchString(*matchList, fuzzTarget.Name); ok {
+			fmt.Println(fuzzTarget.Name)
+		}
+	}
 	for _, example := range examples {
 		if ok, _ := matchString(*matchList, example.Name); ok {
 			fmt.Println(example.Name)
@@ -1364,3 +1575,53 @@ func parseCpuList() {
 func shouldFailFast() bool {
 	return *failFast && atomic.LoadUint32(&numFailed) > 0
 }
+
+// Random number state for mkdirTemp, as in io/ioutil's tempfile.go.
+var tempRand uint32
+var tempRandMu sync.Mutex
+
+func tempReseed() uint32 {
+	return uint32(time.Now().UnixNano() + int64(os.Getpid()))
+}
+
+func tempNextRandom() string {
+	tempRandMu.Lock()
+	r := tempRand
+	if r == 0 {
+		r = tempReseed()
+	}
+	r = r*1664525 + 1013904223 // constants from Numerical Recipes
+	tempRand = r
+	tempRandMu.Unlock()
+	return strconv.Itoa(int(1e9 + r%1e9))[1:]
+}
+
+// mkdirTemp is a copy of ioutil.TempDir with dir set to os.TempDir().
+// Package testing cannot import io/ioutil: the tests of io/ioutil
+// import package testing.
+func mkdirTemp(prefix string) (name string, err error) {
+	dir := os.TempDir()
+	nconflict := 0
+	for i := 0; i < 10000; i++ {
+		try := filepath.Join(dir, prefix+tempNextRandom())
+		err = os.Mkdir(try, 0700)
+		if os.IsExist(err) {
+			if nconflict++; nconflict > 10 {
+				tempRandMu.Lock()
+				tempRand = tempReseed()
+				tempRandMu.Unlock()
+			}
+			continue
+		}
+		if os.IsNotExist(err) {
+			if _, err := os.Stat(dir); os.IsNotExist(err) {
+				return "", err
+			}
+		}
+		if err == nil {
+			name = try
+		}
+		break
+	}
+	return
+}